@@ -0,0 +1,369 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// DeadLetterExchange collects messages every consumer has given up on.
+	// Each consumer's dead letters are routed by its queue name to a queue
+	// named after it with a ".dead" suffix.
+	DeadLetterExchange = "dead_letters"
+
+	DefaultPrefetch    = 10
+	DefaultConcurrency = 1
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = 5 * time.Second
+
+	attemptHeader            = "x-attempt"
+	originalRoutingKeyHeader = "x-original-routing-key"
+	lastErrorHeader          = "x-last-error"
+
+	// settleTimeout bounds how long a retry or dead letter may take to be
+	// confirmed, including while the consumer is draining.
+	settleTimeout = 10 * time.Second
+)
+
+// Message is a delivery as a handler sees it. RoutingKey is the key the event
+// was originally published with, even when it comes back from a retry queue,
+// and Attempt counts from one.
+type Message struct {
+	ID         string
+	RoutingKey string
+	Body       []byte
+	Attempt    int
+}
+
+// HandlerFunc processes one message. Returning nil acknowledges it, returning
+// an error schedules a retry, and returning a Permanent error sends it straight
+// to the dead-letter exchange.
+type HandlerFunc func(ctx context.Context, msg Message) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a payload that
+// will never decode.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type ConsumerConfig struct {
+	// Exchange is the topic exchange the handled routing keys are bound on.
+	Exchange string
+	Queue    string
+
+	// Prefetch caps the unacknowledged messages held at once, and Concurrency
+	// is how many are handled in parallel. Messages are handled in order only
+	// with a concurrency of one.
+	Prefetch    int
+	Concurrency int
+
+	// MaxAttempts counts the first delivery. Retry n waits RetryDelay doubled
+	// n-1 times in a queue of its own before going back to Queue.
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+// Consumer binds a queue to the routing keys it has handlers for and runs
+// those handlers with retries. A failed message is acknowledged and parked in
+// a retry queue whose TTL returns it to the main queue after a delay, so it
+// never blocks the messages behind it. Once MaxAttempts is reached, or the
+// handler reports a permanent error, it is published to DeadLetterExchange.
+type Consumer struct {
+	client   *RabbitMQClient
+	config   ConsumerConfig
+	handlers map[string]HandlerFunc
+}
+
+func NewConsumer(client *RabbitMQClient, config ConsumerConfig) *Consumer {
+	if config.Concurrency < 1 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.Prefetch < 1 {
+		config.Prefetch = max(DefaultPrefetch, config.Concurrency)
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+
+	return &Consumer{client: client, config: config, handlers: make(map[string]HandlerFunc)}
+}
+
+// HandleFunc registers the handler for an exact routing key. Handlers must be
+// registered before Run.
+func (c *Consumer) HandleFunc(routingKey string, handler HandlerFunc) {
+	c.handlers[routingKey] = handler
+}
+
+// Handle registers a handler that receives the event in the message decoded
+// as T, upgraded to T's version if it was written at an older one. Events
+// the handler publishes carry the correlation ID of the one it received. A
+// message that does not decode is dead-lettered without retrying.
+func Handle[T events.Event](c *Consumer, routingKey string, handler func(ctx context.Context, msg Message, payload T) error) {
+	c.HandleFunc(routingKey, func(ctx context.Context, msg Message) error {
+		envelope, payload, err := events.Decode[T](msg.Body)
+		if err != nil {
+			return Permanent(err)
+		}
+		return handler(events.WithCorrelationID(ctx, envelope.CorrelationID), msg, payload)
+	})
+}
+
+// Run declares the consumer's queues and consumes until ctx is cancelled or
+// the client is closed, resubscribing whenever the connection is lost. On
+// cancellation it stops taking new messages and returns once the handlers in
+// flight have finished; they run on a context that is not cancelled with ctx,
+// so their work is not cut off halfway.
+func (c *Consumer) Run(ctx context.Context) error {
+	if err := c.declareTopology(); err != nil {
+		return err
+	}
+
+	channel, deliveries, err := c.client.subscribe(c.config.Queue, c.config.Prefetch)
+	if err != nil {
+		return err
+	}
+
+	for {
+		lost := c.drain(ctx, deliveries)
+		_ = channel.Close()
+		if !lost {
+			return nil
+		}
+
+		for attempt := 0; ; attempt++ {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-c.client.done:
+				return nil
+			case <-time.After(reconnectDelay(attempt)):
+			}
+
+			channel, deliveries, err = c.client.subscribe(c.config.Queue, c.config.Prefetch)
+			if err == nil {
+				logger.Info("pkg: resumed consuming RabbitMQ queue", zap.String("queue", c.config.Queue))
+				break
+			}
+			if !errors.Is(err, ErrNotConnected) {
+				logger.Error("pkg: failed to resume consuming RabbitMQ queue", zap.String("queue", c.config.Queue), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (c *Consumer) declareTopology() error {
+	queue := c.config.Queue
+
+	if err := c.client.DeclareExchange(c.config.Exchange, "topic"); err != nil {
+		return err
+	}
+	if _, err := c.client.DeclareQueue(queue); err != nil {
+		return err
+	}
+	for routingKey := range c.handlers {
+		if err := c.client.BindQueue(queue, c.config.Exchange, routingKey); err != nil {
+			return err
+		}
+	}
+
+	if err := c.client.DeclareExchange(DeadLetterExchange, "direct"); err != nil {
+		return err
+	}
+	if _, err := c.client.DeclareQueue(deadLetterQueue(queue)); err != nil {
+		return err
+	}
+	if err := c.client.BindQueue(deadLetterQueue(queue), DeadLetterExchange, queue); err != nil {
+		return err
+	}
+
+	// Retry queues have no consumers. When a message's TTL runs out, the
+	// broker dead-letters it through the default exchange back to the queue.
+	for retry := 1; retry < c.config.MaxAttempts; retry++ {
+		_, err := c.client.declareQueue(queueDeclaration{
+			name: retryQueue(queue, retry),
+			args: amqp.Table{
+				"x-message-ttl":             retryDelay(c.config.RetryDelay, retry).Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drain hands deliveries to the workers until ctx is cancelled or the
+// subscription is lost, then waits for the workers to finish. It reports
+// whether the subscription was lost.
+func (c *Consumer) drain(ctx context.Context, deliveries <-chan amqp.Delivery) bool {
+	handlerCtx := context.WithoutCancel(ctx)
+
+	var lost bool
+	var once sync.Once
+	var wg sync.WaitGroup
+	for range c.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery, ok := <-deliveries:
+					if !ok {
+						once.Do(func() { lost = true })
+						return
+					}
+					c.handle(handlerCtx, delivery)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return lost && ctx.Err() == nil
+}
+
+func (c *Consumer) handle(ctx context.Context, delivery amqp.Delivery) {
+	msg := Message{
+		ID:         delivery.MessageId,
+		RoutingKey: delivery.RoutingKey,
+		Body:       delivery.Body,
+		Attempt:    1,
+	}
+	if routingKey, ok := delivery.Headers[originalRoutingKeyHeader].(string); ok {
+		msg.RoutingKey = routingKey
+	}
+	if attempt := headerInt(delivery.Headers[attemptHeader]); attempt > 0 {
+		msg.Attempt = attempt
+	}
+
+	handler, ok := c.handlers[msg.RoutingKey]
+	var err error
+	if ok {
+		err = handler(ctx, msg)
+	} else {
+		err = Permanent(fmt.Errorf("pkg: no handler for routing key %s", msg.RoutingKey))
+	}
+	if err == nil {
+		_ = delivery.Ack(false)
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("queue", c.config.Queue),
+		zap.String("routing_key", msg.RoutingKey),
+		zap.String("message_id", msg.ID),
+		zap.Int("attempt", msg.Attempt),
+		zap.Error(err),
+	}
+
+	if IsPermanent(err) || msg.Attempt >= c.config.MaxAttempts {
+		logger.Error("pkg: giving up on message, sending it to the dead-letter exchange", fields...)
+		c.settle(ctx, delivery, msg, DeadLetterExchange, c.config.Queue, err)
+		return
+	}
+
+	logger.Error("pkg: message handler failed, scheduling a retry", fields...)
+	c.settle(ctx, delivery, msg, "", retryQueue(c.config.Queue, msg.Attempt), err)
+}
+
+// settle moves a failed message to a retry queue or the dead-letter exchange
+// and only then acknowledges the original. If the move is not confirmed, the
+// original is requeued so nothing is lost.
+func (c *Consumer) settle(ctx context.Context, delivery amqp.Delivery, msg Message, exchange, routingKey string, cause error) {
+	headers := amqp.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[attemptHeader] = int32(msg.Attempt + 1)
+	headers[originalRoutingKeyHeader] = msg.RoutingKey
+	headers[lastErrorHeader] = cause.Error()
+
+	publishCtx, cancel := context.WithTimeout(ctx, settleTimeout)
+	defer cancel()
+
+	err := c.client.publish(publishCtx, exchange, routingKey, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  delivery.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    delivery.MessageId,
+		Timestamp:    delivery.Timestamp,
+		Body:         delivery.Body,
+	})
+	if err != nil {
+		logger.Error("pkg: failed to move message, requeueing it", zap.String("queue", c.config.Queue), zap.String("message_id", msg.ID), zap.Error(err))
+		_ = delivery.Nack(false, true)
+		return
+	}
+	_ = delivery.Ack(false)
+}
+
+// retryDelay is how long retry n, counting from one, waits: base doubled n-1
+// times.
+func retryDelay(base time.Duration, retry int) time.Duration {
+	return base << (retry - 1)
+}
+
+func retryQueue(queue string, retry int) string {
+	return queue + ".retry." + strconv.Itoa(retry)
+}
+
+func deadLetterQueue(queue string) string {
+	return queue + ".dead"
+}
+
+// headerInt reads an integer header, which the broker may hand back as any of
+// the AMQP integer types.
+func headerInt(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case uint8:
+		return int(v)
+	case uint16:
+		return int(v)
+	case uint32:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce/pkg/events"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(5*time.Second, tt.retry); got != tt.want {
+			t.Errorf("retryDelay(5s, %d) = %s, want %s", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestHandleDecodesEvent(t *testing.T) {
+	consumer := NewConsumer(nil, ConsumerConfig{Exchange: events.OrderEventsExchange, Queue: "test_queue"})
+
+	var got events.OrderCancelled
+	var correlationID string
+	Handle(consumer, events.OrderCancelledKey, func(ctx context.Context, msg Message, payload events.OrderCancelled) error {
+		got = payload
+		correlationID = events.CorrelationID(ctx)
+		return nil
+	})
+	handler := consumer.handlers[events.OrderCancelledKey]
+
+	envelope, err := events.New(events.WithCorrelationID(context.Background(), "corr-1"), "order", events.OrderCancelledKey, events.OrderCancelled{OrderID: "ORD1"})
+	if err != nil {
+		t.Fatalf("events.New returned %v", err)
+	}
+	body, err := envelope.Encode()
+	if err != nil {
+		t.Fatalf("Encode returned %v", err)
+	}
+
+	if err = handler(context.Background(), Message{Body: body}); err != nil {
+		t.Fatalf("handler returned %v", err)
+	}
+	if got.OrderID != "ORD1" {
+		t.Errorf("decoded order id %q, want ORD1", got.OrderID)
+	}
+	if correlationID != "corr-1" {
+		t.Errorf("handler context carries correlation id %q, want corr-1", correlationID)
+	}
+
+	err = handler(context.Background(), Message{Body: []byte(`not json`)})
+	if !IsPermanent(err) {
+		t.Errorf("undecodable body returned %v, want a permanent error", err)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("boom")
+
+	if IsPermanent(cause) {
+		t.Error("plain error reported as permanent")
+	}
+	if err := Permanent(cause); !IsPermanent(err) || !errors.Is(err, cause) {
+		t.Errorf("Permanent(%v) = %v, want a permanent error wrapping the cause", cause, err)
+	}
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) is not nil")
+	}
+}
+
+func TestHeaderInt(t *testing.T) {
+	for _, value := range []any{int32(3), int64(3), int16(3), uint8(3), 3} {
+		if got := headerInt(value); got != 3 {
+			t.Errorf("headerInt(%T) = %d, want 3", value, got)
+		}
+	}
+	if got := headerInt("3"); got != 0 {
+		t.Errorf("headerInt(string) = %d, want 0", got)
+	}
+}
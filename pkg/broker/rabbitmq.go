@@ -2,36 +2,287 @@ package broker
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"ecommerce/pkg/logger"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
 )
 
+const (
+	// publisherPoolSize caps how many idle publisher channels are kept open.
+	// Publishers beyond that open a channel of their own and close it after.
+	publisherPoolSize = 8
+
+	baseReconnectDelay = time.Second
+	maxReconnectDelay  = 30 * time.Second
+)
+
+// ErrNotConnected is returned while the client is between connections.
+var ErrNotConnected = errors.New("pkg: RabbitMQ connection is not available")
+
+type exchangeDeclaration struct {
+	name string
+	kind string
+}
+
+type queueDeclaration struct {
+	name      string
+	temporary bool
+	args      amqp.Table
+}
+
+type bindingDeclaration struct {
+	queue      string
+	exchange   string
+	routingKey string
+}
+
+// RabbitMQClient keeps one connection to RabbitMQ alive for the life of the
+// process. When the connection drops it redials with backoff and declares
+// every exchange, queue and binding it was asked for again, so publishers and
+// consumers carry on once the broker is back. Publishing goes through a pool
+// of confirm-mode channels, so concurrent publishers never share one.
 type RabbitMQClient struct {
+	url string
+
+	mu         sync.RWMutex
 	connection *amqp.Connection
-	channel    *amqp.Channel
+	pool       chan *amqp.Channel
+
+	topologyMu sync.Mutex
+	exchanges  []exchangeDeclaration
+	queues     []queueDeclaration
+	bindings   []bindingDeclaration
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 func NewRabbitMQClient(url string) (*RabbitMQClient, error) {
-	connection, err := amqp.Dial(url)
+	r := &RabbitMQClient{
+		url:  url,
+		pool: make(chan *amqp.Channel, publisherPoolSize),
+		done: make(chan struct{}),
+	}
+
+	closed, err := r.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	go r.watch(closed)
+	return r, nil
+}
+
+// connect dials the broker and replays the recorded topology before making the
+// new connection visible to publishers and consumers.
+func (r *RabbitMQClient) connect() (<-chan *amqp.Error, error) {
+	connection, err := amqp.Dial(r.url)
 	if err != nil {
 		return nil, fmt.Errorf("pkg: failed to connect to RabbitMQ server: %w", err)
 	}
+	closed := connection.NotifyClose(make(chan *amqp.Error, 1))
+
+	if err = r.redeclare(connection); err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.connection = connection
+	r.mu.Unlock()
+	return closed, nil
+}
+
+// watch waits for the connection to drop and redials until it is back or the
+// client is closed.
+func (r *RabbitMQClient) watch(closed <-chan *amqp.Error) {
+	for {
+		select {
+		case <-r.done:
+			return
+		case amqpErr := <-closed:
+			select {
+			case <-r.done:
+				return
+			default:
+			}
+			logger.Error("pkg: lost connection to RabbitMQ, reconnecting", zap.Error(amqpErr))
+		}
+
+		for attempt := 0; ; attempt++ {
+			select {
+			case <-r.done:
+				return
+			case <-time.After(reconnectDelay(attempt)):
+			}
+
+			var err error
+			closed, err = r.connect()
+			if err == nil {
+				logger.Info("pkg: reconnected to RabbitMQ", zap.Int("attempts", attempt+1))
+				break
+			}
+			logger.Error("pkg: failed to reconnect to RabbitMQ", zap.Int("attempt", attempt+1), zap.Error(err))
+		}
+	}
+}
+
+// reconnectDelay is how long to wait before the given reconnect attempt,
+// counting from zero: one second, doubling up to 30 seconds.
+func reconnectDelay(attempt int) time.Duration {
+	delay := baseReconnectDelay
+	for i := 0; i < attempt && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxReconnectDelay)
+}
+
+func (r *RabbitMQClient) currentConnection() (*amqp.Connection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.connection == nil || r.connection.IsClosed() {
+		return nil, ErrNotConnected
+	}
+	return r.connection, nil
+}
+
+// redeclare declares the recorded topology on a fresh connection.
+func (r *RabbitMQClient) redeclare(connection *amqp.Connection) error {
+	r.topologyMu.Lock()
+	defer r.topologyMu.Unlock()
+
+	channel, err := connection.Channel()
+	if err != nil {
+		return fmt.Errorf("pkg: failed to open RabbitMQ channel: %w", err)
+	}
+	defer channel.Close()
+
+	for _, exchange := range r.exchanges {
+		if err = declareExchange(channel, exchange.name, exchange.kind); err != nil {
+			return err
+		}
+	}
+	for _, queue := range r.queues {
+		if _, err = declareQueue(channel, queue); err != nil {
+			return err
+		}
+	}
+	for _, binding := range r.bindings {
+		if err = bindQueue(channel, binding.queue, binding.exchange, binding.routingKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declare runs a topology change on a short-lived channel, since a failed
+// declaration closes the channel it was made on.
+func (r *RabbitMQClient) declare(fn func(channel *amqp.Channel) error) error {
+	connection, err := r.currentConnection()
+	if err != nil {
+		return err
+	}
 
 	channel, err := connection.Channel()
 	if err != nil {
-		return nil, fmt.Errorf("pkg: failed to open RabbitMQ channel: %w", err)
+		return fmt.Errorf("pkg: failed to open RabbitMQ channel: %w", err)
 	}
+	defer channel.Close()
 
-	return &RabbitMQClient{
-		connection: connection,
-		channel:    channel,
-	}, nil
+	return fn(channel)
 }
 
 func (r *RabbitMQClient) DeclareExchange(name, kind string) error {
-	err := r.channel.ExchangeDeclare(
+	r.topologyMu.Lock()
+	defer r.topologyMu.Unlock()
+
+	err := r.declare(func(channel *amqp.Channel) error {
+		return declareExchange(channel, name, kind)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, exchange := range r.exchanges {
+		if exchange.name == name {
+			return nil
+		}
+	}
+	r.exchanges = append(r.exchanges, exchangeDeclaration{name: name, kind: kind})
+	return nil
+}
+
+func (r *RabbitMQClient) DeclareQueue(name string) (*amqp.Queue, error) {
+	return r.declareQueue(queueDeclaration{name: name})
+}
+
+// DeclareTemporaryQueue declares a queue that only lives as long as this
+// client's connection, for consumers that want their own copy of every
+// message, such as one per instance. The broker-side name starts with prefix
+// and stays the same across reconnects, so its bindings can be replayed.
+func (r *RabbitMQClient) DeclareTemporaryQueue(prefix string) (*amqp.Queue, error) {
+	suffix, err := newMessageID()
+	if err != nil {
+		return nil, fmt.Errorf("pkg: failed to generate queue name: %w", err)
+	}
+	return r.declareQueue(queueDeclaration{name: prefix + "." + suffix[:12], temporary: true})
+}
+
+func (r *RabbitMQClient) declareQueue(declaration queueDeclaration) (*amqp.Queue, error) {
+	r.topologyMu.Lock()
+	defer r.topologyMu.Unlock()
+
+	var queue amqp.Queue
+	err := r.declare(func(channel *amqp.Channel) error {
+		var err error
+		queue, err = declareQueue(channel, declaration)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, declared := range r.queues {
+		if declared.name == declaration.name {
+			return &queue, nil
+		}
+	}
+	r.queues = append(r.queues, declaration)
+	return &queue, nil
+}
+
+func (r *RabbitMQClient) BindQueue(queueName, exchange, routingKey string) error {
+	r.topologyMu.Lock()
+	defer r.topologyMu.Unlock()
+
+	err := r.declare(func(channel *amqp.Channel) error {
+		return bindQueue(channel, queueName, exchange, routingKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	binding := bindingDeclaration{queue: queueName, exchange: exchange, routingKey: routingKey}
+	for _, declared := range r.bindings {
+		if declared == binding {
+			return nil
+		}
+	}
+	r.bindings = append(r.bindings, binding)
+	return nil
+}
+
+func declareExchange(channel *amqp.Channel, name, kind string) error {
+	err := channel.ExchangeDeclare(
 		name,
 		kind,
 		true,
@@ -45,21 +296,22 @@ func (r *RabbitMQClient) DeclareExchange(name, kind string) error {
 	return nil
 }
 
-func (r *RabbitMQClient) DeclareQueue(name string) (*amqp.Queue, error) {
-	queue, err := r.channel.QueueDeclare(name, true, false, false, false, nil)
+// declareQueue declares a durable queue, or a temporary one that is deleted
+// along with the connection that declared it.
+func declareQueue(channel *amqp.Channel, declaration queueDeclaration) (amqp.Queue, error) {
+	temporary := declaration.temporary
+	queue, err := channel.QueueDeclare(declaration.name, !temporary, temporary, temporary, false, declaration.args)
 	if err != nil {
-		return nil, fmt.Errorf("pkg: failed to declare RabbitMQ queue: %w", err)
+		return amqp.Queue{}, fmt.Errorf("pkg: failed to declare RabbitMQ queue: %w", err)
 	}
-
-	return &queue, nil
+	return queue, nil
 }
 
-func (r *RabbitMQClient) BindQueue(queueName, exchange, routingKey string) error {
-	err := r.channel.QueueBind(queueName, routingKey, exchange, false, nil)
+func bindQueue(channel *amqp.Channel, queueName, exchange, routingKey string) error {
+	err := channel.QueueBind(queueName, routingKey, exchange, false, nil)
 	if err != nil {
 		return fmt.Errorf("pkg: failed to bind queue to exchange: %w", err)
 	}
-
 	return nil
 }
 
@@ -69,33 +321,220 @@ func (r *RabbitMQClient) Publish(ctx context.Context, exchange, routingKey strin
 		return fmt.Errorf("pkg: failed to marshal payload: %w", err)
 	}
 
-	err = r.channel.PublishWithContext(ctx, exchange, routingKey, false, false, amqp.Publishing{
+	messageID, err := newMessageID()
+	if err != nil {
+		return fmt.Errorf("pkg: failed to generate message id: %w", err)
+	}
+
+	return r.PublishRaw(ctx, exchange, routingKey, messageID, body)
+}
+
+// PublishRaw sends a JSON body that is already encoded under a message ID the
+// caller chose, so an outbox event keeps the same ID every time it is retried.
+// It returns once the broker has confirmed the message. While the client is
+// reconnecting it fails fast with ErrNotConnected rather than waiting.
+func (r *RabbitMQClient) PublishRaw(ctx context.Context, exchange, routingKey, messageID string, body []byte) error {
+	return r.publish(ctx, exchange, routingKey, amqp.Publishing{
 		ContentType:  "application/json",
 		DeliveryMode: amqp.Persistent,
+		MessageId:    messageID,
 		Body:         body,
 	})
+}
+
+func (r *RabbitMQClient) publish(ctx context.Context, exchange, routingKey string, publishing amqp.Publishing) error {
+	messageID := publishing.MessageId
+
+	channel, err := r.acquire()
 	if err != nil {
+		return err
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, publishing)
+	if err != nil {
+		r.release(channel)
 		return fmt.Errorf("pkg: failed to publish to RabbitMQ exchange: %w", err)
 	}
 
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// The confirmation may still arrive on this channel and would be
+		// mistaken for the next publisher's, so the channel is not reused.
+		_ = channel.Close()
+		return fmt.Errorf("pkg: gave up waiting for RabbitMQ to confirm %s: %w", messageID, err)
+	}
+	r.release(channel)
+
+	if !acked {
+		return fmt.Errorf("pkg: RabbitMQ rejected message %s", messageID)
+	}
 	return nil
 }
 
+// acquire hands out an idle publisher channel, or opens a new one in confirm
+// mode. Channels left over from a dropped connection are thrown away.
+func (r *RabbitMQClient) acquire() (*amqp.Channel, error) {
+	for {
+		select {
+		case channel := <-r.pool:
+			if !channel.IsClosed() {
+				return channel, nil
+			}
+			continue
+		default:
+		}
+
+		connection, err := r.currentConnection()
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := connection.Channel()
+		if err != nil {
+			return nil, fmt.Errorf("pkg: failed to open RabbitMQ channel: %w", err)
+		}
+
+		// Publisher confirms let PublishRaw report success only once the
+		// broker has taken responsibility for the message.
+		if err = channel.Confirm(false); err != nil {
+			_ = channel.Close()
+			return nil, fmt.Errorf("pkg: failed to enable publisher confirms: %w", err)
+		}
+		return channel, nil
+	}
+}
+
+func (r *RabbitMQClient) release(channel *amqp.Channel) {
+	if channel.IsClosed() {
+		return
+	}
+	select {
+	case r.pool <- channel:
+	default:
+		_ = channel.Close()
+	}
+}
+
+// Consume delivers messages from the queue until ctx is cancelled or the
+// client is closed, and only then closes the returned channel. If the
+// connection drops, it subscribes again once the client has reconnected;
+// messages that were in flight are redelivered by the broker, so handlers must
+// tolerate duplicates. Acknowledging a message from the old connection fails
+// harmlessly.
 func (r *RabbitMQClient) Consume(ctx context.Context, queueName string) (<-chan amqp.Delivery, error) {
-	result, err := r.channel.Consume(queueName, "", false, false, false, false, nil)
+	channel, deliveries, err := r.subscribe(queueName, 0)
 	if err != nil {
-		return nil, fmt.Errorf("pkg: failed to consume RabbitMQ queue: %w", err)
+		return nil, err
 	}
 
-	return result, nil
+	out := make(chan amqp.Delivery)
+	go func() {
+		defer close(out)
+
+		for {
+			if !r.forward(ctx, deliveries, out) {
+				_ = channel.Close()
+				return
+			}
+
+			for attempt := 0; ; attempt++ {
+				select {
+				case <-ctx.Done():
+					return
+				case <-r.done:
+					return
+				case <-time.After(reconnectDelay(attempt)):
+				}
+
+				channel, deliveries, err = r.subscribe(queueName, 0)
+				if err == nil {
+					logger.Info("pkg: resumed consuming RabbitMQ queue", zap.String("queue", queueName))
+					break
+				}
+				if !errors.Is(err, ErrNotConnected) {
+					logger.Error("pkg: failed to resume consuming RabbitMQ queue", zap.String("queue", queueName), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return out, nil
 }
 
-func (r *RabbitMQClient) Close() error {
-	if r.channel != nil {
-		_ = r.channel.Close()
+// subscribe starts consuming the queue on a channel of its own. A prefetch
+// above zero limits how many unacknowledged messages the broker sends it.
+func (r *RabbitMQClient) subscribe(queueName string, prefetch int) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	connection, err := r.currentConnection()
+	if err != nil {
+		return nil, nil, err
 	}
-	if r.connection != nil {
-		return r.connection.Close()
+
+	channel, err := connection.Channel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("pkg: failed to open RabbitMQ channel: %w", err)
 	}
-	return nil
+
+	if prefetch > 0 {
+		if err = channel.Qos(prefetch, 0, false); err != nil {
+			_ = channel.Close()
+			return nil, nil, fmt.Errorf("pkg: failed to set RabbitMQ prefetch: %w", err)
+		}
+	}
+
+	deliveries, err := channel.Consume(queueName, "", false, false, false, false, nil)
+	if err != nil {
+		_ = channel.Close()
+		return nil, nil, fmt.Errorf("pkg: failed to consume RabbitMQ queue: %w", err)
+	}
+	return channel, deliveries, nil
+}
+
+// forward copies deliveries to out. It reports false when the consumer should
+// stop for good, and true when the subscription was lost and should be
+// renewed.
+func (r *RabbitMQClient) forward(ctx context.Context, deliveries <-chan amqp.Delivery, out chan<- amqp.Delivery) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-r.done:
+			return false
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return true
+			}
+			select {
+			case out <- delivery:
+			case <-ctx.Done():
+				return false
+			case <-r.done:
+				return false
+			}
+		}
+	}
+}
+
+// newMessageID gives every published message an ID consumers can deduplicate on.
+func newMessageID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Close stops reconnecting and closes the connection.
+func (r *RabbitMQClient) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+
+		// Closing the connection closes the pooled channels with it.
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.connection != nil && !r.connection.IsClosed() {
+			err = r.connection.Close()
+		}
+	})
+	return err
 }
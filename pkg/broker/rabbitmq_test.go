@@ -0,0 +1,25 @@
+package broker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{50, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := reconnectDelay(tt.attempt); got != tt.want {
+			t.Errorf("reconnectDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
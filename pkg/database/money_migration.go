@@ -0,0 +1,36 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MigrateDecimalMoney moves a legacy decimal column (major units, e.g. 1499.50)
+// into the integer minor-unit and currency columns of an embedded money.Money,
+// then drops it. Run it after AutoMigrate has created the new columns; it is a
+// no-op once the legacy column is gone.
+//
+// scale is 10^minor digits of currency, 100 for INR.
+func MigrateDecimalMoney(db *gorm.DB, table string, legacyColumn string, amountColumn string, currencyColumn string, currency string, scale int64) error {
+	if !db.Migrator().HasColumn(table, legacyColumn) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(
+			fmt.Sprintf("UPDATE %q SET %q = ROUND(%q * ?)::bigint, %q = ? WHERE %q IS NOT NULL",
+				table, amountColumn, legacyColumn, currencyColumn, legacyColumn),
+			scale, currency,
+		).Error
+		if err != nil {
+			return fmt.Errorf("database (postgres): failed to backfill %s.%s: %w", table, amountColumn, err)
+		}
+
+		err = tx.Migrator().DropColumn(table, legacyColumn)
+		if err != nil {
+			return fmt.Errorf("database (postgres): failed to drop %s.%s: %w", table, legacyColumn, err)
+		}
+		return nil
+	})
+}
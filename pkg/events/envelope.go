@@ -0,0 +1,154 @@
+// Package events holds the contracts services exchange over RabbitMQ: the
+// exchanges and routing keys, a typed struct for every payload, and the
+// envelope each payload travels in.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrUnsupportedVersion means the event was written by a newer producer
+	// than this consumer understands.
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+	ErrMalformedEvent     = errors.New("malformed event")
+)
+
+// Event is implemented by every payload struct. EventVersion is the version
+// the struct describes; it goes up whenever a field changes meaning or is
+// removed, and adding a field does not need a new version.
+type Event interface {
+	EventVersion() int
+}
+
+// Upgrader is implemented by payloads whose shape changed between versions.
+// Upgrade rewrites data written at version from into the shape of version
+// from+1. Decode calls it once per step, starting at the version the event
+// was written with.
+type Upgrader interface {
+	Upgrade(from int, data json.RawMessage) (json.RawMessage, error)
+}
+
+// Envelope is what goes on the wire. Type is the routing key the event is
+// published under and ID doubles as the AMQP message ID, which consumers
+// deduplicate on. CorrelationID is shared by every event that follows from
+// the same original one.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id"`
+	Producer      string          `json:"producer"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// New wraps data for publishing under eventType. The correlation ID comes
+// from ctx; an event with none starts a new chain under its own ID.
+func New(ctx context.Context, producer string, eventType string, data Event) (Envelope, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("pkg: failed to marshal %s event: %w", eventType, err)
+	}
+
+	id, err := NewID()
+	if err != nil {
+		return Envelope{}, fmt.Errorf("pkg: failed to generate event id: %w", err)
+	}
+
+	correlationID := CorrelationID(ctx)
+	if correlationID == "" {
+		correlationID = id
+	}
+
+	return Envelope{
+		ID:            id,
+		Type:          eventType,
+		Version:       data.EventVersion(),
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Producer:      producer,
+		Data:          body,
+	}, nil
+}
+
+// Encode returns the envelope as JSON.
+func (e Envelope) Encode() ([]byte, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("pkg: failed to encode %s event: %w", e.Type, err)
+	}
+	return body, nil
+}
+
+// Decode reads an envelope and its payload as T, upgrading payloads written
+// at an older version first. A body without an envelope is treated as
+// version 0: a bare payload from before envelopes, which has the same shape as
+// version 1 unless T's Upgrader says otherwise.
+func Decode[T Event](body []byte) (Envelope, T, error) {
+	var payload T
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Type == "" {
+		envelope = Envelope{Data: body}
+	}
+
+	current := payload.EventVersion()
+	if envelope.Version > current {
+		return envelope, payload, fmt.Errorf("pkg: %w: %s version %d, newest known is %d", ErrUnsupportedVersion, envelope.Type, envelope.Version, current)
+	}
+
+	data := envelope.Data
+	upgrader, canUpgrade := any(payload).(Upgrader)
+	for version := envelope.Version; version < current; version++ {
+		if !canUpgrade {
+			if version == 0 {
+				continue
+			}
+			return envelope, payload, fmt.Errorf("pkg: %w: no upgrade for %s from version %d", ErrUnsupportedVersion, envelope.Type, version)
+		}
+
+		var err error
+		data, err = upgrader.Upgrade(version, data)
+		if err != nil {
+			return envelope, payload, fmt.Errorf("pkg: %w: failed to upgrade %s from version %d: %v", ErrMalformedEvent, envelope.Type, version, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return envelope, payload, fmt.Errorf("pkg: %w: failed to decode %s payload: %v", ErrMalformedEvent, envelope.Type, err)
+	}
+	return envelope, payload, nil
+}
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context whose events carry id, so that events
+// published while handling another event can be traced back to it.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// NewID returns a random version 4 UUID.
+func NewID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+// renamedEvent moved its name field in version 2, to exercise Upgrade.
+type renamedEvent struct {
+	FullName string `json:"full_name"`
+}
+
+func (renamedEvent) EventVersion() int { return 2 }
+
+func (renamedEvent) Upgrade(from int, data json.RawMessage) (json.RawMessage, error) {
+	if from != 1 {
+		return data, nil
+	}
+
+	var v1 struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return nil, err
+	}
+	return json.Marshal(renamedEvent{FullName: v1.Name})
+}
+
+func TestNewAndDecode(t *testing.T) {
+	ctx := WithCorrelationID(context.Background(), "corr-1")
+
+	envelope, err := New(ctx, "payment", OrderPaidKey, PaymentStatusChanged{OrderID: "ORD1", UserID: "user-1", Status: "paid"})
+	if err != nil {
+		t.Fatalf("New returned %v", err)
+	}
+	body, err := envelope.Encode()
+	if err != nil {
+		t.Fatalf("Encode returned %v", err)
+	}
+
+	decoded, payload, err := Decode[PaymentStatusChanged](body)
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if decoded.ID != envelope.ID || decoded.Type != OrderPaidKey || decoded.Version != 1 || decoded.Producer != "payment" || decoded.CorrelationID != "corr-1" {
+		t.Errorf("decoded envelope %+v does not match %+v", decoded, envelope)
+	}
+	if payload.UserID != "user-1" || payload.Status != "paid" {
+		t.Errorf("decoded payload %+v", payload)
+	}
+}
+
+func TestNewStartsCorrelationChain(t *testing.T) {
+	envelope, err := New(context.Background(), "order", OrderCancelledKey, OrderCancelled{OrderID: "ORD1"})
+	if err != nil {
+		t.Fatalf("New returned %v", err)
+	}
+	if envelope.CorrelationID != envelope.ID {
+		t.Errorf("correlation id %q, want the event's own id %q", envelope.CorrelationID, envelope.ID)
+	}
+}
+
+func TestDecodeBarePayload(t *testing.T) {
+	envelope, payload, err := Decode[OrderCancelled]([]byte(`{"order_id":"ORD1","reason":"changed mind"}`))
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if envelope.Version != 0 || payload.OrderID != "ORD1" || payload.Reason != "changed mind" {
+		t.Errorf("got envelope %+v and payload %+v", envelope, payload)
+	}
+}
+
+func TestDecodeDoubleEncodedUserOnboarded(t *testing.T) {
+	_, payload, err := Decode[UserOnboarded]([]byte(`"{\"user_id\":\"user-1\",\"status\":\"onboarded\"}"`))
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if payload.UserID != "user-1" || payload.Status != "onboarded" {
+		t.Errorf("decoded payload %+v", payload)
+	}
+}
+
+func TestDecodeUpgradesOlderVersion(t *testing.T) {
+	body := []byte(`{"id":"1","type":"test.renamed","version":1,"data":{"name":"Asha"}}`)
+
+	_, payload, err := Decode[renamedEvent](body)
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if payload.FullName != "Asha" {
+		t.Errorf("upgraded payload %+v, want full name Asha", payload)
+	}
+}
+
+func TestDecodeRejectsNewerVersion(t *testing.T) {
+	body := []byte(`{"id":"1","type":"payment.OrderPaid","version":2,"data":{}}`)
+
+	_, _, err := Decode[PaymentStatusChanged](body)
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Decode returned %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	id, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID returned %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("NewID() = %q, not a version 4 UUID", id)
+	}
+}
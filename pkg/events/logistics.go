@@ -0,0 +1,44 @@
+package events
+
+import "time"
+
+const (
+	LogisticsEventsExchange = "logistics_events"
+	ShipmentCheckpointKey   = "shipment.checkpoint"
+)
+
+// ShipmentEventKey is the routing key for a shipment status change, for
+// example shipment.delivered.
+func ShipmentEventKey(status string) string {
+	return "shipment." + status
+}
+
+// ShipmentStatusChanged is published whenever a shipment changes status.
+// ReturnID is set on the reverse shipment that collects a return.
+type ShipmentStatusChanged struct {
+	ShipmentID    string    `json:"shipment_id"`
+	FulfillmentID string    `json:"fulfillment_id"`
+	ReturnID      string    `json:"return_id,omitempty"`
+	OrderID       string    `json:"order_id"`
+	UserID        string    `json:"user_id"`
+	Status        string    `json:"status"`
+	Note          string    `json:"note,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func (ShipmentStatusChanged) EventVersion() int { return 1 }
+
+// ShipmentCheckpoint is an agent's location report for a shipment on its way.
+type ShipmentCheckpoint struct {
+	ShipmentID    string    `json:"shipment_id"`
+	FulfillmentID string    `json:"fulfillment_id"`
+	OrderID       string    `json:"order_id"`
+	UserID        string    `json:"user_id"`
+	Status        string    `json:"status"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	Note          string    `json:"note,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func (ShipmentCheckpoint) EventVersion() int { return 1 }
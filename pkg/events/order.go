@@ -0,0 +1,106 @@
+package events
+
+import (
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+const (
+	OrderEventsExchange = "order_events"
+	OrderCancelledKey   = "order.cancelled"
+
+	FulfillmentReadyKey     = "fulfillment.ready_to_ship"
+	FulfillmentRejectedKey  = "fulfillment.rejected"
+	FulfillmentDeliveredKey = "fulfillment.delivered"
+
+	ReturnApprovedKey = "return.approved"
+	ReturnReceivedKey = "return.received"
+)
+
+// FulfillmentEventKey is the routing key for a fulfillment status change, for
+// example fulfillment.rejected.
+func FulfillmentEventKey(status string) string {
+	return "fulfillment." + status
+}
+
+// ReturnEventKey is the routing key for a return status change, for example
+// return.received.
+func ReturnEventKey(status string) string {
+	return "return." + status
+}
+
+type OrderCancelled struct {
+	OrderID        string    `json:"order_id"`
+	UserID         string    `json:"user_id"`
+	PreviousStatus string    `json:"previous_status"`
+	Reason         string    `json:"reason"`
+	CancelledAt    time.Time `json:"cancelled_at"`
+}
+
+func (OrderCancelled) EventVersion() int { return 1 }
+
+type Item struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// FulfillmentStatusChanged is published whenever a fulfillment changes
+// status. For a rejection, Amount is what the buyer should get back; the
+// shipping details tell logistics where to deliver once it is ready to ship.
+// On delivery, ReturnWindowClosesAt is when the seller's earnings, Amount less
+// the Commission snapshotted at checkout, can no longer be returned and become
+// due for payout.
+type FulfillmentStatusChanged struct {
+	FulfillmentID string      `json:"fulfillment_id"`
+	OrderID       string      `json:"order_id"`
+	SellerID      string      `json:"seller_id"`
+	UserID        string      `json:"user_id"`
+	CustomerEmail string      `json:"customer_email,omitempty"`
+	Status        string      `json:"status"`
+	OrderStatus   string      `json:"order_status"`
+	Reason        string      `json:"reason,omitempty"`
+	Amount        money.Money `json:"amount"`
+	// Commission is the cut snapshotted at checkout; older fulfillments are
+	// charged at the marketplace-wide rule when the order service starts. It is
+	// only nil on events published before that, which cannot be settled.
+	Commission *money.Money `json:"commission"`
+	Items      []Item       `json:"items"`
+	OccurredAt time.Time    `json:"occurred_at"`
+
+	ReturnWindowClosesAt *time.Time `json:"return_window_closes_at,omitempty"`
+
+	ShippingName    string `json:"shipping_name"`
+	ShippingPhone   string `json:"shipping_phone"`
+	ShippingAddress string `json:"shipping_address"`
+	ShippingCity    string `json:"shipping_city"`
+	ShippingState   string `json:"shipping_state"`
+	ShippingZip     string `json:"shipping_zip"`
+}
+
+func (FulfillmentStatusChanged) EventVersion() int { return 1 }
+
+// ReturnStatusChanged is published whenever a return changes status.
+// Logistics collects approved returns from the pickup address, and payment
+// refunds Amount once the return is received.
+type ReturnStatusChanged struct {
+	ReturnID      string      `json:"return_id"`
+	OrderID       string      `json:"order_id"`
+	FulfillmentID string      `json:"fulfillment_id"`
+	SellerID      string      `json:"seller_id"`
+	UserID        string      `json:"user_id"`
+	Status        string      `json:"status"`
+	Reason        string      `json:"reason,omitempty"`
+	Amount        money.Money `json:"amount"`
+	Items         []Item      `json:"items"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+
+	PickupName    string `json:"pickup_name"`
+	PickupPhone   string `json:"pickup_phone"`
+	PickupAddress string `json:"pickup_address"`
+	PickupCity    string `json:"pickup_city"`
+	PickupState   string `json:"pickup_state"`
+	PickupZip     string `json:"pickup_zip"`
+}
+
+func (ReturnStatusChanged) EventVersion() int { return 1 }
@@ -0,0 +1,23 @@
+package events
+
+const (
+	PaymentEventsExchange = "payment_events"
+
+	OrderPaidKey       = "payment.OrderPaid"
+	PaymentFailedKey   = "payment.PaymentFailed"
+	PaymentExpiredKey  = "payment.PaymentExpired"
+	PaymentRefundedKey = "payment.PaymentRefunded"
+	PaymentDisputedKey = "payment.PaymentDisputed"
+)
+
+// PaymentStatusChanged is published when an order's payment is captured,
+// fails, expires, is refunded in full or is disputed. Status is one of paid,
+// failed, expired, refunded or disputed.
+type PaymentStatusChanged struct {
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (PaymentStatusChanged) EventVersion() int { return 1 }
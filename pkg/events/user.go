@@ -0,0 +1,36 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	UserEventsExchange   = "user_events"
+	SellerOnboardedKey   = "seller.onboarded"
+	CustomerOnboardedKey = "customer.onboarded"
+)
+
+// UserOnboarded is published by catalog when a seller profile is created and
+// by order when a customer profile is, so that auth can mark the user as
+// onboarded.
+type UserOnboarded struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+}
+
+func (UserOnboarded) EventVersion() int { return 1 }
+
+// Upgrade handles customer.onboarded payloads from before envelopes, which
+// were encoded twice and arrive as a JSON string holding the object.
+func (UserOnboarded) Upgrade(from int, data json.RawMessage) (json.RawMessage, error) {
+	if from != 0 || len(data) == 0 || data[0] != '"' {
+		return data, nil
+	}
+
+	var inner string
+	if err := json.Unmarshal(data, &inner); err != nil {
+		return nil, fmt.Errorf("double-encoded payload: %w", err)
+	}
+	return json.RawMessage(inner), nil
+}
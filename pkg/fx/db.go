@@ -0,0 +1,96 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is one row of the fx_rates table: 1 Base = Rate Quote.
+type ExchangeRate struct {
+	Base      string    `gorm:"type:varchar(3);primaryKey"`
+	Quote     string    `gorm:"type:varchar(3);primaryKey"`
+	Rate      string    `gorm:"type:numeric(24,12);not null"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "fx_rates"
+}
+
+// DBSource serves rates from the fx_rates table, reloading it at most once per
+// refresh interval.
+type DBSource struct {
+	db      *gorm.DB
+	refresh time.Duration
+
+	mu       sync.Mutex
+	tables   []*Table
+	loadedAt time.Time
+}
+
+func NewDBSource(db *gorm.DB, refresh time.Duration) *DBSource {
+	return &DBSource{db: db, refresh: refresh}
+}
+
+func (s *DBSource) Rate(ctx context.Context, from string, to string) (Rate, error) {
+	tables, err := s.load(ctx)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	for _, table := range tables {
+		rate, innerErr := table.Rate(ctx, from, to)
+		if innerErr == nil {
+			return rate, nil
+		}
+		if !errors.Is(innerErr, ErrRateNotFound) {
+			return Rate{}, innerErr
+		}
+	}
+	return Rate{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, code(from), code(to))
+}
+
+func (s *DBSource) load(ctx context.Context) ([]*Table, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tables != nil && time.Since(s.loadedAt) < s.refresh {
+		return s.tables, nil
+	}
+
+	rows, err := gorm.G[ExchangeRate](s.db).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fx: failed to load exchange rates: %w", err)
+	}
+
+	byBase := make(map[string]map[string]string)
+	asOf := make(map[string]time.Time)
+	for _, row := range rows {
+		base := code(row.Base)
+		if byBase[base] == nil {
+			byBase[base] = make(map[string]string)
+		}
+		byBase[base][row.Quote] = row.Rate
+		if row.UpdatedAt.After(asOf[base]) {
+			asOf[base] = row.UpdatedAt
+		}
+	}
+
+	tables := make([]*Table, 0, len(byBase))
+	for base, rates := range byBase {
+		table, innerErr := NewTable(base, rates, asOf[base])
+		if innerErr != nil {
+			return nil, innerErr
+		}
+		tables = append(tables, table)
+	}
+
+	s.tables = tables
+	s.loadedAt = time.Now()
+	return tables, nil
+}
@@ -0,0 +1,130 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+var (
+	ErrRateNotFound = errors.New("fx: no exchange rate")
+	ErrInvalidRate  = errors.New("fx: invalid exchange rate")
+)
+
+// RateSource looks up how many units of to one unit of from buys. Sources are
+// expected to be cheap to call; wrap slow ones in a cache.
+type RateSource interface {
+	Rate(ctx context.Context, from string, to string) (Rate, error)
+}
+
+// Rate is an exact decimal exchange rate. It is kept as a rational number so
+// that converting with it, or with its inverse, never goes through floats.
+type Rate struct {
+	From  string
+	To    string
+	Value *big.Rat
+	AsOf  time.Time
+}
+
+// ParseRate reads a decimal rate such as "0.01198" (1 INR = 0.01198 USD).
+func ParseRate(from string, to string, value string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || r.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %s/%s %q", ErrInvalidRate, from, to, value)
+	}
+	return Rate{From: code(from), To: code(to), Value: r}, nil
+}
+
+// Identity is the rate between a currency and itself.
+func Identity(currency string) Rate {
+	return Rate{From: code(currency), To: code(currency), Value: big.NewRat(1, 1)}
+}
+
+func (r Rate) Inverse() Rate {
+	return Rate{From: r.To, To: r.From, Value: new(big.Rat).Inv(r.Value), AsOf: r.AsOf}
+}
+
+// Convert turns m, which must be in r.From, into r.To, rounding half away
+// from zero to the target's minor unit.
+func (r Rate) Convert(m money.Money) (money.Money, error) {
+	if code(m.Currency) != r.From {
+		return money.Money{}, fmt.Errorf("%w: rate is for %s, amount is in %s", money.ErrCurrencyMismatch, r.From, m.Currency)
+	}
+
+	// amount_to = amount_from / 10^digits(from) * rate * 10^digits(to)
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, r.Value)
+	value.Mul(value, pow10(money.MinorDigits(r.To)))
+	value.Quo(value, pow10(money.MinorDigits(r.From)))
+
+	amount, err := round(value)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.New(amount, r.To), nil
+}
+
+// String renders the rate for snapshots, with up to ten decimal places.
+func (r Rate) String() string {
+	if r.Value == nil {
+		return ""
+	}
+	s := r.Value.FloatString(10)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Convert is a convenience for converting m into currency with a rate from src.
+func Convert(ctx context.Context, src RateSource, m money.Money, currency string) (money.Money, Rate, error) {
+	if code(m.Currency) == code(currency) {
+		return m, Identity(currency), nil
+	}
+
+	rate, err := src.Rate(ctx, m.Currency, currency)
+	if err != nil {
+		return money.Money{}, Rate{}, err
+	}
+
+	converted, err := rate.Convert(m)
+	if err != nil {
+		return money.Money{}, Rate{}, err
+	}
+	return converted, rate, nil
+}
+
+func round(value *big.Rat) (int64, error) {
+	num := value.Num()
+	den := value.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, fmt.Errorf("%w: converted amount overflows", money.ErrInvalidAmount)
+	}
+	return quotient.Int64(), nil
+}
+
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+func code(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return currency
+}
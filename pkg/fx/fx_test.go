@@ -0,0 +1,82 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+func TestTableConvert(t *testing.T) {
+	table, err := NewTable("INR", map[string]string{"USD": "0.012", "EUR": "0.011", "JPY": "1.8"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		in   money.Money
+		to   string
+		want money.Money
+	}{
+		{"direct", money.New(149950, "INR"), "USD", money.New(1799, "USD")},
+		{"inverse", money.New(1200, "USD"), "INR", money.New(100000, "INR")},
+		{"cross", money.New(1200, "USD"), "EUR", money.New(1100, "EUR")},
+		{"zero decimal target", money.New(100000, "INR"), "JPY", money.New(1800, "JPY")},
+		{"identity", money.New(4200, "INR"), "inr", money.New(4200, "INR")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := Convert(ctx, table, tt.in, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, _, err = Convert(ctx, table, money.New(100, "INR"), "GBP"); !errors.Is(err, ErrRateNotFound) {
+		t.Fatalf("expected ErrRateNotFound, got %v", err)
+	}
+}
+
+func TestRateString(t *testing.T) {
+	rate, err := ParseRate("INR", "USD", "0.011980")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.String() != "0.01198" {
+		t.Fatalf("unexpected rate string %q", rate.String())
+	}
+	if _, err = ParseRate("INR", "USD", "-1"); !errors.Is(err, ErrInvalidRate) {
+		t.Fatalf("expected ErrInvalidRate, got %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"base": "inr", "rates": {"USD": "0.012", "EUR": 0.011}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate, err := table.Rate(context.Background(), "INR", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.String() != "0.011" {
+		t.Fatalf("unexpected rate %s", rate)
+	}
+}
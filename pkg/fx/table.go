@@ -0,0 +1,100 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+// Table holds rates quoted against a single base currency and derives the
+// inverse and cross rates from them.
+type Table struct {
+	base  string
+	rates map[string]*big.Rat
+	asOf  time.Time
+}
+
+// NewTable builds a table from decimal strings, e.g. base "INR" and
+// {"USD": "0.01198"} for 1 INR = 0.01198 USD.
+func NewTable(base string, rates map[string]string, asOf time.Time) (*Table, error) {
+	t := &Table{base: code(base), rates: make(map[string]*big.Rat, len(rates)), asOf: asOf}
+	for quote, value := range rates {
+		rate, err := ParseRate(base, quote, value)
+		if err != nil {
+			return nil, err
+		}
+		t.rates[code(quote)] = rate.Value
+	}
+	return t, nil
+}
+
+func (t *Table) Base() string {
+	return t.base
+}
+
+func (t *Table) Rate(_ context.Context, from string, to string) (Rate, error) {
+	from, to = code(from), code(to)
+	if from == to {
+		return Identity(from), nil
+	}
+
+	baseToFrom, err := t.fromBase(from)
+	if err != nil {
+		return Rate{}, err
+	}
+	baseToTo, err := t.fromBase(to)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	// from -> base -> to
+	value := new(big.Rat).Quo(baseToTo, baseToFrom)
+	return Rate{From: from, To: to, Value: value, AsOf: t.asOf}, nil
+}
+
+func (t *Table) fromBase(currency string) (*big.Rat, error) {
+	if currency == t.base {
+		return big.NewRat(1, 1), nil
+	}
+	rate, ok := t.rates[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, t.base, currency)
+	}
+	return rate, nil
+}
+
+type rateFile struct {
+	Base  string                 `json:"base"`
+	AsOf  time.Time              `json:"as_of"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// LoadFile reads a JSON rate table:
+//
+//	{"base": "INR", "as_of": "2026-10-01T00:00:00Z", "rates": {"USD": "0.01198", "EUR": "0.01102"}}
+//
+// Rates may be JSON strings or numbers; either way they are parsed as exact
+// decimals.
+func LoadFile(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fx: failed to read rate file: %w", err)
+	}
+
+	var file rateFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("fx: failed to parse rate file %s: %w", path, err)
+	}
+	if file.Base == "" {
+		return nil, fmt.Errorf("fx: rate file %s has no base currency", path)
+	}
+
+	rates := make(map[string]string, len(file.Rates))
+	for quote, value := range file.Rates {
+		rates[quote] = value.String()
+	}
+	return NewTable(file.Base, rates, file.AsOf)
+}
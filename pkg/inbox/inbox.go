@@ -0,0 +1,69 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessedEvent marks an incoming event (a Stripe event, an AMQP message) as
+// handled by one consumer. The same event may be processed once per consumer.
+type ProcessedEvent struct {
+	Consumer    string    `gorm:"type:varchar(100);primaryKey"`
+	EventID     string    `gorm:"type:varchar(255);primaryKey"`
+	ProcessedAt time.Time `gorm:"not null;index"`
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+type Inbox struct {
+	db       *gorm.DB
+	consumer string
+}
+
+func New(db *gorm.DB, consumer string) *Inbox {
+	return &Inbox{db: db, consumer: consumer}
+}
+
+// Process runs handle inside a transaction that also records eventID. It
+// reports false without calling handle when the event was already processed.
+// A concurrent delivery of the same event blocks on the first one's insert and
+// only proceeds if that transaction rolls back.
+//
+// Events without an ID cannot be deduplicated; they are handled every time.
+func (i *Inbox) Process(ctx context.Context, eventID string, handle func(tx *gorm.DB) error) (bool, error) {
+	processed := false
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if eventID != "" {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedEvent{
+				Consumer:    i.consumer,
+				EventID:     eventID,
+				ProcessedAt: time.Now(),
+			})
+			if result.Error != nil {
+				return fmt.Errorf("could not record event: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+		}
+
+		if err := handle(tx); err != nil {
+			return err
+		}
+
+		processed = true
+		return nil
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("pkg: failed to process event %s for %s: %w", eventID, i.consumer, err)
+	}
+	return processed, nil
+}
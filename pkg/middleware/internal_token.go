@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireInternalToken guards endpoints meant for other services and the
+// operations team, such as the outbox routes. Callers send the shared
+// INTERNAL_API_TOKEN in the X-Internal-Token header; with no token configured
+// every request is turned away.
+func RequireInternalToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("INTERNAL_API_TOKEN")
+		token := c.GetHeader("X-Internal-Token")
+
+		if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.Next()
+	}
+}
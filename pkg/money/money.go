@@ -0,0 +1,311 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "ecommerce/pkg/protobufs/common"
+)
+
+const DefaultCurrency = "INR"
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+)
+
+// Money is an amount in the currency's minor unit (paise, cents) and an
+// ISO 4217 currency code. Embed it in GORM models with a column prefix:
+//
+//	Price money.Money `gorm:"embedded;embeddedPrefix:price_"`
+//
+// which maps to price_amount_minor and price_currency.
+type Money struct {
+	Amount   int64  `gorm:"column:amount_minor;not null;default:0"`
+	Currency string `gorm:"column:currency;type:varchar(3);not null;default:'INR'"`
+}
+
+// minorUnits lists currencies whose minor unit is not 1/100.
+var minorUnits = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+var symbols = map[string]string{
+	"INR": "₹",
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: normalize(currency)}
+}
+
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// Parse reads a decimal amount in major units ("1499.50") without going
+// through floating point. More fractional digits than the currency has are
+// rejected rather than rounded.
+func Parse(value string, currency string) (Money, error) {
+	currency = normalize(currency)
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	digits := MinorDigits(currency)
+	if len(fraction) > digits {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, value, digits)
+	}
+	fraction += strings.Repeat("0", digits-len(fraction))
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || strings.ContainsAny(whole+fraction, "+-") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MustParse is Parse for constants in seeds and tests.
+func MustParse(value string, currency string) Money {
+	m, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func MinorDigits(currency string) int {
+	if digits, ok := minorUnits[normalize(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) SameCurrency(other Money) bool {
+	return normalize(m.Currency) == normalize(other.Currency)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: normalize(m.Currency)}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: normalize(m.Currency)}, nil
+}
+
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: normalize(m.Currency)}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: normalize(m.Currency)}
+}
+
+// Percent returns basisPoints/10000 of m, rounded half away from zero to the
+// nearest minor unit. 1800 basis points is 18%.
+func (m Money) Percent(basisPoints int64) Money {
+	return Money{Amount: divRound(m.Amount*basisPoints, 10000), Currency: normalize(m.Currency)}
+}
+
+// Cmp returns -1, 0 or 1. Amounts in different currencies are not comparable.
+func (m Money) Cmp(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum adds amounts that must all be in currency.
+func Sum(currency string, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		total, err = total.Add(amount)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal renders the amount in major units without grouping, e.g. "1499.50".
+func (m Money) Decimal() string {
+	digits := MinorDigits(m.Currency)
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if digits == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	raw := strconv.FormatInt(amount, 10)
+	if len(raw) <= digits {
+		raw = strings.Repeat("0", digits-len(raw)+1) + raw
+	}
+	return sign + raw[:len(raw)-digits] + "." + raw[len(raw)-digits:]
+}
+
+// String formats for display, e.g. "₹1,499.50" or "CHF 20.00". INR amounts use
+// Indian digit grouping (₹1,00,000.00).
+func (m Money) String() string {
+	decimal := m.Decimal()
+
+	sign := ""
+	if strings.HasPrefix(decimal, "-") {
+		sign = "-"
+		decimal = decimal[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(decimal, ".")
+	whole = group(whole, normalize(m.Currency) == "INR")
+	if hasFraction {
+		whole += "." + fraction
+	}
+
+	currency := normalize(m.Currency)
+	if symbol, ok := symbols[currency]; ok {
+		return sign + symbol + whole
+	}
+	return sign + currency + " " + whole
+}
+
+type jsonMoney struct {
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Formatted string `json:"formatted,omitempty"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.Amount, Currency: normalize(m.Currency), Formatted: m.String()})
+}
+
+// UnmarshalJSON also accepts a bare decimal number in major units ("price":
+// 1499.5), which is how carts and saga states were stored before amounts moved
+// to minor units. The literal is parsed as text, never as a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	literal := strings.TrimSpace(string(data))
+	if literal != "" && literal[0] != '{' && literal != "null" {
+		parsed, err := Parse(literal, DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var raw jsonMoney
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: expected {\"amount\": <minor units>, \"currency\": \"INR\"}", ErrInvalidAmount)
+	}
+	*m = New(raw.Amount, raw.Currency)
+	return nil
+}
+
+func (m Money) ToProto() *pb.Money {
+	return &pb.Money{AmountMinor: m.Amount, Currency: normalize(m.Currency)}
+}
+
+func FromProto(p *pb.Money) Money {
+	if p == nil {
+		return Zero(DefaultCurrency)
+	}
+	return New(p.AmountMinor, p.Currency)
+}
+
+func normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+func divRound(numerator int64, denominator int64) int64 {
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder*2 >= denominator {
+		if numerator < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return quotient
+}
+
+func group(digits string, indian bool) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	size := 3
+	if indian {
+		size = 2
+	}
+
+	var parts []string
+	for len(head) > size {
+		parts = append([]string{head[len(head)-size:]}, parts...)
+		head = head[:len(head)-size]
+	}
+	parts = append([]string{head}, parts...)
+
+	return strings.Join(parts, ",") + "," + tail
+}
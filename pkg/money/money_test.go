@@ -0,0 +1,145 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"1499.50", "inr", 149950, false},
+		{"1499.5", "INR", 149950, false},
+		{"1499", "INR", 149900, false},
+		{".99", "USD", 99, false},
+		{"-12.34", "USD", -1234, false},
+		{"500", "JPY", 500, false},
+		{"1.234", "KWD", 1234, false},
+		{"0.1", "JPY", 0, true},
+		{"1.005", "INR", 0, true},
+		{"12a", "INR", 0, true},
+		{"", "INR", 0, true},
+		{"--5", "INR", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.value, tt.currency)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Parse(%q): expected ErrInvalidAmount, got %v", tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", tt.value, err)
+			continue
+		}
+		if got.Amount != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.value, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := New(33333, "INR")
+
+	total := price.Mul(3)
+	if total.Amount != 99999 {
+		t.Fatalf("Mul: got %d", total.Amount)
+	}
+
+	sum, err := Sum("INR", price, price, price)
+	if err != nil || sum != total {
+		t.Fatalf("Sum: got %v, %v", sum, err)
+	}
+
+	if _, err = price.Add(New(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch, got %v", err)
+	}
+
+	diff, err := total.Sub(price)
+	if err != nil || diff.Amount != 66666 {
+		t.Fatalf("Sub: got %v, %v", diff, err)
+	}
+
+	if cmp, _ := price.Cmp(total); cmp != -1 {
+		t.Fatalf("Cmp: got %d", cmp)
+	}
+}
+
+func TestPercentRoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		amount int64
+		bps    int64
+		want   int64
+	}{
+		{10000, 1800, 1800},
+		{1, 5000, 1},
+		{3, 5000, 2},
+		{-3, 5000, -2},
+		{999, 1200, 120},
+		{12345, 250, 309},
+	}
+
+	for _, tt := range tests {
+		if got := New(tt.amount, "INR").Percent(tt.bps).Amount; got != tt.want {
+			t.Errorf("%d * %d bps = %d, want %d", tt.amount, tt.bps, got, tt.want)
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(149950, "INR"), "₹1,499.50"},
+		{New(10000000, "INR"), "₹1,00,000.00"},
+		{New(123456789, "USD"), "$1,234,567.89"},
+		{New(5, "USD"), "$0.05"},
+		{New(-250, "EUR"), "-€2.50"},
+		{New(1500, "JPY"), "¥1,500"},
+		{New(2000, "CHF"), "CHF 20.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+
+	if got := New(-5, "INR").Decimal(); got != "-0.05" {
+		t.Errorf("Decimal() = %q", got)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(New(149950, "inr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":149950,"currency":"INR","formatted":"₹1,499.50"}` {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	var decoded Money
+	if err = json.Unmarshal([]byte(`{"amount":2500,"currency":"usd"}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != New(2500, "USD") {
+		t.Fatalf("unexpected decoded value %+v", decoded)
+	}
+
+	if err = json.Unmarshal([]byte(`12.5`), &decoded); err != nil || decoded != New(1250, "INR") {
+		t.Fatalf("expected legacy decimal to decode as 1250 paise, got %+v, %v", decoded, err)
+	}
+
+	if err = json.Unmarshal([]byte(`12.345`), &decoded); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected sub-paisa decimal to be rejected, got %v", err)
+	}
+}
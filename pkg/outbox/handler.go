@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"ecommerce/pkg/logger"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RegisterRoutes adds the endpoints operators use to see events the relay
+// gave up on and send them again. The caller guards group, since the routes
+// do no authorisation of their own.
+func RegisterRoutes(group *gin.RouterGroup, store *Store) {
+	h := &handler{store: store}
+
+	group.GET("/outbox", h.listEvents)
+	group.POST("/outbox/:event_id/replay", h.replayEvent)
+}
+
+type handler struct {
+	store *Store
+}
+
+func (h *handler) listEvents(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	status := c.DefaultQuery("status", StatusFailed)
+	if status != StatusPending && status != StatusPublished && status != StatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status filter"})
+		return
+	}
+
+	events, err := h.store.List(c.Request.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		logger.Error("pkg: failed to list outbox events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list outbox events"})
+		return
+	}
+
+	if events == nil {
+		events = []OutboxEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (h *handler) replayEvent(c *gin.Context) {
+	event, err := h.store.Replay(c.Request.Context(), c.Param("event_id"))
+	switch {
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "outbox event not found"})
+		return
+	case errors.Is(err, ErrNotReplayable):
+		c.JSON(http.StatusConflict, gin.H{"error": "only failed events can be replayed"})
+		return
+	case err != nil:
+		logger.Error("pkg: failed to replay outbox event", zap.String("event_id", c.Param("event_id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to replay outbox event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event queued. The relay will publish it on its next round.",
+		"event":   event,
+	})
+}
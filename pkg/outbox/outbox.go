@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/events"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	// StatusFailed events ran out of attempts and wait for someone to replay
+	// them.
+	StatusFailed = "failed"
+)
+
+// OutboxEvent is a message saved in the same transaction as the change it
+// announces. The Relay publishes it to Exchange under RoutingKey, with its ID
+// as the message ID, and keeps the row as a record of what was sent.
+type OutboxEvent struct {
+	ID         string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Exchange   string `gorm:"type:varchar(100);not null" json:"exchange"`
+	RoutingKey string `gorm:"type:varchar(100);not null" json:"routing_key"`
+	Payload    string `gorm:"type:jsonb;not null" json:"payload"`
+
+	Status        string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_events_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_outbox_events_due,priority:2" json:"next_attempt_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"not null;index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// Enqueue stores the event for publishing under its type once tx commits. The
+// row takes the event's ID, so the message ID consumers see is the event ID.
+// If tx rolls back the event is never sent.
+func Enqueue(tx *gorm.DB, exchange string, event events.Envelope) error {
+	body, err := event.Encode()
+	if err != nil {
+		return err
+	}
+
+	err = tx.Create(&OutboxEvent{
+		ID:            event.ID,
+		Exchange:      exchange,
+		RoutingKey:    event.Type,
+		Payload:       string(body),
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("pkg: failed to save %s event to outbox: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	relayBatchSize = 50
+
+	DefaultMaxAttempts = 10
+
+	baseBackoff = 5 * time.Second
+	maxBackoff  = time.Hour
+
+	// publishTimeout bounds how long one publish may wait for the broker's
+	// confirm, so a blocked broker cannot keep a round's transaction and row
+	// locks open indefinitely.
+	publishTimeout = 10 * time.Second
+)
+
+// Publisher hands an encoded event to the message broker. It must only return
+// nil once the broker has confirmed it holds the message.
+type Publisher interface {
+	PublishRaw(ctx context.Context, exchange string, routingKey string, messageID string, body []byte) error
+}
+
+// Relay moves committed outbox events to the broker. Each round claims a batch
+// of due events with FOR UPDATE SKIP LOCKED, so replicas of a service share the
+// work without publishing the same event twice. A failed publish is retried
+// with exponential backoff until maxAttempts, when the event is parked as
+// failed. Events go out roughly oldest first, but one that is backing off does
+// not hold up the rest.
+type Relay struct {
+	db          *gorm.DB
+	publisher   Publisher
+	interval    time.Duration
+	maxAttempts int
+}
+
+func NewRelay(db *gorm.DB, publisher Publisher, interval time.Duration, maxAttempts int) *Relay {
+	if maxAttempts < 1 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Relay{db: db, publisher: publisher, interval: interval, maxAttempts: maxAttempts}
+}
+
+func (r *Relay) Start(ctx context.Context) {
+	logger.Info("outbox: relay started", zap.Duration("interval", r.interval), zap.Int("max_attempts", r.maxAttempts))
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("outbox: relay shutting down gracefully")
+			return
+		case <-ticker.C:
+			published, err := r.RelayPending(ctx)
+			if err != nil {
+				logger.Error("outbox: relay round failed", zap.Int("published", published), zap.Error(err))
+			}
+		}
+	}
+}
+
+// RelayPending publishes up to one batch of due events and reports how many
+// the broker confirmed. The rows stay locked until every publish in the batch
+// has been answered; if the round's transaction then fails to commit, its
+// events are sent again later, which consumers absorb by deduplicating on the
+// message ID. A publish that times out ends the round early, since the rest of
+// the batch would most likely wait just as long on the same broker.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []OutboxEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, time.Now()).
+			Order("created_at").
+			Limit(relayBatchSize).
+			Find(&events).Error
+		if err != nil {
+			return fmt.Errorf("could not claim outbox events: %w", err)
+		}
+
+		for i := range events {
+			event := &events[i]
+			publishErr := r.attempt(ctx, event)
+			if event.Status == StatusPublished {
+				published++
+			}
+
+			if err = tx.Save(event).Error; err != nil {
+				return fmt.Errorf("could not record attempt on outbox event %s: %w", event.ID, err)
+			}
+			if errors.Is(publishErr, context.DeadlineExceeded) {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pkg: failed to relay outbox events: %w", err)
+	}
+	return published, nil
+}
+
+// attempt publishes event once and records the outcome on it, returning the
+// publish error.
+func (r *Relay) attempt(ctx context.Context, event *OutboxEvent) error {
+	event.Attempts++
+
+	publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	err := r.publisher.PublishRaw(publishCtx, event.Exchange, event.RoutingKey, event.ID, []byte(event.Payload))
+	if err == nil {
+		now := time.Now()
+		event.Status = StatusPublished
+		event.PublishedAt = &now
+		event.LastError = ""
+		return nil
+	}
+
+	event.LastError = err.Error()
+	if event.Attempts >= r.maxAttempts {
+		event.Status = StatusFailed
+		logger.Error("outbox: CRITICAL - giving up on event, it needs to be replayed by hand",
+			zap.String("event_id", event.ID),
+			zap.String("routing_key", event.RoutingKey),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(err),
+		)
+		return err
+	}
+	event.NextAttemptAt = time.Now().Add(Backoff(event.Attempts))
+	return err
+}
+
+// Backoff is how long to wait after the given number of failed attempts:
+// 5s, 10s, 20s and so on, doubling up to an hour.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEventNotFound = errors.New("outbox event not found")
+	ErrNotReplayable = errors.New("only failed outbox events can be replayed")
+)
+
+// Store lets operators look into a service's outbox and send parked events
+// again.
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// List returns events in status, newest first. An empty status lists all.
+func (s *Store) List(ctx context.Context, status string, limit int, offset int) ([]OutboxEvent, error) {
+	query := gorm.G[OutboxEvent](s.db).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	events, err := query.Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pkg: failed to list outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// Replay queues a failed event for the relay again with a fresh set of
+// attempts. The last error is kept until the next attempt replaces it.
+func (s *Store) Replay(ctx context.Context, id string) (*OutboxEvent, error) {
+	var event OutboxEvent
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&OutboxEvent{}).
+			Where("id = ? AND status = ?", id, StatusFailed).
+			Updates(map[string]any{
+				"status":          StatusPending,
+				"attempts":        0,
+				"next_attempt_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("could not requeue event: %w", result.Error)
+		}
+
+		err := tx.Where("id = ?", id).First(&event).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrEventNotFound, id)
+		}
+		if err != nil {
+			return fmt.Errorf("could not load event: %w", err)
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s is %s", ErrNotReplayable, id, event.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkg: failed to replay outbox event: %w", err)
+	}
+	return &event, nil
+}
@@ -7,6 +7,7 @@
 package catalog
 
 import (
+	common "ecommerce/pkg/protobufs/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -66,10 +67,18 @@ func (x *CheckPricesRequest) GetProductIds() []string {
 }
 
 type ProductCheck struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
-	IsAvailable   bool                   `protobuf:"varint,3,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ProductId   string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	IsAvailable bool                   `protobuf:"varint,3,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	Price       *common.Money          `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	// Used by the order service to pick the GST slab and split CGST/SGST vs IGST.
+	CategoryPath string `protobuf:"bytes,5,opt,name=category_path,json=categoryPath,proto3" json:"category_path,omitempty"`
+	SellerId     string `protobuf:"bytes,6,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	SellerGstin  string `protobuf:"bytes,7,opt,name=seller_gstin,json=sellerGstin,proto3" json:"seller_gstin,omitempty"`
+	// Printed on tax invoices: the product and variant title, and the HSN code
+	// of the goods.
+	Name          string `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
+	HsnCode       string `protobuf:"bytes,9,opt,name=hsn_code,json=hsnCode,proto3" json:"hsn_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -111,18 +120,53 @@ func (x *ProductCheck) GetProductId() string {
 	return ""
 }
 
-func (x *ProductCheck) GetPrice() float64 {
+func (x *ProductCheck) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *ProductCheck) GetPrice() *common.Money {
 	if x != nil {
 		return x.Price
 	}
-	return 0
+	return nil
 }
 
-func (x *ProductCheck) GetIsAvailable() bool {
+func (x *ProductCheck) GetCategoryPath() string {
 	if x != nil {
-		return x.IsAvailable
+		return x.CategoryPath
 	}
-	return false
+	return ""
+}
+
+func (x *ProductCheck) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *ProductCheck) GetSellerGstin() string {
+	if x != nil {
+		return x.SellerGstin
+	}
+	return ""
+}
+
+func (x *ProductCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductCheck) GetHsnCode() string {
+	if x != nil {
+		return x.HsnCode
+	}
+	return ""
 }
 
 type CheckPricesResponse struct {
@@ -169,23 +213,750 @@ func (x *CheckPricesResponse) GetProducts() []*ProductCheck {
 	return nil
 }
 
+type StockItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockItem) ProtoMessage() {}
+
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StockItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Items         []*StockItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	TtlSeconds    int32                  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReserveStockRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReserveStockResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReserveStockResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommitReservationRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CommitReservationResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CommittedItems int32                  `protobuf:"varint,1,opt,name=committed_items,json=committedItems,proto3" json:"committed_items,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CommitReservationResponse) Reset() {
+	*x = CommitReservationResponse{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationResponse) ProtoMessage() {}
+
+func (x *CommitReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationResponse.ProtoReflect.Descriptor instead.
+func (*CommitReservationResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CommitReservationResponse) GetCommittedItems() int32 {
+	if x != nil {
+		return x.CommittedItems
+	}
+	return 0
+}
+
+// Puts an order's held or sold units back on sale. product_ids limits the
+// release to those variants, such as the lines one seller rejected.
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ProductIds    []string               `protobuf:"bytes,3,rep,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReleaseReservationRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReleaseReservationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReleaseReservationRequest) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+type ReleaseReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReleasedItems int32                  `protobuf:"varint,1,opt,name=released_items,json=releasedItems,proto3" json:"released_items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationResponse) Reset() {
+	*x = ReleaseReservationResponse{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationResponse) ProtoMessage() {}
+
+func (x *ReleaseReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReleaseReservationResponse) GetReleasedItems() int32 {
+	if x != nil {
+		return x.ReleasedItems
+	}
+	return 0
+}
+
+// Puts returned units back on sale. return_id makes the call idempotent: a
+// return that has already been restocked is left alone.
+type RestockReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	Items         []*StockItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockReturnRequest) Reset() {
+	*x = RestockReturnRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockReturnRequest) ProtoMessage() {}
+
+func (x *RestockReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockReturnRequest.ProtoReflect.Descriptor instead.
+func (*RestockReturnRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RestockReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *RestockReturnRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RestockReturnResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RestockedItems int32                  `protobuf:"varint,1,opt,name=restocked_items,json=restockedItems,proto3" json:"restocked_items,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RestockReturnResponse) Reset() {
+	*x = RestockReturnResponse{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockReturnResponse) ProtoMessage() {}
+
+func (x *RestockReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockReturnResponse.ProtoReflect.Descriptor instead.
+func (*RestockReturnResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RestockReturnResponse) GetRestockedItems() int32 {
+	if x != nil {
+		return x.RestockedItems
+	}
+	return 0
+}
+
+type GetSellersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SellerIds     []string               `protobuf:"bytes,1,rep,name=seller_ids,json=sellerIds,proto3" json:"seller_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellersRequest) Reset() {
+	*x = GetSellersRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellersRequest) ProtoMessage() {}
+
+func (x *GetSellersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellersRequest.ProtoReflect.Descriptor instead.
+func (*GetSellersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSellersRequest) GetSellerIds() []string {
+	if x != nil {
+		return x.SellerIds
+	}
+	return nil
+}
+
+type Seller struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SellerId          string                 `protobuf:"bytes,1,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Gstin             string                 `protobuf:"bytes,3,opt,name=gstin,proto3" json:"gstin,omitempty"`
+	RegisteredAddress string                 `protobuf:"bytes,4,opt,name=registered_address,json=registeredAddress,proto3" json:"registered_address,omitempty"`
+	SupportEmail      string                 `protobuf:"bytes,5,opt,name=support_email,json=supportEmail,proto3" json:"support_email,omitempty"`
+	SupportPhone      string                 `protobuf:"bytes,6,opt,name=support_phone,json=supportPhone,proto3" json:"support_phone,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Seller) Reset() {
+	*x = Seller{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Seller) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Seller) ProtoMessage() {}
+
+func (x *Seller) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Seller.ProtoReflect.Descriptor instead.
+func (*Seller) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Seller) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *Seller) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Seller) GetGstin() string {
+	if x != nil {
+		return x.Gstin
+	}
+	return ""
+}
+
+func (x *Seller) GetRegisteredAddress() string {
+	if x != nil {
+		return x.RegisteredAddress
+	}
+	return ""
+}
+
+func (x *Seller) GetSupportEmail() string {
+	if x != nil {
+		return x.SupportEmail
+	}
+	return ""
+}
+
+func (x *Seller) GetSupportPhone() string {
+	if x != nil {
+		return x.SupportPhone
+	}
+	return ""
+}
+
+type GetSellersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sellers       []*Seller              `protobuf:"bytes,1,rep,name=sellers,proto3" json:"sellers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellersResponse) Reset() {
+	*x = GetSellersResponse{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellersResponse) ProtoMessage() {}
+
+func (x *GetSellersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellersResponse.ProtoReflect.Descriptor instead.
+func (*GetSellersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSellersResponse) GetSellers() []*Seller {
+	if x != nil {
+		return x.Sellers
+	}
+	return nil
+}
+
+type GetSellerByUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellerByUserRequest) Reset() {
+	*x = GetSellerByUserRequest{}
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellerByUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellerByUserRequest) ProtoMessage() {}
+
+func (x *GetSellerByUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_catalog_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellerByUserRequest.ProtoReflect.Descriptor instead.
+func (*GetSellerByUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetSellerByUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
 var File_pkg_protobufs_catalog_catalog_proto protoreflect.FileDescriptor
 
 const file_pkg_protobufs_catalog_catalog_proto_rawDesc = "" +
 	"\n" +
-	"#pkg/protobufs/catalog/catalog.proto\x12\acatalog\"5\n" +
+	"#pkg/protobufs/catalog/catalog.proto\x12\acatalog\x1a pkg/protobufs/common/money.proto\"5\n" +
 	"\x12CheckPricesRequest\x12\x1f\n" +
 	"\vproduct_ids\x18\x01 \x03(\tR\n" +
-	"productIds\"f\n" +
+	"productIds\"\x8f\x02\n" +
 	"\fProductCheck\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
-	"\x05price\x18\x02 \x01(\x01R\x05price\x12!\n" +
-	"\fis_available\x18\x03 \x01(\bR\visAvailable\"H\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12!\n" +
+	"\fis_available\x18\x03 \x01(\bR\visAvailable\x12#\n" +
+	"\x05price\x18\x04 \x01(\v2\r.common.MoneyR\x05price\x12#\n" +
+	"\rcategory_path\x18\x05 \x01(\tR\fcategoryPath\x12\x1b\n" +
+	"\tseller_id\x18\x06 \x01(\tR\bsellerId\x12!\n" +
+	"\fseller_gstin\x18\a \x01(\tR\vsellerGstin\x12\x12\n" +
+	"\x04name\x18\b \x01(\tR\x04name\x12\x19\n" +
+	"\bhsn_code\x18\t \x01(\tR\ahsnCodeJ\x04\b\x02\x10\x03\"H\n" +
 	"\x13CheckPricesResponse\x121\n" +
-	"\bproducts\x18\x01 \x03(\v2\x15.catalog.ProductCheckR\bproducts2\\\n" +
+	"\bproducts\x18\x01 \x03(\v2\x15.catalog.ProductCheckR\bproducts\"F\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"{\n" +
+	"\x13ReserveStockRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.catalog.StockItemR\x05items\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x05R\n" +
+	"ttlSeconds\"P\n" +
+	"\x14ReserveStockResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"5\n" +
+	"\x18CommitReservationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"D\n" +
+	"\x19CommitReservationResponse\x12'\n" +
+	"\x0fcommitted_items\x18\x01 \x01(\x05R\x0ecommittedItems\"o\n" +
+	"\x19ReleaseReservationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vproduct_ids\x18\x03 \x03(\tR\n" +
+	"productIds\"C\n" +
+	"\x1aReleaseReservationResponse\x12%\n" +
+	"\x0ereleased_items\x18\x01 \x01(\x05R\rreleasedItems\"]\n" +
+	"\x14RestockReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.catalog.StockItemR\x05items\"@\n" +
+	"\x15RestockReturnResponse\x12'\n" +
+	"\x0frestocked_items\x18\x01 \x01(\x05R\x0erestockedItems\"2\n" +
+	"\x11GetSellersRequest\x12\x1d\n" +
+	"\n" +
+	"seller_ids\x18\x01 \x03(\tR\tsellerIds\"\xc8\x01\n" +
+	"\x06Seller\x12\x1b\n" +
+	"\tseller_id\x18\x01 \x01(\tR\bsellerId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05gstin\x18\x03 \x01(\tR\x05gstin\x12-\n" +
+	"\x12registered_address\x18\x04 \x01(\tR\x11registeredAddress\x12#\n" +
+	"\rsupport_email\x18\x05 \x01(\tR\fsupportEmail\x12#\n" +
+	"\rsupport_phone\x18\x06 \x01(\tR\fsupportPhone\"?\n" +
+	"\x12GetSellersResponse\x12)\n" +
+	"\asellers\x18\x01 \x03(\v2\x0f.catalog.SellerR\asellers\"1\n" +
+	"\x16GetSellerByUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId2\xcc\x04\n" +
 	"\x0eCatalogService\x12J\n" +
-	"\vCheckPrices\x12\x1b.catalog.CheckPricesRequest\x1a\x1c.catalog.CheckPricesResponse\"\x00B!Z\x1fecommerce/pkg/protobufs/catalogb\x06proto3"
+	"\vCheckPrices\x12\x1b.catalog.CheckPricesRequest\x1a\x1c.catalog.CheckPricesResponse\"\x00\x12M\n" +
+	"\fReserveStock\x12\x1c.catalog.ReserveStockRequest\x1a\x1d.catalog.ReserveStockResponse\"\x00\x12\\\n" +
+	"\x11CommitReservation\x12!.catalog.CommitReservationRequest\x1a\".catalog.CommitReservationResponse\"\x00\x12_\n" +
+	"\x12ReleaseReservation\x12\".catalog.ReleaseReservationRequest\x1a#.catalog.ReleaseReservationResponse\"\x00\x12P\n" +
+	"\rRestockReturn\x12\x1d.catalog.RestockReturnRequest\x1a\x1e.catalog.RestockReturnResponse\"\x00\x12G\n" +
+	"\n" +
+	"GetSellers\x12\x1a.catalog.GetSellersRequest\x1a\x1b.catalog.GetSellersResponse\"\x00\x12E\n" +
+	"\x0fGetSellerByUser\x12\x1f.catalog.GetSellerByUserRequest\x1a\x0f.catalog.Seller\"\x00B!Z\x1fecommerce/pkg/protobufs/catalogb\x06proto3"
 
 var (
 	file_pkg_protobufs_catalog_catalog_proto_rawDescOnce sync.Once
@@ -199,21 +970,51 @@ func file_pkg_protobufs_catalog_catalog_proto_rawDescGZIP() []byte {
 	return file_pkg_protobufs_catalog_catalog_proto_rawDescData
 }
 
-var file_pkg_protobufs_catalog_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_protobufs_catalog_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_pkg_protobufs_catalog_catalog_proto_goTypes = []any{
-	(*CheckPricesRequest)(nil),  // 0: catalog.CheckPricesRequest
-	(*ProductCheck)(nil),        // 1: catalog.ProductCheck
-	(*CheckPricesResponse)(nil), // 2: catalog.CheckPricesResponse
+	(*CheckPricesRequest)(nil),         // 0: catalog.CheckPricesRequest
+	(*ProductCheck)(nil),               // 1: catalog.ProductCheck
+	(*CheckPricesResponse)(nil),        // 2: catalog.CheckPricesResponse
+	(*StockItem)(nil),                  // 3: catalog.StockItem
+	(*ReserveStockRequest)(nil),        // 4: catalog.ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 5: catalog.ReserveStockResponse
+	(*CommitReservationRequest)(nil),   // 6: catalog.CommitReservationRequest
+	(*CommitReservationResponse)(nil),  // 7: catalog.CommitReservationResponse
+	(*ReleaseReservationRequest)(nil),  // 8: catalog.ReleaseReservationRequest
+	(*ReleaseReservationResponse)(nil), // 9: catalog.ReleaseReservationResponse
+	(*RestockReturnRequest)(nil),       // 10: catalog.RestockReturnRequest
+	(*RestockReturnResponse)(nil),      // 11: catalog.RestockReturnResponse
+	(*GetSellersRequest)(nil),          // 12: catalog.GetSellersRequest
+	(*Seller)(nil),                     // 13: catalog.Seller
+	(*GetSellersResponse)(nil),         // 14: catalog.GetSellersResponse
+	(*GetSellerByUserRequest)(nil),     // 15: catalog.GetSellerByUserRequest
+	(*common.Money)(nil),               // 16: common.Money
 }
 var file_pkg_protobufs_catalog_catalog_proto_depIdxs = []int32{
-	1, // 0: catalog.CheckPricesResponse.products:type_name -> catalog.ProductCheck
-	0, // 1: catalog.CatalogService.CheckPrices:input_type -> catalog.CheckPricesRequest
-	2, // 2: catalog.CatalogService.CheckPrices:output_type -> catalog.CheckPricesResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	16, // 0: catalog.ProductCheck.price:type_name -> common.Money
+	1,  // 1: catalog.CheckPricesResponse.products:type_name -> catalog.ProductCheck
+	3,  // 2: catalog.ReserveStockRequest.items:type_name -> catalog.StockItem
+	3,  // 3: catalog.RestockReturnRequest.items:type_name -> catalog.StockItem
+	13, // 4: catalog.GetSellersResponse.sellers:type_name -> catalog.Seller
+	0,  // 5: catalog.CatalogService.CheckPrices:input_type -> catalog.CheckPricesRequest
+	4,  // 6: catalog.CatalogService.ReserveStock:input_type -> catalog.ReserveStockRequest
+	6,  // 7: catalog.CatalogService.CommitReservation:input_type -> catalog.CommitReservationRequest
+	8,  // 8: catalog.CatalogService.ReleaseReservation:input_type -> catalog.ReleaseReservationRequest
+	10, // 9: catalog.CatalogService.RestockReturn:input_type -> catalog.RestockReturnRequest
+	12, // 10: catalog.CatalogService.GetSellers:input_type -> catalog.GetSellersRequest
+	15, // 11: catalog.CatalogService.GetSellerByUser:input_type -> catalog.GetSellerByUserRequest
+	2,  // 12: catalog.CatalogService.CheckPrices:output_type -> catalog.CheckPricesResponse
+	5,  // 13: catalog.CatalogService.ReserveStock:output_type -> catalog.ReserveStockResponse
+	7,  // 14: catalog.CatalogService.CommitReservation:output_type -> catalog.CommitReservationResponse
+	9,  // 15: catalog.CatalogService.ReleaseReservation:output_type -> catalog.ReleaseReservationResponse
+	11, // 16: catalog.CatalogService.RestockReturn:output_type -> catalog.RestockReturnResponse
+	14, // 17: catalog.CatalogService.GetSellers:output_type -> catalog.GetSellersResponse
+	13, // 18: catalog.CatalogService.GetSellerByUser:output_type -> catalog.Seller
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_pkg_protobufs_catalog_catalog_proto_init() }
@@ -227,7 +1028,7 @@ func file_pkg_protobufs_catalog_catalog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_protobufs_catalog_catalog_proto_rawDesc), len(file_pkg_protobufs_catalog_catalog_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
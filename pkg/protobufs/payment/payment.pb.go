@@ -7,6 +7,7 @@
 package payment
 
 import (
+	common "ecommerce/pkg/protobufs/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -25,8 +26,7 @@ type CreatePaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount        *common.Money          `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -75,18 +75,11 @@ func (x *CreatePaymentRequest) GetUserId() string {
 	return ""
 }
 
-func (x *CreatePaymentRequest) GetAmount() int64 {
+func (x *CreatePaymentRequest) GetAmount() *common.Money {
 	if x != nil {
 		return x.Amount
 	}
-	return 0
-}
-
-func (x *CreatePaymentRequest) GetCurrency() string {
-	if x != nil {
-		return x.Currency
-	}
-	return ""
+	return nil
 }
 
 type CreatePaymentResponse struct {
@@ -141,22 +134,414 @@ func (x *CreatePaymentResponse) GetTransactionId() string {
 	return ""
 }
 
+type CancelPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPaymentRequest) Reset() {
+	*x = CancelPaymentRequest{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelPaymentRequest) ProtoMessage() {}
+
+func (x *CancelPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelPaymentRequest.ProtoReflect.Descriptor instead.
+func (*CancelPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CancelPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CancelPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPaymentResponse) Reset() {
+	*x = CancelPaymentResponse{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelPaymentResponse) ProtoMessage() {}
+
+func (x *CancelPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelPaymentResponse.ProtoReflect.Descriptor instead.
+func (*CancelPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelPaymentResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+type RefundPaymentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Leave unset to refund whatever is left of the payment.
+	Amount *common.Money `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason string        `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Repeating a request with the same key returns the original refund.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefundPaymentRequest) Reset() {
+	*x = RefundPaymentRequest{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RefundPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *RefundPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RefundPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefundId      string                 `protobuf:"bytes,1,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Amount        *common.Money          `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentResponse) Reset() {
+	*x = RefundPaymentResponse{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentResponse) ProtoMessage() {}
+
+func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentResponse.ProtoReflect.Descriptor instead.
+func (*RefundPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RefundPaymentResponse) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *RefundPaymentResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RefundPaymentResponse) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type GetSellerBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SellerId      string                 `protobuf:"bytes,1,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellerBalanceRequest) Reset() {
+	*x = GetSellerBalanceRequest{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellerBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellerBalanceRequest) ProtoMessage() {}
+
+func (x *GetSellerBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellerBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetSellerBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSellerBalanceRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+// SellerBalance is what a seller has earned in one currency. Pending earnings
+// are still inside their return window; available has been settled and is
+// waiting for the next payout.
+type SellerBalance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pending       *common.Money          `protobuf:"bytes,1,opt,name=pending,proto3" json:"pending,omitempty"`
+	Available     *common.Money          `protobuf:"bytes,2,opt,name=available,proto3" json:"available,omitempty"`
+	PaidOut       *common.Money          `protobuf:"bytes,3,opt,name=paid_out,json=paidOut,proto3" json:"paid_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SellerBalance) Reset() {
+	*x = SellerBalance{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellerBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellerBalance) ProtoMessage() {}
+
+func (x *SellerBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellerBalance.ProtoReflect.Descriptor instead.
+func (*SellerBalance) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SellerBalance) GetPending() *common.Money {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+func (x *SellerBalance) GetAvailable() *common.Money {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+func (x *SellerBalance) GetPaidOut() *common.Money {
+	if x != nil {
+		return x.PaidOut
+	}
+	return nil
+}
+
+type GetSellerBalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*SellerBalance       `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSellerBalanceResponse) Reset() {
+	*x = GetSellerBalanceResponse{}
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSellerBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSellerBalanceResponse) ProtoMessage() {}
+
+func (x *GetSellerBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_protobufs_payment_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSellerBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetSellerBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_protobufs_payment_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSellerBalanceResponse) GetBalances() []*SellerBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
 var File_pkg_protobufs_payment_payment_proto protoreflect.FileDescriptor
 
 const file_pkg_protobufs_payment_payment_proto_rawDesc = "" +
 	"\n" +
-	"#pkg/protobufs/payment/payment.proto\x12\apayment\"~\n" +
+	"#pkg/protobufs/payment/payment.proto\x12\apayment\x1a pkg/protobufs/common/money.proto\"}\n" +
 	"\x14CreatePaymentRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
-	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
-	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"_\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12%\n" +
+	"\x06amount\x18\x05 \x01(\v2\r.common.MoneyR\x06amountJ\x04\b\x03\x10\x04J\x04\b\x04\x10\x05\"_\n" +
 	"\x15CreatePaymentResponse\x12\x1f\n" +
 	"\vpayment_url\x18\x01 \x01(\tR\n" +
 	"paymentUrl\x12%\n" +
-	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId2g\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\"1\n" +
+	"\x14CancelPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"5\n" +
+	"\x15CancelPaymentResponse\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\"\x99\x01\n" +
+	"\x14RefundPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12%\n" +
+	"\x06amount\x18\x02 \x01(\v2\r.common.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\"s\n" +
+	"\x15RefundPaymentResponse\x12\x1b\n" +
+	"\trefund_id\x18\x01 \x01(\tR\brefundId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12%\n" +
+	"\x06amount\x18\x03 \x01(\v2\r.common.MoneyR\x06amount\"6\n" +
+	"\x17GetSellerBalanceRequest\x12\x1b\n" +
+	"\tseller_id\x18\x01 \x01(\tR\bsellerId\"\x8f\x01\n" +
+	"\rSellerBalance\x12'\n" +
+	"\apending\x18\x01 \x01(\v2\r.common.MoneyR\apending\x12+\n" +
+	"\tavailable\x18\x02 \x01(\v2\r.common.MoneyR\tavailable\x12(\n" +
+	"\bpaid_out\x18\x03 \x01(\v2\r.common.MoneyR\apaidOut\"N\n" +
+	"\x18GetSellerBalanceResponse\x122\n" +
+	"\bbalances\x18\x01 \x03(\v2\x16.payment.SellerBalanceR\bbalances2\xe7\x02\n" +
 	"\x0ePaymentService\x12U\n" +
-	"\x14CreatePaymentSession\x12\x1d.payment.CreatePaymentRequest\x1a\x1e.payment.CreatePaymentResponseB!Z\x1fecommerce/pkg/protobufs/paymentb\x06proto3"
+	"\x14CreatePaymentSession\x12\x1d.payment.CreatePaymentRequest\x1a\x1e.payment.CreatePaymentResponse\x12U\n" +
+	"\x14CancelPaymentSession\x12\x1d.payment.CancelPaymentRequest\x1a\x1e.payment.CancelPaymentResponse\x12N\n" +
+	"\rRefundPayment\x12\x1d.payment.RefundPaymentRequest\x1a\x1e.payment.RefundPaymentResponse\x12W\n" +
+	"\x10GetSellerBalance\x12 .payment.GetSellerBalanceRequest\x1a!.payment.GetSellerBalanceResponseB!Z\x1fecommerce/pkg/protobufs/paymentb\x06proto3"
 
 var (
 	file_pkg_protobufs_payment_payment_proto_rawDescOnce sync.Once
@@ -170,19 +555,40 @@ func file_pkg_protobufs_payment_payment_proto_rawDescGZIP() []byte {
 	return file_pkg_protobufs_payment_payment_proto_rawDescData
 }
 
-var file_pkg_protobufs_payment_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_protobufs_payment_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_pkg_protobufs_payment_payment_proto_goTypes = []any{
-	(*CreatePaymentRequest)(nil),  // 0: payment.CreatePaymentRequest
-	(*CreatePaymentResponse)(nil), // 1: payment.CreatePaymentResponse
+	(*CreatePaymentRequest)(nil),     // 0: payment.CreatePaymentRequest
+	(*CreatePaymentResponse)(nil),    // 1: payment.CreatePaymentResponse
+	(*CancelPaymentRequest)(nil),     // 2: payment.CancelPaymentRequest
+	(*CancelPaymentResponse)(nil),    // 3: payment.CancelPaymentResponse
+	(*RefundPaymentRequest)(nil),     // 4: payment.RefundPaymentRequest
+	(*RefundPaymentResponse)(nil),    // 5: payment.RefundPaymentResponse
+	(*GetSellerBalanceRequest)(nil),  // 6: payment.GetSellerBalanceRequest
+	(*SellerBalance)(nil),            // 7: payment.SellerBalance
+	(*GetSellerBalanceResponse)(nil), // 8: payment.GetSellerBalanceResponse
+	(*common.Money)(nil),             // 9: common.Money
 }
 var file_pkg_protobufs_payment_payment_proto_depIdxs = []int32{
-	0, // 0: payment.PaymentService.CreatePaymentSession:input_type -> payment.CreatePaymentRequest
-	1, // 1: payment.PaymentService.CreatePaymentSession:output_type -> payment.CreatePaymentResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	9,  // 0: payment.CreatePaymentRequest.amount:type_name -> common.Money
+	9,  // 1: payment.RefundPaymentRequest.amount:type_name -> common.Money
+	9,  // 2: payment.RefundPaymentResponse.amount:type_name -> common.Money
+	9,  // 3: payment.SellerBalance.pending:type_name -> common.Money
+	9,  // 4: payment.SellerBalance.available:type_name -> common.Money
+	9,  // 5: payment.SellerBalance.paid_out:type_name -> common.Money
+	7,  // 6: payment.GetSellerBalanceResponse.balances:type_name -> payment.SellerBalance
+	0,  // 7: payment.PaymentService.CreatePaymentSession:input_type -> payment.CreatePaymentRequest
+	2,  // 8: payment.PaymentService.CancelPaymentSession:input_type -> payment.CancelPaymentRequest
+	4,  // 9: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
+	6,  // 10: payment.PaymentService.GetSellerBalance:input_type -> payment.GetSellerBalanceRequest
+	1,  // 11: payment.PaymentService.CreatePaymentSession:output_type -> payment.CreatePaymentResponse
+	3,  // 12: payment.PaymentService.CancelPaymentSession:output_type -> payment.CancelPaymentResponse
+	5,  // 13: payment.PaymentService.RefundPayment:output_type -> payment.RefundPaymentResponse
+	8,  // 14: payment.PaymentService.GetSellerBalance:output_type -> payment.GetSellerBalanceResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_pkg_protobufs_payment_payment_proto_init() }
@@ -196,7 +602,7 @@ func file_pkg_protobufs_payment_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_protobufs_payment_payment_proto_rawDesc), len(file_pkg_protobufs_payment_payment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -97,22 +97,7 @@ func main() {
 	}
 	defer rabbitClient.Close()
 
-	err = rabbitClient.DeclareExchange("user_events", "topic")
-	if err != nil {
-		logger.Fatal("main: failed to declare exchange", zap.Error(err))
-	}
-
-	userQueue, err := rabbitClient.DeclareQueue("auth_queue")
-	if err != nil {
-		logger.Fatal("main: failed to declare RabbitMQ queue", zap.Error(err))
-	}
-
-	err = rabbitClient.BindQueue(userQueue.Name, "user_events", "seller.onboarded")
-	if err != nil {
-		logger.Fatal("main: failed to bind RabbitMQ queue", zap.Error(err))
-	}
-
-	workers.StartUserEventsConsumer(rabbitClient, userRepo, userQueue.Name)
+	workers.StartUserEventsConsumer(rabbitClient, userRepo, "auth_queue")
 
 	authService := service.NewAuthService(userRepo, tokenRepo, otpRepo)
 
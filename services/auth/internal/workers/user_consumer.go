@@ -3,53 +3,46 @@ package workers
 import (
 	"context"
 	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
 	"ecommerce/pkg/logger"
 	"ecommerce/services/auth/internal/repository"
-	"encoding/json"
+	"fmt"
 
 	"go.uber.org/zap"
 )
 
-type UserOnboardedEvent struct {
-	UserID string `json:"user_id"`
-	Status string `json:"status"`
-}
-
 func StartUserEventsConsumer(r *broker.RabbitMQClient, userRepo repository.UserRepository, queueName string) {
 	ctx := context.Background()
-	channel, err := r.Consume(ctx, queueName)
-	if err != nil {
-		logger.Fatal("workers: failed to start user events consumer: ", zap.Error(err))
-	}
+	consumer := broker.NewConsumer(r, broker.ConsumerConfig{
+		Exchange: events.UserEventsExchange,
+		Queue:    queueName,
+	})
+
+	broker.Handle(consumer, events.SellerOnboardedKey, onboard(userRepo, "seller"))
+	broker.Handle(consumer, events.CustomerOnboardedKey, onboard(userRepo, "customer"))
 
 	go func() {
-		for event := range channel {
-			var message UserOnboardedEvent
-
-			err = json.Unmarshal(event.Body, &message)
-			if err != nil {
-				logger.Error("workers: failed to unmarshal event body", zap.Error(err))
-				_ = event.Ack(false)
-				continue
-			}
-
-			if event.RoutingKey == "seller.onboarded" && message.Status == "onboarded" {
-				err = userRepo.UpdateOnboardingStatus(ctx, message.UserID, true)
-				if err != nil {
-					logger.Error("workers: failed to update onboarding status", zap.Error(err))
-				} else {
-					logger.Info("workers: successfully onboarded seller", zap.String("user_id", message.UserID))
-				}
-			} else if (event.RoutingKey == "customer.onboarded") && message.Status == "onboarded" {
-				err = userRepo.UpdateOnboardingStatus(ctx, message.UserID, true)
-				if err != nil {
-					logger.Error("workers: failed to update onboarding status", zap.Error(err))
-				} else {
-					logger.Info("workers: successfully onboarded customer", zap.String("user_id", message.UserID))
-				}
-			}
-
-			_ = event.Ack(false)
+		err := consumer.Run(ctx)
+		if err != nil {
+			logger.Fatal("workers: failed to start user events consumer: ", zap.Error(err))
 		}
 	}()
 }
+
+// onboard marks the user behind an onboarded seller or customer profile, so
+// their next token says they are onboarded.
+func onboard(userRepo repository.UserRepository, kind string) func(ctx context.Context, msg broker.Message, message events.UserOnboarded) error {
+	return func(ctx context.Context, msg broker.Message, message events.UserOnboarded) error {
+		if message.Status != "onboarded" {
+			return nil
+		}
+
+		err := userRepo.UpdateOnboardingStatus(ctx, message.UserID, true)
+		if err != nil {
+			return fmt.Errorf("workers: failed to update onboarding status for %s: %w", message.UserID, err)
+		}
+
+		logger.Info("workers: successfully onboarded "+kind, zap.String("user_id", message.UserID))
+		return nil
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"ecommerce/pkg/money"
 	"ecommerce/services/catalog/internal/domain"
 
 	"github.com/brianvoe/gofakeit/v6"
@@ -108,7 +109,7 @@ func main() {
 				ProductID: product.ID,
 				Title:     fmt.Sprintf("%s - %s", product.Title, gofakeit.Color()),
 				SKU:       fmt.Sprintf("SKU-%s-%d", strings.ToUpper(gofakeit.LetterN(5)), v),
-				Price:     gofakeit.Price(10, 1000),
+				Price:     money.New(int64(gofakeit.Number(1000, 100000)), money.DefaultCurrency),
 				Inventory: gofakeit.Number(0, 100),
 				Specifications: map[string]interface{}{
 					"Color": gofakeit.Color(),
@@ -138,7 +139,7 @@ func main() {
 		ProductID: testProduct.ID,
 		Title:     "E2E Test Variant",
 		SKU:       "TEST-SKU-999",
-		Price:     1500.00,
+		Price:     money.MustParse("1500.00", money.DefaultCurrency),
 		Inventory: 50,
 	}
 	db.Create(&testVariant)
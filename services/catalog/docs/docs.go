@@ -764,7 +764,7 @@ const docTemplate = `{
                     "minimum": 0
                 },
                 "price": {
-                    "type": "number"
+                    "$ref": "#/definitions/money.Money"
                 },
                 "sku": {
                     "type": "string"
@@ -801,8 +801,7 @@ const docTemplate = `{
                     "minimum": 0
                 },
                 "price": {
-                    "type": "number",
-                    "minimum": 0
+                    "$ref": "#/definitions/money.Money"
                 },
                 "productId": {
                     "type": "string"
@@ -818,6 +817,19 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount in the currency's minor unit, e.g. paise",
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "ISO 4217 code, defaults to INR",
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
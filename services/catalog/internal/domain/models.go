@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"ecommerce/pkg/money"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// EventProducer names this service on the events it publishes.
+const EventProducer = "catalog"
+
 type Image struct {
 	URL       string `json:"url"`
 	AltText   string `json:"altText"`
@@ -27,6 +31,8 @@ type Product struct {
 	Highlights  []string               `gorm:"type:jsonb;serializer:json" json:"highlights"`
 	Dimensions  map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"dimensions"`
 	Slug        string                 `gorm:"type:varchar(50)" json:"slug"`
+	// HSNCode classifies the goods for GST and is printed on tax invoices.
+	HSNCode string `gorm:"type:varchar(8)" json:"hsn_code"`
 
 	Variants []*Variant `gorm:"foreignKey:ProductID;references:ID" json:"variants"`
 	Images   []*Image   `gorm:"type:jsonb;serializer:json" json:"images"`
@@ -44,15 +50,17 @@ type Variant struct {
 	PublicID  string    `gorm:"type:varchar(25);uniqueIndex;not null" json:"id"`
 	ProductID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
 
-	Title     string  `gorm:"type:varchar(500);not null" json:"title"`
-	SKU       string  `gorm:"type:varchar(100);not null;uniqueIndex" json:"sku"`
-	Price     float64 `gorm:"type:decimal(10,2);" json:"price"`
-	Inventory int     `gorm:"type:int;default:0" json:"inventory"`
+	Title     string      `gorm:"type:varchar(500);not null" json:"title"`
+	SKU       string      `gorm:"type:varchar(100);not null;uniqueIndex" json:"sku"`
+	Price     money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	Inventory int         `gorm:"type:int;default:0" json:"inventory"`
 
 	Images []*Image `gorm:"type:jsonb;serializer:json" json:"images"`
 
 	Specifications map[string]interface{} `gorm:"type:jsonb;serializer:json;index:idx_specs,type:gin" json:"specifications"`
 
+	Product *Product `gorm:"foreignKey:ProductID" json:"-"`
+
 	CreatedAt time.Time      `gorm:"precision:6" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"precision:6" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"precision:6" json:"deletedAt"`
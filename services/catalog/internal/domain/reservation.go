@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ReservationHeld      = "held"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrVariantNotFound   = errors.New("variant not found")
+	ErrNoReservation     = errors.New("no active reservation")
+)
+
+type StockReservation struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;" json:"-"`
+	OrderID   string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_reservation_order_variant" json:"orderId"`
+	VariantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_reservation_order_variant" json:"-"`
+
+	VariantPublicID string `gorm:"type:varchar(25);not null" json:"variantId"`
+	Quantity        int    `gorm:"type:int;not null" json:"quantity"`
+
+	Status        string    `gorm:"type:varchar(20);default:'held';index" json:"status"`
+	ExpiresAt     time.Time `gorm:"precision:6;index" json:"expiresAt"`
+	ReleaseReason string    `gorm:"type:varchar(100)" json:"releaseReason,omitempty"`
+
+	CreatedAt time.Time `gorm:"precision:6" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"precision:6" json:"updatedAt"`
+}
+
+// StockReturn records units a buyer sent back and that went back into a
+// variant's inventory, so a return is never restocked twice.
+type StockReturn struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;" json:"-"`
+	ReturnID  string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_stock_return_variant" json:"returnId"`
+	VariantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_stock_return_variant" json:"-"`
+
+	VariantPublicID string `gorm:"type:varchar(25);not null" json:"variantId"`
+	Quantity        int    `gorm:"type:int;not null" json:"quantity"`
+
+	CreatedAt time.Time `gorm:"precision:6" json:"createdAt"`
+}
+
+type ReservationItem struct {
+	VariantPublicID string
+	Quantity        int
+}
+
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		newID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate reservation ID: %w", err)
+		}
+		r.ID = newID
+	}
+	return nil
+}
+
+func (r *StockReturn) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		newID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate stock return ID: %w", err)
+		}
+		r.ID = newID
+	}
+	return nil
+}
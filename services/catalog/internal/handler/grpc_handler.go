@@ -2,8 +2,11 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	pb "ecommerce/pkg/protobufs/catalog"
+	"ecommerce/services/catalog/internal/domain"
 	"ecommerce/services/catalog/internal/service"
 
 	"google.golang.org/grpc/codes"
@@ -12,11 +15,13 @@ import (
 
 type CatalogGrpcServer struct {
 	pb.UnimplementedCatalogServiceServer
-	productService service.ProductService
+	productService   service.ProductService
+	inventoryService service.InventoryService
+	sellerService    service.SellerService
 }
 
-func NewCatalogGrpcServer(productService service.ProductService) *CatalogGrpcServer {
-	return &CatalogGrpcServer{productService: productService}
+func NewCatalogGrpcServer(productService service.ProductService, inventoryService service.InventoryService, sellerService service.SellerService) *CatalogGrpcServer {
+	return &CatalogGrpcServer{productService: productService, inventoryService: inventoryService, sellerService: sellerService}
 }
 
 func (s *CatalogGrpcServer) CheckPrices(ctx context.Context, req *pb.CheckPricesRequest) (*pb.CheckPricesResponse, error) {
@@ -36,14 +41,177 @@ func (s *CatalogGrpcServer) CheckPrices(ctx context.Context, req *pb.CheckPrices
 
 	var verifiedProducts []*pb.ProductCheck
 	for _, v := range variants {
-		verifiedProducts = append(verifiedProducts, &pb.ProductCheck{
+		check := &pb.ProductCheck{
 			ProductId:   v.PublicID,
-			Price:       v.Price,
+			Price:       v.Price.ToProto(),
 			IsAvailable: v.Inventory > 0,
-		})
+		}
+		if v.Product != nil {
+			check.CategoryPath = v.Product.Category.Path
+			check.SellerId = v.Product.Seller.PublicID
+			check.SellerGstin = v.Product.Seller.GSTIN
+			check.Name = variantName(v)
+			check.HsnCode = v.Product.HSNCode
+		}
+		verifiedProducts = append(verifiedProducts, check)
 	}
 
 	return &pb.CheckPricesResponse{
 		Products: verifiedProducts,
 	}, nil
 }
+
+// variantName is how a variant is described on invoices: the product's title,
+// followed by the variant's when it adds anything.
+func variantName(v *domain.Variant) string {
+	if v.Title == "" || v.Title == v.Product.Title {
+		return v.Product.Title
+	}
+	return v.Product.Title + " - " + v.Title
+}
+
+func (s *CatalogGrpcServer) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	if req == nil || req.OrderId == "" || len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id and items are required")
+	}
+
+	if len(req.Items) > 100 {
+		return nil, status.Error(codes.InvalidArgument, "cannot reserve more than 100 variants per request")
+	}
+
+	items := make([]domain.ReservationItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId == "" || item.Quantity <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "every item needs a product_id and a positive quantity")
+		}
+		items = append(items, domain.ReservationItem{
+			VariantPublicID: item.ProductId,
+			Quantity:        int(item.Quantity),
+		})
+	}
+
+	expiresAt, err := s.inventoryService.ReserveStock(ctx, req.OrderId, items, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, reservationError(err)
+	}
+
+	return &pb.ReserveStockResponse{
+		OrderId:   req.OrderId,
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
+
+func (s *CatalogGrpcServer) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.CommitReservationResponse, error) {
+	if req == nil || req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	committed, err := s.inventoryService.CommitReservation(ctx, req.OrderId)
+	if err != nil {
+		return nil, reservationError(err)
+	}
+
+	return &pb.CommitReservationResponse{CommittedItems: int32(committed)}, nil
+}
+
+func (s *CatalogGrpcServer) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.ReleaseReservationResponse, error) {
+	if req == nil || req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	released, err := s.inventoryService.ReleaseReservation(ctx, req.OrderId, req.ProductIds, req.Reason)
+	if err != nil {
+		return nil, reservationError(err)
+	}
+
+	return &pb.ReleaseReservationResponse{ReleasedItems: int32(released)}, nil
+}
+
+func (s *CatalogGrpcServer) RestockReturn(ctx context.Context, req *pb.RestockReturnRequest) (*pb.RestockReturnResponse, error) {
+	if req == nil || req.ReturnId == "" || len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "return_id and items are required")
+	}
+
+	items := make([]domain.ReservationItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId == "" || item.Quantity <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "every item needs a product_id and a positive quantity")
+		}
+		items = append(items, domain.ReservationItem{
+			VariantPublicID: item.ProductId,
+			Quantity:        int(item.Quantity),
+		})
+	}
+
+	restocked, err := s.inventoryService.RestockReturn(ctx, req.ReturnId, items)
+	if err != nil {
+		return nil, reservationError(err)
+	}
+
+	return &pb.RestockReturnResponse{RestockedItems: int32(restocked)}, nil
+}
+
+func reservationError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		return status.Errorf(codes.FailedPrecondition, "product is currently unavailable: %v", err)
+	case errors.Is(err, domain.ErrVariantNotFound):
+		return status.Errorf(codes.NotFound, "product not found: %v", err)
+	case errors.Is(err, domain.ErrNoReservation):
+		return status.Errorf(codes.FailedPrecondition, "no active reservation: %v", err)
+	default:
+		return status.Errorf(codes.Internal, "database error while handling reservation: %v", err)
+	}
+}
+
+func (s *CatalogGrpcServer) GetSellers(ctx context.Context, req *pb.GetSellersRequest) (*pb.GetSellersResponse, error) {
+	if req == nil || len(req.SellerIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "seller_ids array cannot be empty")
+	}
+
+	if len(req.SellerIds) > 100 {
+		return nil, status.Error(codes.InvalidArgument, "cannot process more than 100 sellers per request")
+	}
+
+	sellers, err := s.sellerService.GetByPublicIDs(ctx, req.SellerIds)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error while fetching sellers: %v", err)
+	}
+
+	var result []*pb.Seller
+	for _, seller := range sellers {
+		result = append(result, &pb.Seller{
+			SellerId:          seller.PublicID,
+			Name:              seller.Name,
+			Gstin:             seller.GSTIN,
+			RegisteredAddress: seller.RegisteredAddress,
+			SupportEmail:      seller.SupportEmail,
+			SupportPhone:      seller.SupportPhone,
+		})
+	}
+
+	return &pb.GetSellersResponse{Sellers: result}, nil
+}
+
+func (s *CatalogGrpcServer) GetSellerByUser(ctx context.Context, req *pb.GetSellerByUserRequest) (*pb.Seller, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id cannot be empty")
+	}
+
+	seller, err := s.sellerService.GetByUserID(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "database error while fetching seller: %v", err)
+	}
+	if seller == nil {
+		return nil, status.Error(codes.NotFound, "seller not found")
+	}
+
+	return &pb.Seller{
+		SellerId:          seller.PublicID,
+		Name:              seller.Name,
+		Gstin:             seller.GSTIN,
+		RegisteredAddress: seller.RegisteredAddress,
+		SupportEmail:      seller.SupportEmail,
+		SupportPhone:      seller.SupportPhone,
+	}, nil
+}
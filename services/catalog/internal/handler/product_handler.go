@@ -124,6 +124,7 @@ type CreateProductRequest struct {
 	Brand       string                 `json:"brand" required:"true"`
 	Highlights  []string               `json:"highlights"`
 	Dimensions  map[string]interface{} `json:"dimensions"`
+	HSNCode     string                 `json:"hsn_code"`
 
 	Images   []*domain.Image   `json:"images"`
 	Variants []*domain.Variant `json:"variants"`
@@ -150,6 +151,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		Description: request.Description,
 		Brand:       request.Brand,
 		Highlights:  request.Highlights,
+		HSNCode:     request.HSNCode,
 		Images:      request.Images,
 		Variants:    request.Variants,
 	}
@@ -180,6 +182,7 @@ type UpdateProductRequest struct {
 	Description string                 `json:"description" required:"true"`
 	Highlights  []string               `json:"highlights" required:"true"`
 	Dimensions  map[string]interface{} `json:"dimensions"`
+	HSNCode     string                 `json:"hsn_code"`
 
 	Variants []*domain.Variant `json:"variants"`
 	Images   []*domain.Image   `json:"images"`
@@ -227,6 +230,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		Brand:       request.Brand,
 		Highlights:  request.Highlights,
 		Dimensions:  request.Dimensions,
+		HSNCode:     request.HSNCode,
 		Images:      request.Images,
 		Variants:    request.Variants,
 	}
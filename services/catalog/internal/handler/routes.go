@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"ecommerce/pkg/middleware"
+	"ecommerce/pkg/outbox"
 	"ecommerce/services/catalog/internal/service"
 	"net/http"
 
@@ -18,6 +20,7 @@ func RegisterRoutes(
 	sellerHandler *SellerHandler,
 	variantHandler *VariantHandler,
 	sellerService service.SellerService,
+	outboxStore *outbox.Store,
 ) {
 	router.GET("/api/v1/catalog/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	v1 := router.Group("/api/v1/catalog")
@@ -55,4 +58,10 @@ func RegisterRoutes(
 		sellerRoutes.PUT("/variants/:id", variantHandler.UpdateVariant)
 		sellerRoutes.DELETE("/variants/:id", variantHandler.DeleteVariant)
 	}
+
+	internal := v1.Group("/internal")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		outbox.RegisterRoutes(internal, outboxStore)
+	}
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
 	"ecommerce/services/catalog/internal/domain"
 	"ecommerce/services/catalog/internal/service"
 	"net/http"
@@ -49,7 +50,7 @@ func (h *VariantHandler) GetVariantByID(c *gin.Context) {
 type CreateVariantRequest struct {
 	SKU            string                 `json:"sku" binding:"required"`
 	Title          string                 `json:"title" binding:"required"`
-	Price          float64                `json:"price" binding:"required,gt=0"`
+	Price          money.Money            `json:"price" binding:"required"`
 	Inventory      int                    `json:"inventory" binding:"gte=0"`
 	Specifications map[string]interface{} `json:"specifications"`
 	Images         []*domain.Image        `json:"images"`
@@ -91,6 +92,11 @@ func (h *VariantHandler) CreateVariant(c *gin.Context) {
 		return
 	}
 
+	if !req.Price.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "price must be positive"})
+		return
+	}
+
 	newVariant := &domain.Variant{
 		Title:     req.Title,
 		SKU:       req.SKU,
@@ -121,7 +127,7 @@ func (h *VariantHandler) CreateVariant(c *gin.Context) {
 
 type UpdateVariantRequest struct {
 	Title           string                 `json:"title" binding:"required"`
-	Price           float64                `json:"price" binding:"required,gte=0"`
+	Price           money.Money            `json:"price" binding:"required"`
 	SKU             string                 `json:"sku" binding:"required"`
 	Inventory       int                    `json:"inventory" binding:"gte=0"`
 	Images          []*domain.Image        `json:"images"`
@@ -142,7 +142,11 @@ func (p *productRepository) GetByCategoryID(ctx context.Context, categoryID uuid
 func (p *productRepository) GetVariantsByPublicIDs(ctx context.Context, publicIDs []string) ([]*domain.Variant, error) {
 	var variants []*domain.Variant
 
-	variants, err := gorm.G[*domain.Variant](p.db).Where("public_id IN (?)", publicIDs).Find(ctx)
+	variants, err := gorm.G[*domain.Variant](p.db).
+		Preload("Product.Category", nil).
+		Preload("Product.Seller", nil).
+		Where("public_id IN (?)", publicIDs).
+		Find(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to get variants by IDs: %w", err)
 	}
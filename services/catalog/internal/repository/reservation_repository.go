@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/services/catalog/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReservationRepository interface {
+	Reserve(ctx context.Context, orderID string, items []domain.ReservationItem, expiresAt time.Time) error
+	Commit(ctx context.Context, orderID string) (int64, error)
+	Release(ctx context.Context, orderID string, variantPublicIDs []string, reason string) (int64, error)
+	ReleaseExpired(ctx context.Context, orderID string, now time.Time) (int64, error)
+	ListExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+	Restock(ctx context.Context, returnID string, items []domain.ReservationItem) (int64, error)
+}
+
+// expiredReason is recorded on reservations released because they ran out.
+const expiredReason = "expired"
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) Reserve(ctx context.Context, orderID string, items []domain.ReservationItem, expiresAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locked so two attempts to reinstate an expired hold cannot both take
+		// the units.
+		var existing []domain.StockReservation
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			Find(&existing).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not look up existing reservations: %w", innerErr)
+		}
+
+		if len(existing) > 0 {
+			if allExpired(existing) {
+				return reinstate(tx, existing, expiresAt)
+			}
+			for _, e := range existing {
+				if e.Status != domain.ReservationHeld {
+					return fmt.Errorf("reservation for order %s is already %s", orderID, e.Status)
+				}
+			}
+			return nil
+		}
+
+		// Lock variants in a stable order so concurrent checkouts sharing items cannot deadlock.
+		sorted := mergeReservationItems(items)
+
+		for _, item := range sorted {
+			var variant domain.Variant
+			innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("public_id = ?", item.VariantPublicID).
+				Take(&variant).Error
+			if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s", domain.ErrVariantNotFound, item.VariantPublicID)
+			} else if innerErr != nil {
+				return fmt.Errorf("could not lock variant %s: %w", item.VariantPublicID, innerErr)
+			}
+
+			if variant.Inventory < item.Quantity {
+				return fmt.Errorf("%w: %s", domain.ErrInsufficientStock, item.VariantPublicID)
+			}
+
+			innerErr = tx.Model(&domain.Variant{}).
+				Where("id = ?", variant.ID).
+				Update("inventory", gorm.Expr("inventory - ?", item.Quantity)).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not decrement inventory: %w", innerErr)
+			}
+
+			reservation := &domain.StockReservation{
+				OrderID:         orderID,
+				VariantID:       variant.ID,
+				VariantPublicID: variant.PublicID,
+				Quantity:        item.Quantity,
+				Status:          domain.ReservationHeld,
+				ExpiresAt:       expiresAt,
+			}
+			innerErr = tx.Create(reservation).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not save reservation: %w", innerErr)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to reserve stock: %w", err)
+	}
+	return nil
+}
+
+func allExpired(reservations []domain.StockReservation) bool {
+	for _, reservation := range reservations {
+		if reservation.Status != domain.ReservationReleased || reservation.ReleaseReason != expiredReason {
+			return false
+		}
+	}
+	return true
+}
+
+// reinstate holds an order's expired reservations again, for a buyer whose
+// payment arrived after the hold ran out. It fails with ErrInsufficientStock if
+// the units have been sold in the meantime.
+func reinstate(tx *gorm.DB, reservations []domain.StockReservation, expiresAt time.Time) error {
+	// Same lock order as a fresh reservation, so the two cannot deadlock.
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].VariantPublicID < reservations[j].VariantPublicID
+	})
+
+	for _, reservation := range reservations {
+		var variant domain.Variant
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", reservation.VariantID).
+			Take(&variant).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrVariantNotFound, reservation.VariantPublicID)
+		} else if err != nil {
+			return fmt.Errorf("could not lock variant %s: %w", reservation.VariantPublicID, err)
+		}
+
+		if variant.Inventory < reservation.Quantity {
+			return fmt.Errorf("%w: %s", domain.ErrInsufficientStock, reservation.VariantPublicID)
+		}
+
+		err = tx.Model(&domain.Variant{}).
+			Where("id = ?", variant.ID).
+			Update("inventory", gorm.Expr("inventory - ?", reservation.Quantity)).Error
+		if err != nil {
+			return fmt.Errorf("could not decrement inventory: %w", err)
+		}
+
+		err = tx.Model(&domain.StockReservation{}).
+			Where("id = ?", reservation.ID).
+			Updates(map[string]interface{}{
+				"status":         domain.ReservationHeld,
+				"expires_at":     expiresAt,
+				"release_reason": "",
+			}).Error
+		if err != nil {
+			return fmt.Errorf("could not reinstate reservation: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *reservationRepository) Commit(ctx context.Context, orderID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, domain.ReservationHeld).
+		Update("status", domain.ReservationCommitted)
+	if result.Error != nil {
+		return 0, fmt.Errorf("repository: failed to commit reservation: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		return result.RowsAffected, nil
+	}
+
+	var committed int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, domain.ReservationCommitted).
+		Count(&committed).Error
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to check committed reservation: %w", err)
+	}
+
+	if committed == 0 {
+		return 0, fmt.Errorf("repository: %w for order %s", domain.ErrNoReservation, orderID)
+	}
+	return committed, nil
+}
+
+// Release gives back an order's held and committed units, limited to the
+// given variants when any are named.
+func (r *reservationRepository) Release(ctx context.Context, orderID string, variantPublicIDs []string, reason string) (int64, error) {
+	return r.release(ctx, reason, func(tx *gorm.DB) *gorm.DB {
+		query := tx.Where("order_id = ? AND status IN ?", orderID, []string{domain.ReservationHeld, domain.ReservationCommitted})
+		if len(variantPublicIDs) > 0 {
+			query = query.Where("variant_public_id IN ?", variantPublicIDs)
+		}
+		return query
+	})
+}
+
+func (r *reservationRepository) ReleaseExpired(ctx context.Context, orderID string, now time.Time) (int64, error) {
+	return r.release(ctx, expiredReason, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("order_id = ? AND status = ? AND expires_at < ?", orderID, domain.ReservationHeld, now)
+	})
+}
+
+func (r *reservationRepository) release(ctx context.Context, reason string, scope func(tx *gorm.DB) *gorm.DB) (int64, error) {
+	var released int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservations []domain.StockReservation
+		innerErr := scope(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
+			Order("variant_id").
+			Find(&reservations).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not lock reservations: %w", innerErr)
+		}
+
+		for _, reservation := range reservations {
+			innerErr = tx.Model(&domain.Variant{}).
+				Where("id = ?", reservation.VariantID).
+				Update("inventory", gorm.Expr("inventory + ?", reservation.Quantity)).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not restore inventory: %w", innerErr)
+			}
+
+			innerErr = tx.Model(&domain.StockReservation{}).
+				Where("id = ?", reservation.ID).
+				Updates(map[string]interface{}{
+					"status":         domain.ReservationReleased,
+					"release_reason": reason,
+				}).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not mark reservation released: %w", innerErr)
+			}
+		}
+
+		released = int64(len(reservations))
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to release reservation: %w", err)
+	}
+	return released, nil
+}
+
+func (r *reservationRepository) ListExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var orderIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Distinct("order_id").
+		Where("status = ? AND expires_at < ?", domain.ReservationHeld, now).
+		Limit(limit).
+		Pluck("order_id", &orderIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list expired reservations: %w", err)
+	}
+	return orderIDs, nil
+}
+
+// Restock adds returned units back to inventory and records them against the
+// return. A return that has already been restocked is reported, not repeated.
+func (r *reservationRepository) Restock(ctx context.Context, returnID string, items []domain.ReservationItem) (int64, error) {
+	var restocked int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		innerErr := tx.Model(&domain.StockReturn{}).Where("return_id = ?", returnID).Count(&existing).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not look up existing stock returns: %w", innerErr)
+		}
+		if existing > 0 {
+			restocked = existing
+			return nil
+		}
+
+		for _, item := range mergeReservationItems(items) {
+			var variant domain.Variant
+			innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("public_id = ?", item.VariantPublicID).
+				Take(&variant).Error
+			if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s", domain.ErrVariantNotFound, item.VariantPublicID)
+			} else if innerErr != nil {
+				return fmt.Errorf("could not lock variant %s: %w", item.VariantPublicID, innerErr)
+			}
+
+			innerErr = tx.Model(&domain.Variant{}).
+				Where("id = ?", variant.ID).
+				Update("inventory", gorm.Expr("inventory + ?", item.Quantity)).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not restore inventory: %w", innerErr)
+			}
+
+			innerErr = tx.Create(&domain.StockReturn{
+				ReturnID:        returnID,
+				VariantID:       variant.ID,
+				VariantPublicID: variant.PublicID,
+				Quantity:        item.Quantity,
+			}).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not save stock return: %w", innerErr)
+			}
+			restocked++
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to restock return: %w", err)
+	}
+	return restocked, nil
+}
+
+func mergeReservationItems(items []domain.ReservationItem) []domain.ReservationItem {
+	quantities := make(map[string]int)
+	for _, item := range items {
+		quantities[item.VariantPublicID] += item.Quantity
+	}
+
+	merged := make([]domain.ReservationItem, 0, len(quantities))
+	for id, quantity := range quantities {
+		merged = append(merged, domain.ReservationItem{VariantPublicID: id, Quantity: quantity})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].VariantPublicID < merged[j].VariantPublicID
+	})
+	return merged
+}
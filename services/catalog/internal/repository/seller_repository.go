@@ -16,6 +16,8 @@ type SellerRepository interface {
 	GetByPublicID(ctx context.Context, publicID string) (*domain.Seller, error)
 	GetByUserID(ctx context.Context, userID string) (*domain.Seller, error)
 	GetByGSTIN(ctx context.Context, gstin string) (*domain.Seller, error)
+	GetByPublicIDs(ctx context.Context, publicIDs []string) ([]*domain.Seller, error)
+	WithTx(tx *gorm.DB) SellerRepository
 }
 
 type sellerRepository struct {
@@ -26,6 +28,10 @@ func NewSellerRepository(db *gorm.DB) SellerRepository {
 	return &sellerRepository{db: db}
 }
 
+func (s *sellerRepository) WithTx(tx *gorm.DB) SellerRepository {
+	return &sellerRepository{db: tx}
+}
+
 func (s *sellerRepository) Create(ctx context.Context, seller *domain.Seller) error {
 	err := gorm.G[*domain.Seller](s.db).Create(ctx, &seller)
 	if err != nil {
@@ -78,3 +84,11 @@ func (s *sellerRepository) GetByGSTIN(ctx context.Context, gstin string) (*domai
 	}
 	return seller, nil
 }
+
+func (s *sellerRepository) GetByPublicIDs(ctx context.Context, publicIDs []string) ([]*domain.Seller, error) {
+	sellers, err := gorm.G[*domain.Seller](s.db).Where("public_id IN (?)", publicIDs).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get sellers by public ids: %w", err)
+	}
+	return sellers, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"ecommerce/pkg/money"
 	"ecommerce/services/catalog/internal/domain"
 	"errors"
 	"fmt"
@@ -15,7 +16,7 @@ type VariantRepository interface {
 	GetBySKU(ctx context.Context, sku string) (*domain.Variant, error)
 	GetByPublicID(ctx context.Context, publicID string) (*domain.Variant, error)
 	UpdateInventory(ctx context.Context, sku string, quantityChange int) error
-	UpdatePrice(ctx context.Context, sku string, newPrice float64) error
+	UpdatePrice(ctx context.Context, sku string, newPrice money.Money) error
 	UpdateByID(ctx context.Context, id *uuid.UUID, updated *domain.Variant) error
 
 	Delete(ctx context.Context, id *uuid.UUID) error
@@ -78,10 +79,14 @@ func (v *variantRepository) UpdateInventory(ctx context.Context, sku string, qua
 	return nil
 }
 
-func (v *variantRepository) UpdatePrice(ctx context.Context, sku string, newPrice float64) error {
-	_, err := gorm.G[*domain.Variant](v.db).
+func (v *variantRepository) UpdatePrice(ctx context.Context, sku string, newPrice money.Money) error {
+	err := v.db.WithContext(ctx).
+		Model(&domain.Variant{}).
 		Where("sku = ?", sku).
-		Update(ctx, "price", newPrice) // Just update the column directly
+		Updates(map[string]interface{}{
+			"price_amount_minor": newPrice.Amount,
+			"price_currency":     newPrice.Currency,
+		}).Error
 
 	if err != nil {
 		return fmt.Errorf("repository: failed to update price: %w", err)
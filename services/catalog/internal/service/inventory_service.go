@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/catalog/internal/domain"
+	"ecommerce/services/catalog/internal/repository"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 2 * time.Hour
+)
+
+type InventoryService interface {
+	ReserveStock(ctx context.Context, orderID string, items []domain.ReservationItem, ttl time.Duration) (time.Time, error)
+	CommitReservation(ctx context.Context, orderID string) (int64, error)
+	ReleaseReservation(ctx context.Context, orderID string, variantPublicIDs []string, reason string) (int64, error)
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	RestockReturn(ctx context.Context, returnID string, items []domain.ReservationItem) (int64, error)
+}
+
+type inventoryService struct {
+	reservationRepo repository.ReservationRepository
+}
+
+func NewInventoryService(reservationRepo repository.ReservationRepository) InventoryService {
+	return &inventoryService{reservationRepo: reservationRepo}
+}
+
+func (s *inventoryService) ReserveStock(ctx context.Context, orderID string, items []domain.ReservationItem, ttl time.Duration) (time.Time, error) {
+	if orderID == "" {
+		return time.Time{}, fmt.Errorf("service: order id is required")
+	}
+	if len(items) == 0 {
+		return time.Time{}, fmt.Errorf("service: at least one item is required")
+	}
+	for _, item := range items {
+		if item.VariantPublicID == "" || item.Quantity <= 0 {
+			return time.Time{}, fmt.Errorf("service: every item needs a product id and a positive quantity")
+		}
+	}
+
+	if ttl <= 0 {
+		ttl = defaultReservationTTL
+	} else if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	err := s.reservationRepo.Reserve(ctx, orderID, items, expiresAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("service: failed to reserve stock: %w", err)
+	}
+
+	return expiresAt, nil
+}
+
+func (s *inventoryService) CommitReservation(ctx context.Context, orderID string) (int64, error) {
+	committed, err := s.reservationRepo.Commit(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to commit reservation: %w", err)
+	}
+	return committed, nil
+}
+
+// ReleaseReservation puts an order's units back on sale, only those of the
+// given variants when any are named.
+func (s *inventoryService) ReleaseReservation(ctx context.Context, orderID string, variantPublicIDs []string, reason string) (int64, error) {
+	released, err := s.reservationRepo.Release(ctx, orderID, variantPublicIDs, reason)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to release reservation: %w", err)
+	}
+	return released, nil
+}
+
+func (s *inventoryService) RestockReturn(ctx context.Context, returnID string, items []domain.ReservationItem) (int64, error) {
+	if returnID == "" {
+		return 0, fmt.Errorf("service: return id is required")
+	}
+	if len(items) == 0 {
+		return 0, fmt.Errorf("service: at least one item is required")
+	}
+	for _, item := range items {
+		if item.VariantPublicID == "" || item.Quantity <= 0 {
+			return 0, fmt.Errorf("service: every item needs a product id and a positive quantity")
+		}
+	}
+
+	restocked, err := s.reservationRepo.Restock(ctx, returnID, items)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to restock return: %w", err)
+	}
+	return restocked, nil
+}
+
+func (s *inventoryService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	now := time.Now()
+	orderIDs, err := s.reservationRepo.ListExpiredOrderIDs(ctx, now, 100)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to list expired reservations: %w", err)
+	}
+
+	released := 0
+	for _, orderID := range orderIDs {
+		count, innerErr := s.reservationRepo.ReleaseExpired(ctx, orderID, now)
+		if innerErr != nil {
+			logger.Error("service: failed to release expired reservation", zap.String("order_id", orderID), zap.Error(innerErr))
+			continue
+		}
+		if count > 0 {
+			released++
+		}
+	}
+
+	return released, nil
+}
@@ -62,7 +62,7 @@ func (p *productService) UpdateProduct(ctx context.Context, sellerPublicID strin
 		return fmt.Errorf("service: unauthorized. seller does not own this product")
 	}
 
-	err = p.checkProductValidity(updatedData.Title, updatedData.Description, updatedData.Brand)
+	err = p.checkProductValidity(updatedData.Title, updatedData.Description, updatedData.Brand, updatedData.HSNCode)
 	if err != nil {
 		return fmt.Errorf("service: incorrect/invalid product data: %w", err)
 	}
@@ -158,7 +158,7 @@ func NewProductService(categoryRepo repository.CategoryRepository, productRepo r
 }
 
 func (p *productService) CreateProduct(c context.Context, sellerPublicID, categoryPublicID string, product *domain.Product) error {
-	err := p.checkProductValidity(product.Title, product.Description, product.Brand)
+	err := p.checkProductValidity(product.Title, product.Description, product.Brand, product.HSNCode)
 	if err != nil {
 		return fmt.Errorf("service: invalid product details")
 	}
@@ -189,7 +189,7 @@ func (p *productService) CreateProduct(c context.Context, sellerPublicID, catego
 	return nil
 }
 
-func (p *productService) checkProductValidity(title, description, brand string) error {
+func (p *productService) checkProductValidity(title, description, brand, hsnCode string) error {
 	if len(title) < 3 {
 		return fmt.Errorf("product name is too short (minimum 3 characters)")
 	}
@@ -199,10 +199,25 @@ func (p *productService) checkProductValidity(title, description, brand string)
 	if len(brand) == 0 {
 		return fmt.Errorf("product brand cannot be empty")
 	}
+	if hsnCode != "" && !validHSNCode(hsnCode) {
+		return fmt.Errorf("hsn code must be 4, 6 or 8 digits")
+	}
 
 	return nil
 }
 
+func validHSNCode(code string) bool {
+	if len(code) != 4 && len(code) != 6 && len(code) != 8 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *productService) VerifyVariants(ctx context.Context, variantIDs []string) ([]*domain.Variant, error) {
 	if len(variantIDs) == 0 {
 		return nil, nil
@@ -2,30 +2,31 @@ package service
 
 import (
 	"context"
-	"ecommerce/pkg/broker"
-	"ecommerce/pkg/logger"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/outbox"
 	"ecommerce/services/catalog/internal/domain"
 	"ecommerce/services/catalog/internal/repository"
 	"fmt"
 
-	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type SellerService interface {
 	CreateSeller(c context.Context, seller *domain.Seller) error
 	GetByUserID(c context.Context, userID string) (*domain.Seller, error)
 	GetByPublicID(c context.Context, publicID string) (*domain.Seller, error)
+	GetByPublicIDs(c context.Context, publicIDs []string) ([]*domain.Seller, error)
 }
 
 type sellerService struct {
 	sellerRepo repository.SellerRepository
-	broker     *broker.RabbitMQClient
+	db         *gorm.DB
 }
 
-func NewSellerService(sellerRepo repository.SellerRepository, broker *broker.RabbitMQClient) SellerService {
+func NewSellerService(sellerRepo repository.SellerRepository, db *gorm.DB) SellerService {
 	return &sellerService{
 		sellerRepo: sellerRepo,
-		broker:     broker,
+		db:         db,
 	}
 }
 
@@ -44,18 +45,23 @@ func (s *sellerService) CreateSeller(c context.Context, seller *domain.Seller) e
 	} else if existingSeller != nil {
 		return fmt.Errorf("service: gstin already exists")
 	}
-	err = s.sellerRepo.Create(c, seller)
-	if err != nil {
-		return fmt.Errorf("service: failed to create seller: %w", err)
-	}
 
-	event := map[string]interface{}{
-		"user_id": seller.UserID,
-		"status":  "onboarded",
-	}
-	err = s.broker.Publish(c, "user_events", "user.onboarded", event)
+	err = s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if innerErr := s.sellerRepo.WithTx(tx).Create(c, seller); innerErr != nil {
+			return innerErr
+		}
+
+		event, innerErr := events.New(c, domain.EventProducer, events.SellerOnboardedKey, events.UserOnboarded{
+			UserID: seller.UserID,
+			Status: "onboarded",
+		})
+		if innerErr != nil {
+			return innerErr
+		}
+		return outbox.Enqueue(tx, events.UserEventsExchange, event)
+	})
 	if err != nil {
-		logger.Error("service: failed to publish user onboarded event: %v", zap.Error(err))
+		return fmt.Errorf("service: failed to create seller: %w", err)
 	}
 
 	return nil
@@ -81,3 +87,15 @@ func (s *sellerService) GetByPublicID(c context.Context, publicID string) (*doma
 	}
 	return seller, nil
 }
+
+func (s *sellerService) GetByPublicIDs(c context.Context, publicIDs []string) ([]*domain.Seller, error) {
+	if len(publicIDs) == 0 {
+		return nil, nil
+	}
+
+	sellers, err := s.sellerRepo.GetByPublicIDs(c, publicIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get sellers by public IDs: %w", err)
+	}
+	return sellers, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"ecommerce/pkg/money"
 	"ecommerce/services/catalog/internal/domain"
 	"ecommerce/services/catalog/internal/repository"
 	"fmt"
@@ -9,7 +10,7 @@ import (
 
 type VariantService interface {
 	CreateVariant(c context.Context, productPublicID string, sellerPublicID string, item *domain.Variant) error
-	UpdatePriceAndStock(c context.Context, sku string, newPrice float64, stock int) error
+	UpdatePriceAndStock(c context.Context, sku string, newPrice money.Money, stock int) error
 	UpdateVariant(c context.Context, productPublicID string, variantPublicID string, sellerPublicID string, newVariant *domain.Variant) error
 	GetBySKU(c context.Context, sku string) (*domain.Variant, error)
 
@@ -94,7 +95,7 @@ func (v *variantService) UpdateVariant(c context.Context, productPublicID string
 		return fmt.Errorf("service: seller does not own the product")
 	}
 
-	if newVariant.Price < 0 || newVariant.Inventory < 0 {
+	if newVariant.Price.IsNegative() || newVariant.Inventory < 0 {
 		return fmt.Errorf("service: price and inventory must be non negative")
 	}
 
@@ -153,7 +154,7 @@ func (v *variantService) CreateVariant(c context.Context, productPublicID string
 	return nil
 }
 
-func (v *variantService) UpdatePriceAndStock(c context.Context, sku string, newPrice float64, stock int) error {
+func (v *variantService) UpdatePriceAndStock(c context.Context, sku string, newPrice money.Money, stock int) error {
 	variant, err := v.variantRepo.GetBySKU(c, sku)
 	if err != nil {
 		return fmt.Errorf("service: failed to get product variant by sku: %w", err)
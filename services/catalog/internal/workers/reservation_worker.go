@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/catalog/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type ReservationWorker struct {
+	inventoryService service.InventoryService
+	interval         time.Duration
+}
+
+func NewReservationWorker(inventoryService service.InventoryService, interval time.Duration) *ReservationWorker {
+	return &ReservationWorker{inventoryService: inventoryService, interval: interval}
+}
+
+func (w *ReservationWorker) StartReservationWorker(ctx context.Context) {
+	logger.Info("worker: Reservation expiry worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Reservation expiry worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			released, err := w.inventoryService.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				logger.Error("worker: failed to release expired reservations", zap.Error(err))
+				continue
+			}
+			if released > 0 {
+				logger.Info("worker: released expired reservations", zap.Int("order_count", released))
+			}
+		}
+	}
+}
@@ -3,3 +3,17 @@ package domain
 type EmailData struct {
 	OTP string
 }
+
+type InvoiceEmailData struct {
+	BuyerName     string
+	OrderID       string
+	InvoiceNumber string
+	SellerName    string
+	Total         string
+}
+
+type DeliveryOTPEmailData struct {
+	RecipientName string
+	OrderID       string
+	OTP           string
+}
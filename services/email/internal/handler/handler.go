@@ -2,6 +2,7 @@ package handler
 
 import (
 	"ecommerce/pkg/logger"
+	"ecommerce/services/email/internal/domain"
 	"ecommerce/services/email/internal/service"
 	"net/http"
 
@@ -11,6 +12,8 @@ import (
 
 type EmailHandler interface {
 	VerificationEmail(ctx *gin.Context)
+	InvoiceEmail(ctx *gin.Context)
+	DeliveryOTPEmail(ctx *gin.Context)
 }
 
 type VerificationEmailHandler struct {
@@ -45,3 +48,87 @@ func (e *emailHandler) VerificationEmail(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"msg": "email sent successfully"})
 }
+
+type InvoiceEmailRequest struct {
+	To            string `json:"to" binding:"email,required"`
+	BuyerName     string `json:"buyer_name"`
+	OrderID       string `json:"order_id" binding:"required"`
+	InvoiceNumber string `json:"invoice_number" binding:"required"`
+	SellerName    string `json:"seller_name"`
+	Total         string `json:"total"`
+	Filename      string `json:"filename" binding:"required"`
+	// Attachment is the PDF, base64 encoded in JSON.
+	Attachment []byte `json:"attachment" binding:"required"`
+}
+
+func (e *emailHandler) InvoiceEmail(c *gin.Context) {
+	var body InvoiceEmailRequest
+	err := c.ShouldBindJSON(&body)
+
+	if err != nil {
+		logger.Error("handler: could not bind request: ", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "receiver's email address, order, invoice number and attachment are required"})
+		return
+	}
+
+	if len(body.Attachment) > 10<<20 {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "attachment is too large"})
+		return
+	}
+
+	data := domain.InvoiceEmailData{
+		BuyerName:     body.BuyerName,
+		OrderID:       body.OrderID,
+		InvoiceNumber: body.InvoiceNumber,
+		SellerName:    body.SellerName,
+		Total:         body.Total,
+	}
+	if data.BuyerName == "" {
+		data.BuyerName = "there"
+	}
+
+	err = e.service.SendInvoiceEmail(c, body.To, data, body.Filename, body.Attachment)
+	if err != nil {
+		logger.Error("handler: could not send invoice email", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "email sent successfully"})
+}
+
+type DeliveryOTPEmailRequest struct {
+	To            string `json:"to" binding:"email,required"`
+	RecipientName string `json:"recipient_name"`
+	OrderID       string `json:"order_id" binding:"required"`
+	OTP           string `json:"otp" binding:"required,len=6,numeric"`
+}
+
+func (e *emailHandler) DeliveryOTPEmail(c *gin.Context) {
+	var body DeliveryOTPEmailRequest
+	err := c.ShouldBindJSON(&body)
+
+	if err != nil {
+		logger.Error("handler: could not bind request: ", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "receiver's email address, order and OTP are required"})
+		return
+	}
+
+	data := domain.DeliveryOTPEmailData{
+		RecipientName: body.RecipientName,
+		OrderID:       body.OrderID,
+		OTP:           body.OTP,
+	}
+	if data.RecipientName == "" {
+		data.RecipientName = "there"
+	}
+
+	err = e.service.SendDeliveryOTPEmail(c, body.To, data)
+	if err != nil {
+		logger.Error("handler: could not send delivery otp email", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "email sent successfully"})
+}
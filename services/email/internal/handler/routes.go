@@ -1,6 +1,10 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"ecommerce/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
 
 func RegisterRoutes(router *gin.Engine, emailHandler EmailHandler) {
 	v1 := router.Group("/api/v1/email/")
@@ -12,4 +16,13 @@ func RegisterRoutes(router *gin.Engine, emailHandler EmailHandler) {
 			})
 		})
 	}
+
+	// Only other services may send these, so that nobody can use the
+	// platform's mail account to reach recipients of their choosing.
+	internal := router.Group("/api/v1/email/")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		internal.POST("/invoice-email", emailHandler.InvoiceEmail)
+		internal.POST("/delivery-otp-email", emailHandler.DeliveryOTPEmail)
+	}
 }
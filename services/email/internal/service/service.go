@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"ecommerce/services/email/internal/domain"
 	"ecommerce/services/email/internal/utils"
 	"fmt"
 	"os"
@@ -11,6 +12,8 @@ import (
 
 type EmailService interface {
 	SendVerificationEmail(ctx context.Context, to string, OTP string) error
+	SendInvoiceEmail(ctx context.Context, to string, data domain.InvoiceEmailData, filename string, pdf []byte) error
+	SendDeliveryOTPEmail(ctx context.Context, to string, data domain.DeliveryOTPEmailData) error
 }
 
 type emailService struct {
@@ -22,10 +25,7 @@ func NewEmailService(client *resend.Client) EmailService {
 }
 
 func (e *emailService) SendVerificationEmail(ctx context.Context, to, OTP string) error {
-	from := os.Getenv("FROM_EMAIL")
-	if from == "" {
-		from = "onboarding@resend.dev"
-	}
+	from := fromAddress()
 
 	payload, err := utils.GenerateHTMLBody(OTP)
 	if err != nil {
@@ -46,3 +46,60 @@ func (e *emailService) SendVerificationEmail(ctx context.Context, to, OTP string
 
 	return nil
 }
+
+func (e *emailService) SendInvoiceEmail(ctx context.Context, to string, data domain.InvoiceEmailData, filename string, pdf []byte) error {
+	payload, err := utils.GenerateInvoiceHTMLBody(data)
+	if err != nil {
+		return fmt.Errorf("service: could not generate HTML body: %w", err)
+	}
+
+	params := &resend.SendEmailRequest{
+		From:    fromAddress(),
+		To:      []string{to},
+		Subject: fmt.Sprintf("Invoice %s for order %s", data.InvoiceNumber, data.OrderID),
+		Html:    payload,
+		Attachments: []*resend.Attachment{
+			{
+				Filename:    filename,
+				Content:     pdf,
+				ContentType: "application/pdf",
+			},
+		},
+	}
+
+	_, err = e.client.Emails.SendWithContext(ctx, params)
+	if err != nil {
+		return fmt.Errorf("service: could not send invoice email via resend: %w", err)
+	}
+
+	return nil
+}
+
+func (e *emailService) SendDeliveryOTPEmail(ctx context.Context, to string, data domain.DeliveryOTPEmailData) error {
+	payload, err := utils.GenerateDeliveryOTPHTMLBody(data)
+	if err != nil {
+		return fmt.Errorf("service: could not generate HTML body: %w", err)
+	}
+
+	params := &resend.SendEmailRequest{
+		From:    fromAddress(),
+		To:      []string{to},
+		Subject: fmt.Sprintf("Delivery code for order %s", data.OrderID),
+		Html:    payload,
+	}
+
+	_, err = e.client.Emails.SendWithContext(ctx, params)
+	if err != nil {
+		return fmt.Errorf("service: could not send delivery otp email via resend: %w", err)
+	}
+
+	return nil
+}
+
+func fromAddress() string {
+	from := os.Getenv("FROM_EMAIL")
+	if from == "" {
+		from = "onboarding@resend.dev"
+	}
+	return from
+}
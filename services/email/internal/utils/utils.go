@@ -13,6 +13,14 @@ var (
 	verificationEmailTemplate *template.Template
 	tmplOnce                  sync.Once
 	tmplErr                   error
+
+	invoiceEmailTemplate *template.Template
+	invoiceTmplOnce      sync.Once
+	invoiceTmplErr       error
+
+	deliveryOTPEmailTemplate *template.Template
+	deliveryOTPTmplOnce      sync.Once
+	deliveryOTPTmplErr       error
 )
 
 func GenerateHTMLBody(otp string) (string, error) {
@@ -34,3 +42,37 @@ func GenerateHTMLBody(otp string) (string, error) {
 
 	return body.String(), nil
 }
+
+func GenerateInvoiceHTMLBody(data domain.InvoiceEmailData) (string, error) {
+	invoiceTmplOnce.Do(func() {
+		invoiceEmailTemplate, invoiceTmplErr = template.ParseFiles("./internal/templates/invoice.html")
+	})
+
+	if invoiceTmplErr != nil {
+		return "", fmt.Errorf("utils: failed to parse invoice email template: %w", invoiceTmplErr)
+	}
+
+	var body bytes.Buffer
+	if err := invoiceEmailTemplate.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("utils: could not generate invoice HTML body: %w", err)
+	}
+
+	return body.String(), nil
+}
+
+func GenerateDeliveryOTPHTMLBody(data domain.DeliveryOTPEmailData) (string, error) {
+	deliveryOTPTmplOnce.Do(func() {
+		deliveryOTPEmailTemplate, deliveryOTPTmplErr = template.ParseFiles("./internal/templates/delivery_otp.html")
+	})
+
+	if deliveryOTPTmplErr != nil {
+		return "", fmt.Errorf("utils: failed to parse delivery otp email template: %w", deliveryOTPTmplErr)
+	}
+
+	var body bytes.Buffer
+	if err := deliveryOTPEmailTemplate.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("utils: could not generate delivery otp HTML body: %w", err)
+	}
+
+	return body.String(), nil
+}
@@ -0,0 +1,46 @@
+package assignment
+
+import (
+	"ecommerce/services/logistics/internal/domain"
+)
+
+// PickAgent chooses who to offer a shipment to from the agents serving its
+// pincode. Only active, on-duty agents with spare capacity qualify. The one
+// with the lowest load relative to their capacity wins; ties go to whoever has
+// waited longest since their last assignment.
+func PickAgent(candidates []domain.Agent) (*domain.Agent, bool) {
+	var best *domain.Agent
+	for i := range candidates {
+		agent := &candidates[i]
+		if !available(agent) {
+			continue
+		}
+		if best == nil || less(agent, best) {
+			best = agent
+		}
+	}
+	return best, best != nil
+}
+
+func available(a *domain.Agent) bool {
+	return a.Status == domain.AgentActive && a.OnDuty && a.ActiveJobs < a.MaxActiveJobs
+}
+
+func less(a *domain.Agent, b *domain.Agent) bool {
+	// Compare ActiveJobs/MaxActiveJobs without dividing.
+	loadA := a.ActiveJobs * b.MaxActiveJobs
+	loadB := b.ActiveJobs * a.MaxActiveJobs
+	if loadA != loadB {
+		return loadA < loadB
+	}
+
+	switch {
+	case a.LastAssignedAt == nil && b.LastAssignedAt != nil:
+		return true
+	case a.LastAssignedAt != nil && b.LastAssignedAt == nil:
+		return false
+	case a.LastAssignedAt != nil && !a.LastAssignedAt.Equal(*b.LastAssignedAt):
+		return a.LastAssignedAt.Before(*b.LastAssignedAt)
+	}
+	return a.PublicID < b.PublicID
+}
@@ -0,0 +1,56 @@
+package assignment
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce/services/logistics/internal/domain"
+)
+
+func TestPickAgent(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	agent := func(id string, active int, max int, lastAssigned *time.Time) domain.Agent {
+		return domain.Agent{
+			PublicID:       id,
+			Status:         domain.AgentActive,
+			OnDuty:         true,
+			ActiveJobs:     active,
+			MaxActiveJobs:  max,
+			LastAssignedAt: lastAssigned,
+		}
+	}
+
+	offDuty := agent("agt_off", 0, 5, nil)
+	offDuty.OnDuty = false
+	suspended := agent("agt_suspended", 0, 5, nil)
+	suspended.Status = domain.AgentSuspended
+
+	tests := []struct {
+		name       string
+		candidates []domain.Agent
+		want       string
+	}{
+		{"lowest load wins", []domain.Agent{agent("agt_a", 3, 5, nil), agent("agt_b", 1, 5, &now)}, "agt_b"},
+		{"load is relative to capacity", []domain.Agent{agent("agt_a", 2, 4, nil), agent("agt_b", 3, 10, nil)}, "agt_b"},
+		{"full agents are skipped", []domain.Agent{agent("agt_a", 5, 5, nil), agent("agt_b", 4, 5, nil)}, "agt_b"},
+		{"tie goes to the longest idle", []domain.Agent{agent("agt_a", 1, 5, &now), agent("agt_b", 1, 5, &earlier)}, "agt_b"},
+		{"never assigned beats assigned", []domain.Agent{agent("agt_a", 0, 5, &earlier), agent("agt_b", 0, 5, nil)}, "agt_b"},
+		{"off duty and suspended agents are skipped", []domain.Agent{offDuty, suspended, agent("agt_c", 4, 5, nil)}, "agt_c"},
+		{"nobody available", []domain.Agent{offDuty, agent("agt_full", 2, 2, nil)}, ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := PickAgent(tt.candidates)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: picked %s, want nobody", tt.name, got.PublicID)
+			}
+			continue
+		}
+		if !ok || got.PublicID != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sixafter/nanoid"
+	"gorm.io/gorm"
+)
+
+const (
+	AgentActive    = "active"
+	AgentSuspended = "suspended"
+)
+
+var (
+	ErrAgentNotFound    = errors.New("delivery agent not found")
+	ErrAgentExists      = errors.New("delivery agent already registered")
+	ErrNoAgentAvailable = errors.New("no delivery agent available")
+	ErrInvalidPincode   = errors.New("invalid pincode")
+)
+
+// Agent is a delivery partner. ActiveJobs counts the shipments offered to or
+// held by the agent and is what the assignment engine balances on.
+type Agent struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	PublicID string    `gorm:"type:varchar(25);uniqueIndex;not null" json:"id"`
+	UserID   string    `gorm:"type:varchar(21);uniqueIndex;not null" json:"user_id"`
+
+	Name        string `gorm:"type:varchar(100);not null" json:"name"`
+	Phone       string `gorm:"type:varchar(20);not null" json:"phone"`
+	VehicleType string `gorm:"type:varchar(20)" json:"vehicle_type,omitempty"`
+
+	Status         string     `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	OnDuty         bool       `gorm:"not null;default:false" json:"on_duty"`
+	MaxActiveJobs  int        `gorm:"not null;default:5" json:"max_active_jobs"`
+	ActiveJobs     int        `gorm:"not null;default:0" json:"active_jobs"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ServiceAreas []ServiceArea `gorm:"foreignKey:AgentID" json:"service_areas"`
+}
+
+// ServiceArea is a pincode an agent delivers to. Pincodes are the zones the
+// assignment engine matches shipments on.
+type ServiceArea struct {
+	AgentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Pincode string    `gorm:"type:varchar(6);primaryKey;index" json:"pincode"`
+}
+
+func (a *Agent) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		newID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate agent ID: %w", err)
+		}
+		a.ID = newID
+	}
+
+	if a.PublicID == "" {
+		publicID, err := nanoid.New()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate agent public ID: %w", err)
+		}
+		a.PublicID = "agt_" + publicID.String()
+	}
+
+	return nil
+}
+
+// ValidPincode checks the shape of an Indian postal code: six digits, the
+// first of which is not zero.
+func ValidPincode(pincode string) bool {
+	if len(pincode) != 6 || pincode[0] == '0' {
+		return false
+	}
+	for _, r := range pincode {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
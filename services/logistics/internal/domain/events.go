@@ -0,0 +1,5 @@
+package domain
+
+// EventProducer names this service on the events it publishes. The events
+// themselves are defined in pkg/events.
+const EventProducer = "logistics"
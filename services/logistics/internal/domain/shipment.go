@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sixafter/nanoid"
+	"gorm.io/gorm"
+)
+
+type ShipmentStatus string
+
+const (
+	ShipmentPendingAssignment ShipmentStatus = "pending_assignment"
+	ShipmentAssigned          ShipmentStatus = "assigned"
+	ShipmentAccepted          ShipmentStatus = "accepted"
+	ShipmentPickedUp          ShipmentStatus = "picked_up"
+	ShipmentOutForDelivery    ShipmentStatus = "out_for_delivery"
+	ShipmentDelivered         ShipmentStatus = "delivered"
+	ShipmentDeliveryFailed    ShipmentStatus = "delivery_failed"
+	ShipmentCancelled         ShipmentStatus = "cancelled"
+)
+
+// ShipmentDirection tells a delivery to the buyer from a return pickup that
+// carries items back to the seller.
+type ShipmentDirection string
+
+const (
+	DirectionForward ShipmentDirection = "forward"
+	DirectionReverse ShipmentDirection = "reverse"
+)
+
+const (
+	ActorSystem = "system"
+	ActorAgent  = "agent"
+	ActorOps    = "ops"
+)
+
+var (
+	ErrShipmentNotFound          = errors.New("shipment not found")
+	ErrInvalidShipmentTransition = errors.New("invalid shipment status transition")
+	ErrInvalidDeliveryOTP        = errors.New("invalid delivery otp")
+	ErrDeliveryOTPLocked         = errors.New("too many wrong delivery otp attempts")
+)
+
+// MaxDeliveryOTPAttempts is how many wrong codes an agent may enter before the
+// delivery has to be reattempted with a fresh code.
+const MaxDeliveryOTPAttempts = 5
+
+// shipmentTransitions lists where a shipment may go next. Until it is picked
+// up a shipment can fall back to pending_assignment, when its agent declines
+// or lets the offer lapse.
+var shipmentTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentPendingAssignment: {ShipmentAssigned, ShipmentCancelled},
+	ShipmentAssigned:          {ShipmentAccepted, ShipmentPendingAssignment, ShipmentCancelled},
+	ShipmentAccepted:          {ShipmentPickedUp, ShipmentPendingAssignment, ShipmentCancelled},
+	ShipmentPickedUp:          {ShipmentOutForDelivery},
+	ShipmentOutForDelivery:    {ShipmentDelivered, ShipmentDeliveryFailed},
+	ShipmentDeliveryFailed:    {ShipmentOutForDelivery},
+}
+
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentPendingAssignment, ShipmentAssigned, ShipmentAccepted, ShipmentPickedUp,
+		ShipmentOutForDelivery, ShipmentDelivered, ShipmentDeliveryFailed, ShipmentCancelled:
+		return true
+	}
+	return false
+}
+
+func (s ShipmentStatus) CanTransitionTo(next ShipmentStatus) bool {
+	for _, allowed := range shipmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// HoldsAgent reports whether a shipment in this status counts towards its
+// agent's load.
+func (s ShipmentStatus) HoldsAgent() bool {
+	switch s {
+	case ShipmentAssigned, ShipmentAccepted, ShipmentPickedUp, ShipmentOutForDelivery, ShipmentDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+type ShipmentItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Shipment carries one seller's fulfillment of an order from the seller to
+// the buyer or, for a reverse shipment, returned items from the buyer back to
+// the seller. A fulfillment has at most one forward shipment and one reverse
+// shipment per return.
+type Shipment struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey" json:"-"`
+	PublicID      string            `gorm:"type:varchar(25);uniqueIndex;not null" json:"id"`
+	FulfillmentID string            `gorm:"type:varchar(24);not null;uniqueIndex:idx_shipment_source" json:"fulfillment_id"`
+	ReturnID      string            `gorm:"type:varchar(24);not null;default:'';uniqueIndex:idx_shipment_source" json:"return_id,omitempty"`
+	Direction     ShipmentDirection `gorm:"type:varchar(8);not null;default:'forward'" json:"direction"`
+	OrderID       string            `gorm:"type:varchar(20);not null;index" json:"order_id"`
+	SellerID      string            `gorm:"type:varchar(25);not null;index" json:"seller_id"`
+	UserID        string            `gorm:"type:varchar(21);not null;index" json:"user_id"`
+	AgentID       *uuid.UUID        `gorm:"type:uuid;index" json:"-"`
+	Status        ShipmentStatus    `gorm:"type:varchar(24);not null;default:'pending_assignment';index" json:"status"`
+
+	PickupName    string `gorm:"type:varchar(100)" json:"pickup_name"`
+	PickupAddress string `gorm:"type:text" json:"pickup_address"`
+	PickupPhone   string `gorm:"type:varchar(20)" json:"pickup_phone"`
+	// PickupPincode is only known for reverse shipments, which are collected
+	// from the buyer's address.
+	PickupPincode string `gorm:"type:varchar(6)" json:"pickup_pincode,omitempty"`
+
+	RecipientName   string `gorm:"type:varchar(100);not null" json:"recipient_name"`
+	RecipientPhone  string `gorm:"type:varchar(20);not null" json:"recipient_phone"`
+	DeliveryAddress string `gorm:"type:text;not null" json:"delivery_address"`
+	DeliveryCity    string `gorm:"type:varchar(50)" json:"delivery_city"`
+	DeliveryState   string `gorm:"type:varchar(50)" json:"delivery_state"`
+	DeliveryPincode string `gorm:"type:varchar(6);not null;index" json:"delivery_pincode"`
+	RecipientEmail  string `gorm:"type:varchar(255)" json:"-"`
+
+	Items []ShipmentItem `gorm:"type:jsonb;serializer:json" json:"items"`
+
+	DeliveryAttempts int        `gorm:"not null;default:0" json:"delivery_attempts"`
+	AssignedAt       *time.Time `gorm:"index" json:"assigned_at,omitempty"`
+	PickedUpAt       *time.Time `json:"picked_up_at,omitempty"`
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty"`
+
+	// The buyer is emailed a code each time the shipment goes out for delivery
+	// and hands it to the agent; only its hash is kept.
+	DeliveryOTPHash     string `gorm:"type:varchar(64)" json:"-"`
+	DeliveryOTPAttempts int    `gorm:"not null;default:0" json:"-"`
+	ProofOfDeliveryURL  string `gorm:"type:text" json:"proof_of_delivery_url,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Agent *Agent `gorm:"foreignKey:AgentID" json:"agent,omitempty"`
+}
+
+// ShipmentEvent is one entry on a shipment's tracking history. A decline is
+// recorded as a move back to pending_assignment by the agent, which keeps the
+// shipment from being offered to that agent again.
+type ShipmentEvent struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	ShipmentID uuid.UUID      `gorm:"type:uuid;not null;index" json:"-"`
+	Status     ShipmentStatus `gorm:"type:varchar(24);not null" json:"status"`
+	AgentID    *uuid.UUID     `gorm:"type:uuid" json:"-"`
+	Actor      string         `gorm:"type:varchar(20);not null" json:"actor"`
+	Note       string         `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time      `gorm:"index" json:"created_at"`
+}
+
+func (s *Shipment) IsReverse() bool {
+	return s.Direction == DirectionReverse
+}
+
+// ServicePincode is where the agent starts the job, which decides who it is
+// offered to: the buyer's pincode either way, for a delivery or a pickup.
+func (s *Shipment) ServicePincode() string {
+	if s.IsReverse() {
+		return s.PickupPincode
+	}
+	return s.DeliveryPincode
+}
+
+func (s *Shipment) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		newID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate shipment ID: %w", err)
+		}
+		s.ID = newID
+	}
+
+	if s.PublicID == "" {
+		publicID, err := nanoid.New()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate shipment public ID: %w", err)
+		}
+		s.PublicID = "shp_" + publicID.String()
+	}
+
+	return nil
+}
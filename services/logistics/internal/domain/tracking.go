@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Checkpoint is a location an agent reported while carrying a shipment.
+type Checkpoint struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	ShipmentID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	AgentID    uuid.UUID `gorm:"type:uuid;not null" json:"-"`
+	Latitude   float64   `gorm:"not null" json:"latitude"`
+	Longitude  float64   `gorm:"not null" json:"longitude"`
+	Note       string    `gorm:"type:text" json:"note,omitempty"`
+	RecordedAt time.Time `gorm:"not null;index" json:"recorded_at"`
+}
+
+// CanRecordCheckpoint reports whether an agent may post locations for a
+// shipment in this status: from the moment they accept it until it is closed.
+func (s ShipmentStatus) CanRecordCheckpoint() bool {
+	switch s {
+	case ShipmentAccepted, ShipmentPickedUp, ShipmentOutForDelivery, ShipmentDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// Tracking is the public view of a shipment, looked up by tracking number. It
+// leaves out the buyer's contact details and the street address, and anything
+// that could stand in for them: agents' checkpoints, which near the end of the
+// route pin down the buyer's home and the agent's position, and the free-text
+// notes on the timeline. Buyers follow exact checkpoints on their order's
+// tracking stream instead.
+type Tracking struct {
+	TrackingNumber string          `json:"tracking_number"`
+	OrderID        string          `json:"order_id"`
+	Status         ShipmentStatus  `json:"status"`
+	DeliveryCity   string          `json:"delivery_city"`
+	DeliveryState  string          `json:"delivery_state"`
+	PickedUpAt     *time.Time      `json:"picked_up_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	Timeline       []TrackingEvent `json:"timeline"`
+}
+
+// TrackingEvent is a status change as the public tracking view shows it.
+type TrackingEvent struct {
+	Status    ShipmentStatus `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"ecommerce/pkg/logger"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"ecommerce/services/logistics/internal/domain"
+	"ecommerce/services/logistics/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AgentHandler struct {
+	agentService    service.AgentService
+	shipmentService service.ShipmentService
+}
+
+func NewAgentHandler(agentService service.AgentService, shipmentService service.ShipmentService) *AgentHandler {
+	return &AgentHandler{agentService: agentService, shipmentService: shipmentService}
+}
+
+type registerAgentRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Phone        string   `json:"phone" binding:"required"`
+	VehicleType  string   `json:"vehicle_type" binding:"required,oneof=bicycle motorcycle van truck"`
+	ServiceAreas []string `json:"service_areas" binding:"required,min=1"`
+}
+
+type serviceAreasRequest struct {
+	Pincodes []string `json:"pincodes" binding:"required,min=1"`
+}
+
+type dutyRequest struct {
+	OnDuty *bool `json:"on_duty" binding:"required"`
+}
+
+type checkpointRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Note      string   `json:"note" binding:"max=280"`
+}
+
+type declineJobRequest struct {
+	Reason string `json:"reason"`
+}
+
+// jobStatusRequest is sent as JSON, or as a multipart form when a delivery
+// comes with a proof photo.
+type jobStatusRequest struct {
+	Status string `json:"status" form:"status" binding:"required"`
+	Note   string `json:"note" form:"note"`
+	OTP    string `json:"otp" form:"otp"`
+}
+
+func (h *AgentHandler) Register(c *gin.Context) {
+	var req registerAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	agent, err := h.agentService.Register(c.Request.Context(), c.GetString("user_id"), req.Name, req.Phone, req.VehicleType, req.ServiceAreas)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, agent)
+}
+
+func (h *AgentHandler) GetProfile(c *gin.Context) {
+	agent, err := h.agentService.GetProfile(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *AgentHandler) SetServiceAreas(c *gin.Context) {
+	var req serviceAreasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	agent, err := h.agentService.SetServiceAreas(c.Request.Context(), c.GetString("user_id"), req.Pincodes)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *AgentHandler) SetDuty(c *gin.Context) {
+	var req dutyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	agent, err := h.agentService.SetOnDuty(c.Request.Context(), c.GetString("user_id"), *req.OnDuty)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *AgentHandler) ListJobs(c *gin.Context) {
+	page, limit := pagination(c)
+
+	status := domain.ShipmentStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status filter"})
+		return
+	}
+
+	shipments, err := h.shipmentService.ListAgentJobs(c.Request.Context(), c.GetString("user_id"), status, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if shipments == nil {
+		shipments = []domain.Shipment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  shipments,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func (h *AgentHandler) AcceptJob(c *gin.Context) {
+	shipment, err := h.shipmentService.AcceptJob(c.Request.Context(), c.GetString("user_id"), c.Param("shipment_id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+func (h *AgentHandler) DeclineJob(c *gin.Context) {
+	var req declineJobRequest
+	_ = c.ShouldBindJSON(&req)
+
+	_, err := h.shipmentService.DeclineJob(c.Request.Context(), c.GetString("user_id"), c.Param("shipment_id"), req.Reason)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job declined. It will be offered to another agent."})
+}
+
+func (h *AgentHandler) UpdateJobStatus(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 5<<20)
+
+	var req jobStatusRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	status := domain.ShipmentStatus(req.Status)
+	if status == domain.ShipmentDelivered {
+		h.confirmDelivery(c, req)
+		return
+	}
+
+	shipment, err := h.shipmentService.UpdateJobStatus(c.Request.Context(), c.GetString("user_id"), c.Param("shipment_id"), status, req.Note)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+func (h *AgentHandler) confirmDelivery(c *gin.Context, req jobStatusRequest) {
+	// Return pickups are handed to the seller without a code.
+	if req.OTP != "" && len(req.OTP) != 6 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the delivery code from the buyer has 6 digits"})
+		return
+	}
+
+	var photo *service.ProofPhoto
+	if file, err := c.FormFile("photo"); err == nil {
+		content, err := readFormFile(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "could not read proof photo", "details": err.Error()})
+			return
+		}
+		photo = &service.ProofPhoto{Filename: file.Filename, Content: content}
+	}
+
+	shipment, err := h.shipmentService.ConfirmDelivery(c.Request.Context(), c.GetString("user_id"), c.Param("shipment_id"), req.OTP, req.Note, photo)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+func (h *AgentHandler) RecordCheckpoint(c *gin.Context) {
+	var req checkpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude are required", "details": err.Error()})
+		return
+	}
+
+	checkpoint, err := h.shipmentService.RecordCheckpoint(c.Request.Context(), c.GetString("user_id"), c.Param("shipment_id"), *req.Latitude, *req.Longitude, req.Note)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, checkpoint)
+}
+
+func pagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "delivery agent profile not found"})
+	case errors.Is(err, domain.ErrShipmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "shipment not found"})
+	case errors.Is(err, domain.ErrAgentExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "delivery agent profile already exists"})
+	case errors.Is(err, domain.ErrInvalidShipmentTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "This action is not allowed in the shipment's current state.", "details": err.Error()})
+	case errors.Is(err, domain.ErrNoAgentAvailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "no delivery agent is available for this shipment"})
+	case errors.Is(err, domain.ErrInvalidDeliveryOTP):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "the delivery code does not match"})
+	case errors.Is(err, domain.ErrDeliveryOTPLocked):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many wrong delivery codes. mark the attempt failed and try again later."})
+	case errors.Is(err, domain.ErrInvalidPincode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pincodes must be 6 digits", "details": err.Error()})
+	default:
+		logger.Error("Logistics request failed.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
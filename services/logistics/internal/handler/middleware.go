@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"ecommerce/services/logistics/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireLogisticUser admits users signed up with the logistic role, the
+// delivery agents.
+func RequireLogisticUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+			c.Abort()
+			return
+		}
+
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.VerifyJWT(parts[1])
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "details": err.Error()})
+			return
+		}
+
+		userID, ok := claims["id"].(string)
+		if !ok || userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token payload"})
+			c.Abort()
+			return
+		}
+
+		if role, _ := claims["role"].(string); role != "logistic" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden: delivery agent access required"})
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
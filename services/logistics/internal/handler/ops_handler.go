@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"ecommerce/services/logistics/internal/domain"
+	"ecommerce/services/logistics/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpsHandler serves the internal endpoints the operations team uses to manage
+// agents and step in on shipments.
+type OpsHandler struct {
+	agentService    service.AgentService
+	shipmentService service.ShipmentService
+}
+
+func NewOpsHandler(agentService service.AgentService, shipmentService service.ShipmentService) *OpsHandler {
+	return &OpsHandler{agentService: agentService, shipmentService: shipmentService}
+}
+
+type agentStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active suspended"`
+}
+
+type agentCapacityRequest struct {
+	MaxActiveJobs int `json:"max_active_jobs" binding:"required,min=1,max=50"`
+}
+
+type assignShipmentRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+func (h *OpsHandler) ListAgents(c *gin.Context) {
+	page, limit := pagination(c)
+
+	agents, err := h.agentService.ListAgents(c.Request.Context(), c.Query("pincode"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if agents == nil {
+		agents = []domain.Agent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"agents": agents,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (h *OpsHandler) SetAgentStatus(c *gin.Context) {
+	var req agentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	agent, err := h.agentService.SetStatus(c.Request.Context(), c.Param("agent_id"), req.Status)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *OpsHandler) SetAgentCapacity(c *gin.Context) {
+	var req agentCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	agent, err := h.agentService.SetCapacity(c.Request.Context(), c.Param("agent_id"), req.MaxActiveJobs)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *OpsHandler) ListShipments(c *gin.Context) {
+	page, limit := pagination(c)
+
+	status := domain.ShipmentStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status filter"})
+		return
+	}
+
+	shipments, err := h.shipmentService.ListShipments(c.Request.Context(), status, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if shipments == nil {
+		shipments = []domain.Shipment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shipments": shipments,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+func (h *OpsHandler) GetShipment(c *gin.Context) {
+	shipment, events, err := h.shipmentService.GetShipment(c.Request.Context(), c.Param("shipment_id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shipment": shipment,
+		"events":   events,
+	})
+}
+
+// AssignShipment places a shipment with the given agent, or lets the
+// assignment engine choose when no agent is named.
+func (h *OpsHandler) AssignShipment(c *gin.Context) {
+	var req assignShipmentRequest
+	_ = c.ShouldBindJSON(&req)
+
+	shipment, err := h.shipmentService.Assign(c.Request.Context(), c.Param("shipment_id"), req.AgentID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"ecommerce/pkg/middleware"
+	"ecommerce/pkg/outbox"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.Engine, agentHandler *AgentHandler, opsHandler *OpsHandler, trackingHandler *TrackingHandler, outboxStore *outbox.Store) {
+
+	v1 := router.Group("/api/v1/logistics")
+
+	v1.GET("/track/:tracking_number", trackingHandler.Track)
+
+	agent := v1.Group("/agent")
+	agent.Use(RequireLogisticUser())
+	{
+		agent.POST("/profile", agentHandler.Register)
+		agent.GET("/profile", agentHandler.GetProfile)
+		agent.PUT("/service-areas", agentHandler.SetServiceAreas)
+		agent.PUT("/duty", agentHandler.SetDuty)
+
+		agent.GET("/jobs", agentHandler.ListJobs)
+		agent.POST("/jobs/:shipment_id/accept", agentHandler.AcceptJob)
+		agent.POST("/jobs/:shipment_id/decline", agentHandler.DeclineJob)
+		agent.POST("/jobs/:shipment_id/status", agentHandler.UpdateJobStatus)
+		agent.POST("/jobs/:shipment_id/checkpoints", agentHandler.RecordCheckpoint)
+	}
+
+	internal := v1.Group("/internal")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		internal.GET("/agents", opsHandler.ListAgents)
+		internal.PUT("/agents/:agent_id/status", opsHandler.SetAgentStatus)
+		internal.PUT("/agents/:agent_id/capacity", opsHandler.SetAgentCapacity)
+
+		internal.GET("/shipments", opsHandler.ListShipments)
+		internal.GET("/shipments/:shipment_id", opsHandler.GetShipment)
+		internal.POST("/shipments/:shipment_id/assign", opsHandler.AssignShipment)
+
+		outbox.RegisterRoutes(internal, outboxStore)
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"ecommerce/services/logistics/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrackingHandler serves the public tracking lookup. Anyone holding a tracking
+// number may follow the shipment, so it shows the status history and the
+// delivery city but nothing that locates the buyer or the agent.
+type TrackingHandler struct {
+	shipmentService service.ShipmentService
+}
+
+func NewTrackingHandler(shipmentService service.ShipmentService) *TrackingHandler {
+	return &TrackingHandler{shipmentService: shipmentService}
+}
+
+func (h *TrackingHandler) Track(c *gin.Context) {
+	tracking, err := h.shipmentService.Track(c.Request.Context(), c.Param("tracking_number"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tracking)
+}
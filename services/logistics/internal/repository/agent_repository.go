@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce/services/logistics/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AgentRepository interface {
+	Create(ctx context.Context, agent *domain.Agent) error
+	GetByUserID(ctx context.Context, userID string) (*domain.Agent, error)
+	GetByPublicID(ctx context.Context, publicID string) (*domain.Agent, error)
+	List(ctx context.Context, pincode string, limit int, offset int) ([]domain.Agent, error)
+	Update(ctx context.Context, agentID uuid.UUID, fields map[string]any) error
+	ReplaceServiceAreas(ctx context.Context, agentID uuid.UUID, pincodes []string) error
+}
+
+type agentRepository struct {
+	db *gorm.DB
+}
+
+func NewAgentRepository(db *gorm.DB) AgentRepository {
+	return &agentRepository{db: db}
+}
+
+func (r *agentRepository) Create(ctx context.Context, agent *domain.Agent) error {
+	err := gorm.G[domain.Agent](r.db).Create(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create agent: %w", err)
+	}
+	return nil
+}
+
+func (r *agentRepository) GetByUserID(ctx context.Context, userID string) (*domain.Agent, error) {
+	return r.get(ctx, "user_id = ?", userID)
+}
+
+func (r *agentRepository) GetByPublicID(ctx context.Context, publicID string) (*domain.Agent, error) {
+	return r.get(ctx, "public_id = ?", publicID)
+}
+
+func (r *agentRepository) get(ctx context.Context, query string, value string) (*domain.Agent, error) {
+	agent, err := gorm.G[domain.Agent](r.db).
+		Preload("ServiceAreas", nil).
+		Where(query, value).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w", domain.ErrAgentNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get agent: %w", err)
+	}
+	return &agent, nil
+}
+
+// List returns agents by name, optionally only those serving pincode.
+func (r *agentRepository) List(ctx context.Context, pincode string, limit int, offset int) ([]domain.Agent, error) {
+	query := gorm.G[domain.Agent](r.db).Preload("ServiceAreas", nil)
+	if pincode != "" {
+		query = query.Where("id IN (SELECT agent_id FROM service_areas WHERE pincode = ?)", pincode)
+	}
+
+	agents, err := query.Order("name asc").Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list agents: %w", err)
+	}
+	return agents, nil
+}
+
+func (r *agentRepository) Update(ctx context.Context, agentID uuid.UUID, fields map[string]any) error {
+	err := r.db.WithContext(ctx).Model(&domain.Agent{}).Where("id = ?", agentID).Updates(fields).Error
+	if err != nil {
+		return fmt.Errorf("repository: failed to update agent: %w", err)
+	}
+	return nil
+}
+
+func (r *agentRepository) ReplaceServiceAreas(ctx context.Context, agentID uuid.UUID, pincodes []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Where("agent_id = ?", agentID).Delete(&domain.ServiceArea{}).Error
+		if innerErr != nil {
+			return innerErr
+		}
+		if len(pincodes) == 0 {
+			return nil
+		}
+
+		areas := make([]domain.ServiceArea, 0, len(pincodes))
+		for _, pincode := range pincodes {
+			areas = append(areas, domain.ServiceArea{AgentID: agentID, Pincode: pincode})
+		}
+		return tx.Create(&areas).Error
+	})
+	if err != nil {
+		return fmt.Errorf("repository: failed to update service areas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/logistics/internal/assignment"
+	"ecommerce/services/logistics/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// shipmentTimestamps names the column recording when a shipment reached a status.
+var shipmentTimestamps = map[domain.ShipmentStatus]string{
+	domain.ShipmentAssigned:  "assigned_at",
+	domain.ShipmentPickedUp:  "picked_up_at",
+	domain.ShipmentDelivered: "delivered_at",
+}
+
+type ShipmentRepository interface {
+	CreateShipment(ctx context.Context, shipment *domain.Shipment) (*domain.Shipment, bool, error)
+	GetByPublicID(ctx context.Context, publicID string) (*domain.Shipment, error)
+	GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]domain.ShipmentEvent, error)
+	ListShipments(ctx context.Context, status domain.ShipmentStatus, limit int, offset int) ([]domain.Shipment, error)
+	ListAgentShipments(ctx context.Context, agentID uuid.UUID, statuses []domain.ShipmentStatus, limit int, offset int) ([]domain.Shipment, error)
+	ListOrderShipments(ctx context.Context, orderID string) ([]domain.Shipment, error)
+	ListPendingAssignment(ctx context.Context, limit int) ([]domain.Shipment, error)
+	ListStaleOffers(ctx context.Context, assignedBefore time.Time, limit int) ([]domain.Shipment, error)
+	AssignShipment(ctx context.Context, publicID string, agentPublicID string) (*domain.Shipment, error)
+	TransitionShipment(ctx context.Context, publicID string, agentID *uuid.UUID, to domain.ShipmentStatus, actor string, note string) (*domain.Shipment, error)
+	SetDeliveryOTP(ctx context.Context, shipmentID uuid.UUID, otpHash string) error
+	CheckDeliveryOTP(ctx context.Context, publicID string, agentID uuid.UUID, otpHash string) error
+	SetProofOfDelivery(ctx context.Context, shipmentID uuid.UUID, url string) error
+	AddCheckpoint(ctx context.Context, publicID string, agentID uuid.UUID, checkpoint *domain.Checkpoint) (*domain.Shipment, error)
+	WithTx(tx *gorm.DB) ShipmentRepository
+}
+
+type shipmentRepository struct {
+	db *gorm.DB
+}
+
+func NewShipmentRepository(db *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+func (r *shipmentRepository) WithTx(tx *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: tx}
+}
+
+// CreateShipment stores a shipment for a fulfillment unless one exists
+// already, in which case that one is returned and created is false.
+func (r *shipmentRepository) CreateShipment(ctx context.Context, shipment *domain.Shipment) (*domain.Shipment, bool, error) {
+	created := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "fulfillment_id"}, {Name: "return_id"}},
+			DoNothing: true,
+		}).Create(shipment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("fulfillment_id = ? AND return_id = ?", shipment.FulfillmentID, shipment.ReturnID).Take(shipment).Error
+		}
+
+		created = true
+		return tx.Create(&domain.ShipmentEvent{
+			ShipmentID: shipment.ID,
+			Status:     shipment.Status,
+			Actor:      domain.ActorSystem,
+			Note:       "shipment created",
+		}).Error
+	})
+
+	if err != nil {
+		return nil, false, fmt.Errorf("repository: failed to create shipment: %w", err)
+	}
+	return shipment, created, nil
+}
+
+func (r *shipmentRepository) GetByPublicID(ctx context.Context, publicID string) (*domain.Shipment, error) {
+	shipment, err := gorm.G[domain.Shipment](r.db).
+		Preload("Agent", nil).
+		Where("public_id = ?", publicID).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrShipmentNotFound, publicID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get shipment: %w", err)
+	}
+	return &shipment, nil
+}
+
+func (r *shipmentRepository) GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]domain.ShipmentEvent, error) {
+	events, err := gorm.G[domain.ShipmentEvent](r.db).
+		Where("shipment_id = ?", shipmentID).
+		Order("created_at asc").
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get shipment events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *shipmentRepository) ListShipments(ctx context.Context, status domain.ShipmentStatus, limit int, offset int) ([]domain.Shipment, error) {
+	query := gorm.G[domain.Shipment](r.db).Preload("Agent", nil)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	shipments, err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+func (r *shipmentRepository) ListAgentShipments(ctx context.Context, agentID uuid.UUID, statuses []domain.ShipmentStatus, limit int, offset int) ([]domain.Shipment, error) {
+	query := gorm.G[domain.Shipment](r.db).Where("agent_id = ?", agentID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	shipments, err := query.Order("assigned_at desc").Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list agent shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+func (r *shipmentRepository) ListOrderShipments(ctx context.Context, orderID string) ([]domain.Shipment, error) {
+	shipments, err := gorm.G[domain.Shipment](r.db).Where("order_id = ?", orderID).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list order shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+func (r *shipmentRepository) ListPendingAssignment(ctx context.Context, limit int) ([]domain.Shipment, error) {
+	shipments, err := gorm.G[domain.Shipment](r.db).
+		Where("status = ?", domain.ShipmentPendingAssignment).
+		Order("created_at asc").
+		Limit(limit).
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list unassigned shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+func (r *shipmentRepository) ListStaleOffers(ctx context.Context, assignedBefore time.Time, limit int) ([]domain.Shipment, error) {
+	shipments, err := gorm.G[domain.Shipment](r.db).
+		Where("status = ? AND assigned_at < ?", domain.ShipmentAssigned, assignedBefore).
+		Order("assigned_at asc").
+		Limit(limit).
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list stale offers: %w", err)
+	}
+	return shipments, nil
+}
+
+// AssignShipment offers an unassigned shipment to an agent. With an empty
+// agentPublicID the assignment engine picks among the agents serving the
+// delivery pincode, leaving out anyone who already turned the shipment down.
+// Candidate rows are locked so concurrent assignments cannot overload an agent.
+func (r *shipmentRepository) AssignShipment(ctx context.Context, publicID string, agentPublicID string) (*domain.Shipment, error) {
+	var shipment domain.Shipment
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&shipment).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrShipmentNotFound, publicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock shipment: %w", innerErr)
+		}
+
+		if !shipment.Status.CanTransitionTo(domain.ShipmentAssigned) {
+			return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidShipmentTransition, shipment.Status, domain.ShipmentAssigned)
+		}
+
+		var candidates []domain.Agent
+		if agentPublicID != "" {
+			innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("public_id = ?", agentPublicID).
+				Find(&candidates).Error
+		} else {
+			innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("id IN (SELECT agent_id FROM service_areas WHERE pincode = ?)", shipment.ServicePincode()).
+				Where("status = ? AND on_duty AND active_jobs < max_active_jobs", domain.AgentActive).
+				Where("NOT EXISTS (SELECT 1 FROM shipment_events e WHERE e.shipment_id = ? AND e.agent_id = agents.id AND e.status = ?)",
+					shipment.ID, domain.ShipmentPendingAssignment).
+				Find(&candidates).Error
+		}
+		if innerErr != nil {
+			return fmt.Errorf("could not load candidate agents: %w", innerErr)
+		}
+
+		agent, ok := assignment.PickAgent(candidates)
+		if !ok {
+			return domain.ErrNoAgentAvailable
+		}
+
+		now := time.Now()
+		innerErr = tx.Model(&domain.Agent{}).Where("id = ?", agent.ID).Updates(map[string]any{
+			"active_jobs":      gorm.Expr("active_jobs + 1"),
+			"last_assigned_at": now,
+		}).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not update agent load: %w", innerErr)
+		}
+
+		innerErr = tx.Model(&domain.Shipment{}).Where("id = ?", shipment.ID).Updates(map[string]any{
+			"agent_id":    agent.ID,
+			"status":      domain.ShipmentAssigned,
+			"assigned_at": now,
+		}).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not assign shipment: %w", innerErr)
+		}
+
+		innerErr = tx.Create(&domain.ShipmentEvent{
+			ShipmentID: shipment.ID,
+			Status:     domain.ShipmentAssigned,
+			AgentID:    &agent.ID,
+			Actor:      domain.ActorSystem,
+			Note:       "offered to " + agent.Name,
+		}).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not record shipment event: %w", innerErr)
+		}
+
+		shipment.AgentID = &agent.ID
+		shipment.Agent = agent
+		shipment.Status = domain.ShipmentAssigned
+		shipment.AssignedAt = &now
+		return nil
+	})
+
+	if errors.Is(err, domain.ErrNoAgentAvailable) {
+		return nil, fmt.Errorf("repository: %w for pincode %s", domain.ErrNoAgentAvailable, shipment.ServicePincode())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to assign shipment: %w", err)
+	}
+	return &shipment, nil
+}
+
+// TransitionShipment moves a shipment along its lifecycle and records it on
+// the tracking history. When agentID is set the shipment must be held by that
+// agent. Leaving the agent's hands, by delivery, decline or cancellation,
+// frees a slot in the agent's load.
+func (r *shipmentRepository) TransitionShipment(ctx context.Context, publicID string, agentID *uuid.UUID, to domain.ShipmentStatus, actor string, note string) (*domain.Shipment, error) {
+	var shipment domain.Shipment
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&shipment).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrShipmentNotFound, publicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock shipment: %w", innerErr)
+		}
+
+		if agentID != nil && (shipment.AgentID == nil || *shipment.AgentID != *agentID) {
+			return fmt.Errorf("%w: %s", domain.ErrShipmentNotFound, publicID)
+		}
+		if !shipment.Status.CanTransitionTo(to) {
+			return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidShipmentTransition, shipment.Status, to)
+		}
+
+		heldBy := shipment.AgentID
+		updates := map[string]any{"status": to}
+		if column, ok := shipmentTimestamps[to]; ok {
+			updates[column] = time.Now()
+		}
+		if to == domain.ShipmentPendingAssignment {
+			updates["agent_id"] = nil
+			updates["assigned_at"] = nil
+		}
+		if to == domain.ShipmentDeliveryFailed {
+			updates["delivery_attempts"] = gorm.Expr("delivery_attempts + 1")
+		}
+
+		innerErr = tx.Model(&domain.Shipment{}).Where("id = ?", shipment.ID).Updates(updates).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not update shipment status: %w", innerErr)
+		}
+
+		if heldBy != nil && shipment.Status.HoldsAgent() && !to.HoldsAgent() {
+			innerErr = tx.Model(&domain.Agent{}).Where("id = ?", *heldBy).
+				Update("active_jobs", gorm.Expr("GREATEST(active_jobs - 1, 0)")).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not update agent load: %w", innerErr)
+			}
+		}
+
+		innerErr = tx.Create(&domain.ShipmentEvent{
+			ShipmentID: shipment.ID,
+			Status:     to,
+			AgentID:    heldBy,
+			Actor:      actor,
+			Note:       note,
+		}).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not record shipment event: %w", innerErr)
+		}
+
+		return tx.Preload("Agent").Where("id = ?", shipment.ID).Take(&shipment).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to transition shipment: %w", err)
+	}
+	return &shipment, nil
+}
+
+// SetDeliveryOTP replaces the shipment's delivery code and clears the count of
+// wrong attempts against it.
+func (r *shipmentRepository) SetDeliveryOTP(ctx context.Context, shipmentID uuid.UUID, otpHash string) error {
+	err := r.db.WithContext(ctx).Model(&domain.Shipment{}).
+		Where("id = ?", shipmentID).
+		Updates(map[string]any{"delivery_otp_hash": otpHash, "delivery_otp_attempts": 0}).Error
+	if err != nil {
+		return fmt.Errorf("repository: failed to set delivery otp: %w", err)
+	}
+	return nil
+}
+
+// CheckDeliveryOTP verifies the code the agent collected from the buyer. A
+// wrong code is counted even though the check fails, so the row is locked and
+// the count committed before the error is returned.
+func (r *shipmentRepository) CheckDeliveryOTP(ctx context.Context, publicID string, agentID uuid.UUID, otpHash string) error {
+	var checkErr error
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var shipment domain.Shipment
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&shipment).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) || (innerErr == nil && (shipment.AgentID == nil || *shipment.AgentID != agentID)) {
+			checkErr = fmt.Errorf("%w: %s", domain.ErrShipmentNotFound, publicID)
+			return nil
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock shipment: %w", innerErr)
+		}
+
+		switch {
+		case shipment.Status != domain.ShipmentOutForDelivery:
+			checkErr = fmt.Errorf("%w: %s -> %s", domain.ErrInvalidShipmentTransition, shipment.Status, domain.ShipmentDelivered)
+			return nil
+		case shipment.DeliveryOTPAttempts >= domain.MaxDeliveryOTPAttempts:
+			checkErr = domain.ErrDeliveryOTPLocked
+			return nil
+		case shipment.DeliveryOTPHash != "" && subtle.ConstantTimeCompare([]byte(shipment.DeliveryOTPHash), []byte(otpHash)) == 1:
+			return nil
+		}
+
+		checkErr = domain.ErrInvalidDeliveryOTP
+		return tx.Model(&domain.Shipment{}).Where("id = ?", shipment.ID).
+			Update("delivery_otp_attempts", gorm.Expr("delivery_otp_attempts + 1")).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to check delivery otp: %w", err)
+	}
+	if checkErr != nil {
+		return fmt.Errorf("repository: %w", checkErr)
+	}
+	return nil
+}
+
+func (r *shipmentRepository) SetProofOfDelivery(ctx context.Context, shipmentID uuid.UUID, url string) error {
+	err := r.db.WithContext(ctx).Model(&domain.Shipment{}).
+		Where("id = ?", shipmentID).
+		Update("proof_of_delivery_url", url).Error
+	if err != nil {
+		return fmt.Errorf("repository: failed to save proof of delivery: %w", err)
+	}
+	return nil
+}
+
+// AddCheckpoint records a location for a shipment the agent is carrying.
+func (r *shipmentRepository) AddCheckpoint(ctx context.Context, publicID string, agentID uuid.UUID, checkpoint *domain.Checkpoint) (*domain.Shipment, error) {
+	shipment, err := gorm.G[domain.Shipment](r.db).Where("public_id = ?", publicID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (shipment.AgentID == nil || *shipment.AgentID != agentID)) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrShipmentNotFound, publicID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get shipment: %w", err)
+	}
+
+	if !shipment.Status.CanRecordCheckpoint() {
+		return nil, fmt.Errorf("repository: %w: no checkpoints while %s", domain.ErrInvalidShipmentTransition, shipment.Status)
+	}
+
+	checkpoint.ShipmentID = shipment.ID
+	checkpoint.AgentID = agentID
+	err = gorm.G[domain.Checkpoint](r.db).Create(ctx, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to record checkpoint: %w", err)
+	}
+	return &shipment, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"ecommerce/services/logistics/internal/domain"
+	"ecommerce/services/logistics/internal/repository"
+)
+
+type AgentService interface {
+	Register(ctx context.Context, userID string, name string, phone string, vehicleType string, pincodes []string) (*domain.Agent, error)
+	GetProfile(ctx context.Context, userID string) (*domain.Agent, error)
+	SetServiceAreas(ctx context.Context, userID string, pincodes []string) (*domain.Agent, error)
+	SetOnDuty(ctx context.Context, userID string, onDuty bool) (*domain.Agent, error)
+	ListAgents(ctx context.Context, pincode string, page int, limit int) ([]domain.Agent, error)
+	SetStatus(ctx context.Context, agentPublicID string, status string) (*domain.Agent, error)
+	SetCapacity(ctx context.Context, agentPublicID string, maxActiveJobs int) (*domain.Agent, error)
+}
+
+type agentService struct {
+	agentRepo repository.AgentRepository
+}
+
+func NewAgentService(agentRepo repository.AgentRepository) AgentService {
+	return &agentService{agentRepo: agentRepo}
+}
+
+func (s *agentService) Register(ctx context.Context, userID string, name string, phone string, vehicleType string, pincodes []string) (*domain.Agent, error) {
+	areas, err := normalisePincodes(pincodes)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err == nil && existing != nil {
+		return nil, fmt.Errorf("service: %w", domain.ErrAgentExists)
+	}
+	if err != nil && !errors.Is(err, domain.ErrAgentNotFound) {
+		return nil, fmt.Errorf("service: failed to check existing agent: %w", err)
+	}
+
+	agent := &domain.Agent{
+		UserID:        userID,
+		Name:          name,
+		Phone:         phone,
+		VehicleType:   vehicleType,
+		Status:        domain.AgentActive,
+		MaxActiveJobs: 5,
+	}
+	for _, pincode := range areas {
+		agent.ServiceAreas = append(agent.ServiceAreas, domain.ServiceArea{Pincode: pincode})
+	}
+
+	err = s.agentRepo.Create(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to register agent: %w", err)
+	}
+	return agent, nil
+}
+
+func (s *agentService) GetProfile(ctx context.Context, userID string) (*domain.Agent, error) {
+	agent, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent profile: %w", err)
+	}
+	return agent, nil
+}
+
+func (s *agentService) SetServiceAreas(ctx context.Context, userID string, pincodes []string) (*domain.Agent, error) {
+	areas, err := normalisePincodes(pincodes)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.agentRepo.ReplaceServiceAreas(ctx, agent.ID, areas)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to set service areas: %w", err)
+	}
+	return s.GetProfile(ctx, userID)
+}
+
+func (s *agentService) SetOnDuty(ctx context.Context, userID string, onDuty bool) (*domain.Agent, error) {
+	agent, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.agentRepo.Update(ctx, agent.ID, map[string]any{"on_duty": onDuty})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update duty status: %w", err)
+	}
+	agent.OnDuty = onDuty
+	return agent, nil
+}
+
+func (s *agentService) ListAgents(ctx context.Context, pincode string, page int, limit int) ([]domain.Agent, error) {
+	agents, err := s.agentRepo.List(ctx, pincode, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list agents: %w", err)
+	}
+	return agents, nil
+}
+
+func (s *agentService) SetStatus(ctx context.Context, agentPublicID string, status string) (*domain.Agent, error) {
+	if status != domain.AgentActive && status != domain.AgentSuspended {
+		return nil, fmt.Errorf("service: unknown agent status %q", status)
+	}
+	return s.update(ctx, agentPublicID, map[string]any{"status": status})
+}
+
+func (s *agentService) SetCapacity(ctx context.Context, agentPublicID string, maxActiveJobs int) (*domain.Agent, error) {
+	if maxActiveJobs < 1 {
+		return nil, fmt.Errorf("service: max active jobs must be at least 1")
+	}
+	return s.update(ctx, agentPublicID, map[string]any{"max_active_jobs": maxActiveJobs})
+}
+
+func (s *agentService) update(ctx context.Context, agentPublicID string, fields map[string]any) (*domain.Agent, error) {
+	agent, err := s.agentRepo.GetByPublicID(ctx, agentPublicID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent: %w", err)
+	}
+
+	err = s.agentRepo.Update(ctx, agent.ID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update agent: %w", err)
+	}
+	return s.agentRepo.GetByPublicID(ctx, agentPublicID)
+}
+
+func normalisePincodes(pincodes []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, pincode := range pincodes {
+		if !domain.ValidPincode(pincode) {
+			return nil, fmt.Errorf("service: %w: %q", domain.ErrInvalidPincode, pincode)
+		}
+		if !seen[pincode] {
+			seen[pincode] = true
+			out = append(out, pincode)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
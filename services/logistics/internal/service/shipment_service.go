@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/outbox"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce/services/logistics/internal/client"
+	"ecommerce/services/logistics/internal/domain"
+	"ecommerce/services/logistics/internal/repository"
+	"ecommerce/services/logistics/internal/utils"
+
+	pb "ecommerce/pkg/protobufs/catalog"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// agentStatuses are the updates an agent may post on a job they accepted.
+// Delivery goes through ConfirmDelivery, which needs the buyer's code.
+var agentStatuses = map[domain.ShipmentStatus]bool{
+	domain.ShipmentPickedUp:       true,
+	domain.ShipmentOutForDelivery: true,
+	domain.ShipmentDeliveryFailed: true,
+}
+
+// ProofPhoto is an optional picture of the handed-over package.
+type ProofPhoto struct {
+	Filename string
+	Content  []byte
+}
+
+type ShipmentService interface {
+	CreateFromFulfillment(ctx context.Context, event events.FulfillmentStatusChanged) (*domain.Shipment, error)
+	CreateReturnPickup(ctx context.Context, event events.ReturnStatusChanged) (*domain.Shipment, error)
+	CancelOrderShipments(ctx context.Context, orderID string, reason string) error
+	AssignPending(ctx context.Context) (int, error)
+	ExpireOffers(ctx context.Context, offerTTL time.Duration) (int, error)
+
+	ListAgentJobs(ctx context.Context, userID string, status domain.ShipmentStatus, page int, limit int) ([]domain.Shipment, error)
+	AcceptJob(ctx context.Context, userID string, shipmentID string) (*domain.Shipment, error)
+	DeclineJob(ctx context.Context, userID string, shipmentID string, reason string) (*domain.Shipment, error)
+	UpdateJobStatus(ctx context.Context, userID string, shipmentID string, to domain.ShipmentStatus, note string) (*domain.Shipment, error)
+	ConfirmDelivery(ctx context.Context, userID string, shipmentID string, otp string, note string, photo *ProofPhoto) (*domain.Shipment, error)
+	RecordCheckpoint(ctx context.Context, userID string, shipmentID string, latitude float64, longitude float64, note string) (*domain.Checkpoint, error)
+	Track(ctx context.Context, trackingNumber string) (*domain.Tracking, error)
+
+	ListShipments(ctx context.Context, status domain.ShipmentStatus, page int, limit int) ([]domain.Shipment, error)
+	GetShipment(ctx context.Context, shipmentID string) (*domain.Shipment, []domain.ShipmentEvent, error)
+	Assign(ctx context.Context, shipmentID string, agentPublicID string) (*domain.Shipment, error)
+
+	WithTx(tx *gorm.DB) ShipmentService
+}
+
+type shipmentService struct {
+	shipmentRepo  repository.ShipmentRepository
+	agentRepo     repository.AgentRepository
+	catalogClient pb.CatalogServiceClient
+	emailClient   client.EmailClient
+	mediaClient   client.MediaClient
+	db            *gorm.DB
+}
+
+func NewShipmentService(
+	shipmentRepo repository.ShipmentRepository,
+	agentRepo repository.AgentRepository,
+	catalogClient pb.CatalogServiceClient,
+	emailClient client.EmailClient,
+	mediaClient client.MediaClient,
+	db *gorm.DB,
+) ShipmentService {
+	return &shipmentService{
+		shipmentRepo:  shipmentRepo,
+		agentRepo:     agentRepo,
+		catalogClient: catalogClient,
+		emailClient:   emailClient,
+		mediaClient:   mediaClient,
+		db:            db,
+	}
+}
+
+// WithTx returns a copy of the service whose shipment writes and events join
+// tx.
+func (s *shipmentService) WithTx(tx *gorm.DB) ShipmentService {
+	clone := *s
+	clone.shipmentRepo = s.shipmentRepo.WithTx(tx)
+	clone.db = tx
+	return &clone
+}
+
+// CreateFromFulfillment opens a shipment for a fulfillment the seller marked
+// ready to ship and offers it to an agent straight away. If nobody is free the
+// assignment worker keeps trying.
+func (s *shipmentService) CreateFromFulfillment(ctx context.Context, event events.FulfillmentStatusChanged) (*domain.Shipment, error) {
+	if !domain.ValidPincode(event.ShippingZip) {
+		return nil, fmt.Errorf("service: %w: %q", domain.ErrInvalidPincode, event.ShippingZip)
+	}
+
+	resp, err := s.catalogClient.GetSellers(ctx, &pb.GetSellersRequest{SellerIds: []string{event.SellerID}})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch seller pickup address: %w", err)
+	}
+
+	shipment := &domain.Shipment{
+		FulfillmentID:   event.FulfillmentID,
+		OrderID:         event.OrderID,
+		SellerID:        event.SellerID,
+		UserID:          event.UserID,
+		Status:          domain.ShipmentPendingAssignment,
+		RecipientName:   event.ShippingName,
+		RecipientPhone:  event.ShippingPhone,
+		RecipientEmail:  event.CustomerEmail,
+		DeliveryAddress: event.ShippingAddress,
+		DeliveryCity:    event.ShippingCity,
+		DeliveryState:   event.ShippingState,
+		DeliveryPincode: event.ShippingZip,
+		Items:           shipmentItems(event.Items),
+	}
+	if len(resp.Sellers) > 0 {
+		seller := resp.Sellers[0]
+		shipment.PickupName = seller.Name
+		shipment.PickupAddress = seller.RegisteredAddress
+		shipment.PickupPhone = seller.SupportPhone
+	}
+
+	return s.open(ctx, shipment)
+}
+
+// CreateReturnPickup opens a reverse shipment that collects an approved
+// return from the buyer and takes it back to the seller.
+func (s *shipmentService) CreateReturnPickup(ctx context.Context, event events.ReturnStatusChanged) (*domain.Shipment, error) {
+	if !domain.ValidPincode(event.PickupZip) {
+		return nil, fmt.Errorf("service: %w: %q", domain.ErrInvalidPincode, event.PickupZip)
+	}
+
+	resp, err := s.catalogClient.GetSellers(ctx, &pb.GetSellersRequest{SellerIds: []string{event.SellerID}})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch seller return address: %w", err)
+	}
+	if len(resp.Sellers) == 0 {
+		return nil, fmt.Errorf("service: seller %s not found for return %s", event.SellerID, event.ReturnID)
+	}
+	seller := resp.Sellers[0]
+
+	shipment := &domain.Shipment{
+		FulfillmentID:   event.FulfillmentID,
+		ReturnID:        event.ReturnID,
+		Direction:       domain.DirectionReverse,
+		OrderID:         event.OrderID,
+		SellerID:        event.SellerID,
+		UserID:          event.UserID,
+		Status:          domain.ShipmentPendingAssignment,
+		PickupName:      event.PickupName,
+		PickupPhone:     event.PickupPhone,
+		PickupAddress:   joinAddress(event.PickupAddress, event.PickupCity, event.PickupState),
+		PickupPincode:   event.PickupZip,
+		RecipientName:   seller.Name,
+		RecipientPhone:  seller.SupportPhone,
+		DeliveryAddress: seller.RegisteredAddress,
+		Items:           shipmentItems(event.Items),
+	}
+	return s.open(ctx, shipment)
+}
+
+// open saves a new shipment and offers it to an agent straight away.
+func (s *shipmentService) open(ctx context.Context, shipment *domain.Shipment) (*domain.Shipment, error) {
+	shipment, created, err := s.shipmentRepo.CreateShipment(ctx, shipment)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to create shipment: %w", err)
+	}
+	if !created || shipment.Status != domain.ShipmentPendingAssignment {
+		return shipment, nil
+	}
+
+	assigned, err := s.shipmentRepo.AssignShipment(ctx, shipment.PublicID, "")
+	if errors.Is(err, domain.ErrNoAgentAvailable) {
+		logger.Info("service: no agent free for new shipment", zap.String("shipment_id", shipment.PublicID), zap.String("pincode", shipment.ServicePincode()))
+		return shipment, nil
+	}
+	if err != nil {
+		logger.Error("service: failed to assign new shipment", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+		return shipment, nil
+	}
+	return assigned, nil
+}
+
+// CancelOrderShipments withdraws the order's shipments that have not been
+// picked up yet.
+func (s *shipmentService) CancelOrderShipments(ctx context.Context, orderID string, reason string) error {
+	shipments, err := s.shipmentRepo.ListOrderShipments(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("service: failed to list order shipments: %w", err)
+	}
+
+	for _, shipment := range shipments {
+		if !shipment.Status.CanTransitionTo(domain.ShipmentCancelled) {
+			continue
+		}
+		_, err = s.announce(ctx, reason, func(shipmentRepo repository.ShipmentRepository) (*domain.Shipment, error) {
+			return shipmentRepo.TransitionShipment(ctx, shipment.PublicID, nil, domain.ShipmentCancelled, domain.ActorSystem, reason)
+		})
+		if err != nil {
+			return fmt.Errorf("service: failed to cancel shipment %s: %w", shipment.PublicID, err)
+		}
+	}
+	return nil
+}
+
+func (s *shipmentService) AssignPending(ctx context.Context) (int, error) {
+	shipments, err := s.shipmentRepo.ListPendingAssignment(ctx, 100)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to list unassigned shipments: %w", err)
+	}
+
+	assigned := 0
+	for _, shipment := range shipments {
+		_, err = s.shipmentRepo.AssignShipment(ctx, shipment.PublicID, "")
+		if errors.Is(err, domain.ErrNoAgentAvailable) {
+			continue
+		}
+		if err != nil {
+			logger.Error("service: failed to assign shipment", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+			continue
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+// ExpireOffers takes back offers an agent has not answered within offerTTL so
+// the shipment can go to someone else.
+func (s *shipmentService) ExpireOffers(ctx context.Context, offerTTL time.Duration) (int, error) {
+	shipments, err := s.shipmentRepo.ListStaleOffers(ctx, time.Now().Add(-offerTTL), 100)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to list stale offers: %w", err)
+	}
+
+	expired := 0
+	for _, shipment := range shipments {
+		_, err = s.shipmentRepo.TransitionShipment(ctx, shipment.PublicID, nil, domain.ShipmentPendingAssignment, domain.ActorSystem, "offer expired")
+		if errors.Is(err, domain.ErrInvalidShipmentTransition) {
+			continue
+		}
+		if err != nil {
+			logger.Error("service: failed to expire shipment offer", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *shipmentService) ListAgentJobs(ctx context.Context, userID string, status domain.ShipmentStatus, page int, limit int) ([]domain.Shipment, error) {
+	agent, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent: %w", err)
+	}
+
+	var statuses []domain.ShipmentStatus
+	if status != "" {
+		statuses = []domain.ShipmentStatus{status}
+	}
+
+	shipments, err := s.shipmentRepo.ListAgentShipments(ctx, agent.ID, statuses, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list agent jobs: %w", err)
+	}
+	return shipments, nil
+}
+
+func (s *shipmentService) AcceptJob(ctx context.Context, userID string, shipmentID string) (*domain.Shipment, error) {
+	return s.agentTransition(ctx, userID, shipmentID, domain.ShipmentAccepted, "accepted by agent")
+}
+
+// DeclineJob hands the shipment back for reassignment. It will not be offered
+// to the same agent again.
+func (s *shipmentService) DeclineJob(ctx context.Context, userID string, shipmentID string, reason string) (*domain.Shipment, error) {
+	if reason == "" {
+		reason = "declined by agent"
+	}
+
+	shipment, err := s.agentTransition(ctx, userID, shipmentID, domain.ShipmentPendingAssignment, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.announce(ctx, "", func(shipmentRepo repository.ShipmentRepository) (*domain.Shipment, error) {
+		return shipmentRepo.AssignShipment(ctx, shipment.PublicID, "")
+	})
+	if err != nil && !errors.Is(err, domain.ErrNoAgentAvailable) {
+		logger.Error("service: failed to reassign declined shipment", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+	}
+	return shipment, nil
+}
+
+func (s *shipmentService) UpdateJobStatus(ctx context.Context, userID string, shipmentID string, to domain.ShipmentStatus, note string) (*domain.Shipment, error) {
+	if !agentStatuses[to] {
+		return nil, fmt.Errorf("service: %w: agents cannot set %q", domain.ErrInvalidShipmentTransition, to)
+	}
+	shipment, err := s.agentTransition(ctx, userID, shipmentID, to, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == domain.ShipmentOutForDelivery && !shipment.IsReverse() {
+		s.issueDeliveryOTP(ctx, shipment)
+	}
+	return shipment, nil
+}
+
+// ConfirmDelivery closes a shipment once the agent presents the code the buyer
+// was sent. A photo, if the agent took one, is kept as proof of delivery.
+// Returns taken back to a seller need no code; the seller confirms receipt
+// with the order service instead.
+func (s *shipmentService) ConfirmDelivery(ctx context.Context, userID string, shipmentID string, otp string, note string, photo *ProofPhoto) (*domain.Shipment, error) {
+	agent, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent: %w", err)
+	}
+
+	shipment, err := s.shipmentRepo.GetByPublicID(ctx, shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get shipment: %w", err)
+	}
+
+	if !shipment.IsReverse() {
+		if otp == "" {
+			return nil, fmt.Errorf("service: %w: the buyer's code is required", domain.ErrInvalidDeliveryOTP)
+		}
+		err = s.shipmentRepo.CheckDeliveryOTP(ctx, shipmentID, agent.ID, utils.HashOTP(otp))
+		if err != nil {
+			return nil, fmt.Errorf("service: delivery not confirmed: %w", err)
+		}
+	} else if shipment.AgentID == nil || *shipment.AgentID != agent.ID {
+		return nil, fmt.Errorf("service: %w: %s", domain.ErrShipmentNotFound, shipmentID)
+	} else if !shipment.Status.CanTransitionTo(domain.ShipmentDelivered) {
+		return nil, fmt.Errorf("service: %w: %s -> %s", domain.ErrInvalidShipmentTransition, shipment.Status, domain.ShipmentDelivered)
+	}
+
+	if photo != nil {
+		url, innerErr := s.mediaClient.UploadImage(ctx, "proof-of-delivery/"+shipmentID, photo.Filename, photo.Content)
+		if innerErr != nil {
+			return nil, fmt.Errorf("service: failed to upload proof of delivery: %w", innerErr)
+		}
+		if innerErr = s.shipmentRepo.SetProofOfDelivery(ctx, shipment.ID, url); innerErr != nil {
+			return nil, fmt.Errorf("service: %w", innerErr)
+		}
+	}
+
+	if note == "" && shipment.IsReverse() {
+		note = "returned to seller"
+	} else if note == "" {
+		note = "delivered to buyer"
+	}
+	return s.agentTransition(ctx, userID, shipmentID, domain.ShipmentDelivered, note)
+}
+
+// RecordCheckpoint stores where the agent is and pushes it to anyone following
+// the order.
+func (s *shipmentService) RecordCheckpoint(ctx context.Context, userID string, shipmentID string, latitude float64, longitude float64, note string) (*domain.Checkpoint, error) {
+	agent, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent: %w", err)
+	}
+
+	checkpoint := &domain.Checkpoint{
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Note:       note,
+		RecordedAt: time.Now(),
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		shipment, innerErr := s.shipmentRepo.WithTx(tx).AddCheckpoint(ctx, shipmentID, agent.ID, checkpoint)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		return enqueueEvent(ctx, tx, events.ShipmentCheckpointKey, events.ShipmentCheckpoint{
+			ShipmentID:    shipment.PublicID,
+			FulfillmentID: shipment.FulfillmentID,
+			OrderID:       shipment.OrderID,
+			UserID:        shipment.UserID,
+			Status:        string(shipment.Status),
+			Latitude:      checkpoint.Latitude,
+			Longitude:     checkpoint.Longitude,
+			Note:          checkpoint.Note,
+			OccurredAt:    checkpoint.RecordedAt,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to record checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// Track looks a shipment up by its tracking number, which is its public ID.
+func (s *shipmentService) Track(ctx context.Context, trackingNumber string) (*domain.Tracking, error) {
+	shipment, history, err := s.GetShipment(ctx, trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := make([]domain.TrackingEvent, 0, len(history))
+	for _, event := range history {
+		timeline = append(timeline, domain.TrackingEvent{Status: event.Status, CreatedAt: event.CreatedAt})
+	}
+
+	return &domain.Tracking{
+		TrackingNumber: shipment.PublicID,
+		OrderID:        shipment.OrderID,
+		Status:         shipment.Status,
+		DeliveryCity:   shipment.DeliveryCity,
+		DeliveryState:  shipment.DeliveryState,
+		PickedUpAt:     shipment.PickedUpAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		Timeline:       timeline,
+	}, nil
+}
+
+// issueDeliveryOTP sends the buyer a fresh code for this delivery attempt.
+// Without it the agent cannot mark the shipment delivered, so failures are
+// logged loudly; the agent can report the attempt failed and go out again.
+func (s *shipmentService) issueDeliveryOTP(ctx context.Context, shipment *domain.Shipment) {
+	otp, err := utils.GetOTP()
+	if err != nil {
+		logger.Error("service: CRITICAL - could not generate delivery otp", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+		return
+	}
+
+	err = s.shipmentRepo.SetDeliveryOTP(ctx, shipment.ID, utils.HashOTP(otp))
+	if err != nil {
+		logger.Error("service: CRITICAL - could not store delivery otp", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+		return
+	}
+
+	if shipment.RecipientEmail == "" {
+		logger.Error("service: CRITICAL - no buyer email to send delivery otp to", zap.String("shipment_id", shipment.PublicID))
+		return
+	}
+
+	err = s.emailClient.SendDeliveryOTPEmail(ctx, client.DeliveryOTPEmail{
+		To:            shipment.RecipientEmail,
+		RecipientName: shipment.RecipientName,
+		OrderID:       shipment.OrderID,
+		OTP:           otp,
+	})
+	if err != nil {
+		logger.Error("service: CRITICAL - could not email delivery otp", zap.String("shipment_id", shipment.PublicID), zap.Error(err))
+	}
+}
+
+func (s *shipmentService) agentTransition(ctx context.Context, userID string, shipmentID string, to domain.ShipmentStatus, note string) (*domain.Shipment, error) {
+	agent, err := s.agentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get agent: %w", err)
+	}
+
+	shipment, err := s.announce(ctx, note, func(shipmentRepo repository.ShipmentRepository) (*domain.Shipment, error) {
+		return shipmentRepo.TransitionShipment(ctx, shipmentID, &agent.ID, to, domain.ActorAgent, note)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update shipment: %w", err)
+	}
+	return shipment, nil
+}
+
+func (s *shipmentService) ListShipments(ctx context.Context, status domain.ShipmentStatus, page int, limit int) ([]domain.Shipment, error) {
+	shipments, err := s.shipmentRepo.ListShipments(ctx, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+func (s *shipmentService) GetShipment(ctx context.Context, shipmentID string) (*domain.Shipment, []domain.ShipmentEvent, error) {
+	shipment, err := s.shipmentRepo.GetByPublicID(ctx, shipmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service: failed to get shipment: %w", err)
+	}
+
+	history, err := s.shipmentRepo.GetEvents(ctx, shipment.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service: failed to get shipment history: %w", err)
+	}
+	return shipment, history, nil
+}
+
+// Assign lets operations place a shipment by hand. With an empty agent ID the
+// assignment engine picks, as it would for a new shipment.
+func (s *shipmentService) Assign(ctx context.Context, shipmentID string, agentPublicID string) (*domain.Shipment, error) {
+	shipment, err := s.announce(ctx, "", func(shipmentRepo repository.ShipmentRepository) (*domain.Shipment, error) {
+		return shipmentRepo.AssignShipment(ctx, shipmentID, agentPublicID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to assign shipment: %w", err)
+	}
+	return shipment, nil
+}
+
+// announce runs update and queues the status event for the shipment it
+// returns in the same transaction, so the order service hears of every change
+// that sticks and of nothing else.
+func (s *shipmentService) announce(ctx context.Context, note string, update func(shipmentRepo repository.ShipmentRepository) (*domain.Shipment, error)) (*domain.Shipment, error) {
+	var shipment *domain.Shipment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var innerErr error
+		shipment, innerErr = update(s.shipmentRepo.WithTx(tx))
+		if innerErr != nil {
+			return innerErr
+		}
+		return enqueueEvent(ctx, tx, events.ShipmentEventKey(string(shipment.Status)), shipmentStatusEvent(shipment, note))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// enqueueEvent saves a shipment event to the outbox in tx, to be published
+// once tx commits.
+func enqueueEvent(ctx context.Context, tx *gorm.DB, eventType string, data events.Event) error {
+	event, err := events.New(ctx, domain.EventProducer, eventType, data)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(tx, events.LogisticsEventsExchange, event)
+}
+
+func shipmentStatusEvent(shipment *domain.Shipment, note string) events.ShipmentStatusChanged {
+	return events.ShipmentStatusChanged{
+		ShipmentID:    shipment.PublicID,
+		FulfillmentID: shipment.FulfillmentID,
+		ReturnID:      shipment.ReturnID,
+		OrderID:       shipment.OrderID,
+		UserID:        shipment.UserID,
+		Status:        string(shipment.Status),
+		Note:          note,
+		OccurredAt:    time.Now(),
+	}
+}
+
+func shipmentItems(items []events.Item) []domain.ShipmentItem {
+	shipmentItems := make([]domain.ShipmentItem, 0, len(items))
+	for _, item := range items {
+		shipmentItems = append(shipmentItems, domain.ShipmentItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return shipmentItems
+}
+
+func joinAddress(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
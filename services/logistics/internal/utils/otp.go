@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sixafter/nanoid"
+)
+
+var otpGenerator nanoid.Interface
+
+func InitOTPGenerator(length int) error {
+	const digits = "0123456789"
+	var err error
+	otpGenerator, err = nanoid.NewGenerator(
+		nanoid.WithAlphabet(digits),
+		nanoid.WithLengthHint(uint16(length)))
+	if err != nil {
+		return fmt.Errorf("utils: failed to create OTP generator: %w", err)
+	}
+
+	return nil
+}
+
+func GetOTP() (string, error) {
+	otp, err := otpGenerator.New()
+	if err != nil {
+		return "", fmt.Errorf("utils: failed to generate OTP: %w", err)
+	}
+
+	return otp.String(), nil
+}
+
+// HashOTP is what a delivery code is stored and compared as.
+func HashOTP(otp string) string {
+	sum := sha256.Sum256([]byte(otp))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/logistics/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// AssignmentWorker takes back offers agents have sat on and keeps offering
+// unassigned shipments until someone in the area is free.
+type AssignmentWorker struct {
+	shipmentService service.ShipmentService
+	interval        time.Duration
+	offerTTL        time.Duration
+}
+
+func NewAssignmentWorker(shipmentService service.ShipmentService, interval time.Duration, offerTTL time.Duration) *AssignmentWorker {
+	return &AssignmentWorker{shipmentService: shipmentService, interval: interval, offerTTL: offerTTL}
+}
+
+func (w *AssignmentWorker) StartAssignmentWorker(ctx context.Context) {
+	logger.Info("worker: Assignment worker started", zap.Duration("interval", w.interval), zap.Duration("offer_ttl", w.offerTTL))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Assignment worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			expired, err := w.shipmentService.ExpireOffers(ctx, w.offerTTL)
+			if err != nil {
+				logger.Error("worker: failed to expire stale offers", zap.Error(err))
+			} else if expired > 0 {
+				logger.Info("worker: expired unanswered shipment offers", zap.Int("shipment_count", expired))
+			}
+
+			assigned, err := w.shipmentService.AssignPending(ctx)
+			if err != nil {
+				logger.Error("worker: failed to assign pending shipments", zap.Error(err))
+				continue
+			}
+			if assigned > 0 {
+				logger.Info("worker: assigned pending shipments", zap.Int("shipment_count", assigned))
+			}
+		}
+	}
+}
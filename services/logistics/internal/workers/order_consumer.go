@@ -0,0 +1,118 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/inbox"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/logistics/internal/domain"
+	"ecommerce/services/logistics/internal/service"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OrderConsumer struct {
+	rabbitMQ    *broker.RabbitMQClient
+	shipmentSvc service.ShipmentService
+	inbox       *inbox.Inbox
+}
+
+func NewOrderConsumer(rabbitMQ *broker.RabbitMQClient, shipmentSvc service.ShipmentService, inbox *inbox.Inbox) *OrderConsumer {
+	return &OrderConsumer{rabbitMQ: rabbitMQ, shipmentSvc: shipmentSvc, inbox: inbox}
+}
+
+func (c *OrderConsumer) StartListening(ctx context.Context) error {
+	consumer := broker.NewConsumer(c.rabbitMQ, broker.ConsumerConfig{
+		Exchange: events.OrderEventsExchange,
+		Queue:    "logistics_service_order_queue",
+	})
+
+	broker.Handle(consumer, events.FulfillmentReadyKey, c.processFulfillment)
+	broker.Handle(consumer, events.ReturnApprovedKey, c.processReturn)
+	broker.Handle(consumer, events.OrderCancelledKey, c.processCancellation)
+
+	logger.Info("worker: Logistics Service is now listening for order events...")
+
+	err := consumer.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("worker: failed to consume order events: %w", err)
+	}
+
+	logger.Info("worker: Shutting down order consumer gracefully...")
+	return nil
+}
+
+func (c *OrderConsumer) processFulfillment(ctx context.Context, msg broker.Message, payload events.FulfillmentStatusChanged) error {
+	if payload.FulfillmentID == "" {
+		return broker.Permanent(errors.New("worker: fulfillment ready event has no fulfillment id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		_, innerErr := c.shipmentSvc.WithTx(tx).CreateFromFulfillment(ctx, payload)
+		return innerErr
+	})
+	// An undeliverable address will not fix itself, so the event is parked
+	// for someone to look at.
+	if errors.Is(err, domain.ErrInvalidPincode) {
+		return broker.Permanent(fmt.Errorf("worker: fulfillment %s has an undeliverable pincode %s: %w", payload.FulfillmentID, payload.ShippingZip, err))
+	}
+	if err != nil {
+		return fmt.Errorf("worker: failed to create shipment for fulfillment %s: %w", payload.FulfillmentID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed fulfillment", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: opened shipment for fulfillment", zap.String("fulfillment_id", payload.FulfillmentID))
+	return nil
+}
+
+func (c *OrderConsumer) processReturn(ctx context.Context, msg broker.Message, payload events.ReturnStatusChanged) error {
+	if payload.ReturnID == "" {
+		return broker.Permanent(errors.New("worker: return approved event has no return id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		_, innerErr := c.shipmentSvc.WithTx(tx).CreateReturnPickup(ctx, payload)
+		return innerErr
+	})
+	if errors.Is(err, domain.ErrInvalidPincode) {
+		return broker.Permanent(fmt.Errorf("worker: return %s has an unserviceable pickup pincode %s: %w", payload.ReturnID, payload.PickupZip, err))
+	}
+	if err != nil {
+		return fmt.Errorf("worker: failed to create return pickup for %s: %w", payload.ReturnID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed return", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: opened return pickup", zap.String("return_id", payload.ReturnID))
+	return nil
+}
+
+func (c *OrderConsumer) processCancellation(ctx context.Context, msg broker.Message, payload events.OrderCancelled) error {
+	if payload.OrderID == "" {
+		return broker.Permanent(errors.New("worker: order cancelled event has no order id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		return c.shipmentSvc.WithTx(tx).CancelOrderShipments(ctx, payload.OrderID, payload.Reason)
+	})
+	if err != nil {
+		return fmt.Errorf("worker: failed to cancel shipments for order %s: %w", payload.OrderID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed order cancellation", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: cancelled shipments for order", zap.String("order_id", payload.OrderID))
+	return nil
+}
@@ -168,6 +168,104 @@ func (h *MediaHandler) DeleteImage(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
 }
 
+// UploadDocument godoc
+// @Summary      Upload a generated document
+// @Description  Internal endpoint for other services to store generated PDFs such as invoices. Maximum file size is 10MB.
+// @Tags         Internal
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Internal-Token  header    string  true   "Shared service token"
+// @Param        file              formData  file    true   "The PDF to upload (Max 10MB)"
+// @Param        folder            formData  string  false  "Target folder name (default: 'documents')"
+// @Success      201     {object}  map[string]interface{} "Document uploaded successfully with URL"
+// @Failure      400     {object}  map[string]interface{} "Bad request (file too large, no file provided)"
+// @Failure      401     {object}  map[string]interface{} "Unauthorized (missing or invalid service token)"
+// @Failure      415     {object}  map[string]interface{} "Unsupported media type"
+// @Failure      500     {object}  map[string]interface{} "Internal server error"
+// @Router       /internal/documents [post]
+func (h *MediaHandler) UploadDocument(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
+	err := c.Request.ParseMultipartForm(10 << 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is too large. Maximum size is 10MB."})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		return
+	}
+
+	folder := c.DefaultPostForm("folder", "documents")
+
+	url, err := h.mediaService.UploadDocument(c.Request.Context(), file, folder)
+	if err != nil {
+		if err.Error() == "service: invalid file type, only pdf documents are allowed" {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Only PDF documents are allowed"})
+			return
+		}
+
+		logger.Error("handler: failed to upload document: ", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload document"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Document uploaded successfully",
+		"url":     url,
+	})
+}
+
+// UploadInternalImage godoc
+// @Summary      Upload an image for another service
+// @Description  Internal endpoint for other services to store images such as proof of delivery photos. Maximum file size is 5MB.
+// @Tags         Internal
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        X-Internal-Token  header    string  true   "Shared service token"
+// @Param        file              formData  file    true   "The image to upload (Max 5MB)"
+// @Param        folder            formData  string  false  "Target folder name (default: 'general')"
+// @Success      201     {object}  map[string]interface{} "Image uploaded successfully with URL"
+// @Failure      400     {object}  map[string]interface{} "Bad request (file too large, no file provided)"
+// @Failure      401     {object}  map[string]interface{} "Unauthorized (missing or invalid service token)"
+// @Failure      415     {object}  map[string]interface{} "Unsupported media type"
+// @Failure      500     {object}  map[string]interface{} "Internal server error"
+// @Router       /internal/images [post]
+func (h *MediaHandler) UploadInternalImage(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 5<<20)
+	err := c.Request.ParseMultipartForm(5 << 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is too large. Maximum size is 5MB."})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		return
+	}
+
+	folder := c.DefaultPostForm("folder", "general")
+
+	url, err := h.mediaService.UploadImage(c.Request.Context(), file, folder)
+	if err != nil {
+		if err.Error() == "service: invalid file type, only images are allowed" {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Only image files (jpeg, png, webp) are allowed"})
+			return
+		}
+
+		logger.Error("handler: failed to upload image: ", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Image uploaded successfully",
+		"url":     url,
+	})
+}
+
 // HealthCheck godoc
 // @Summary      Health check the server
 // @Description  Use this to check if the Media service is active and running.
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"ecommerce/pkg/middleware"
+
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -24,4 +26,11 @@ func RegisterRoutes(router *gin.Engine, mediaHandler *MediaHandler) {
 		seller.POST("/upload-multiple", mediaHandler.UploadMultipleImages)
 		seller.DELETE("/image", mediaHandler.DeleteImage)
 	}
+
+	internal := v1.Group("/internal")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		internal.POST("/documents", mediaHandler.UploadDocument)
+		internal.POST("/images", mediaHandler.UploadInternalImage)
+	}
 }
@@ -19,6 +19,7 @@ type MediaService interface {
 	UploadImage(ctx context.Context, fileHeader *multipart.FileHeader, folder string) (string, error)
 	DeleteImage(ctx context.Context, fileUrl string) error
 	UploadImages(ctx context.Context, fileHeaders []*multipart.FileHeader, folder string) ([]string, []string)
+	UploadDocument(ctx context.Context, fileHeader *multipart.FileHeader, folder string) (string, error)
 }
 
 type mediaService struct {
@@ -98,3 +99,42 @@ func (s *mediaService) DeleteImage(ctx context.Context, fileUrl string) error {
 
 	return nil
 }
+
+// UploadDocument stores a PDF generated by another service, e.g. an invoice.
+// The original file name is kept so the object key stays meaningful.
+func (s *mediaService) UploadDocument(ctx context.Context, fileHeader *multipart.FileHeader, folder string) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("service: failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, 512)
+	_, err = file.Read(buffer)
+	if err != nil {
+		return "", fmt.Errorf("service: failed to read file header: %w", err)
+	}
+
+	_, err = file.Seek(0, 0)
+	if err != nil {
+		return "", fmt.Errorf("service: failed to reset file pointer: %w", err)
+	}
+
+	contentType := http.DetectContentType(buffer)
+	if contentType != "application/pdf" {
+		return "", errors.New("service: invalid file type, only pdf documents are allowed")
+	}
+
+	id, _ := nanoid.New()
+	name := strings.TrimSuffix(filepath.Base(fileHeader.Filename), filepath.Ext(fileHeader.Filename))
+
+	// e.g., "invoices/ORD-1a2b3c4d/INV-27-2026-27-000042_nano123.pdf"
+	objectKey := fmt.Sprintf("%s/%s_%s.pdf", folder, name, id)
+
+	url, err := s.s3Storage.Upload(ctx, file, objectKey, contentType)
+	if err != nil {
+		return "", fmt.Errorf("service: failed to upload file: %w", err)
+	}
+
+	return url, nil
+}
@@ -6,14 +6,16 @@ import (
 	"log"
 	"os"
 
+	"ecommerce/pkg/money"
+
 	"github.com/go-redis/redis/v8"
 	"github.com/joho/godotenv"
 )
 
 type CartItem struct {
-	ProductVariantID string  `json:"product_variant_id"`
-	Quantity         int     `json:"quantity"`
-	Price            float64 `json:"price"`
+	ProductVariantID string      `json:"product_variant_id"`
+	Quantity         int         `json:"quantity"`
+	Price            money.Money `json:"price"`
 }
 
 type Cart struct {
@@ -47,7 +49,7 @@ func main() {
 			{
 				ProductVariantID: "var_test_123",
 				Quantity:         2,
-				Price:            1.00,
+				Price:            money.MustParse("1.00", money.DefaultCurrency),
 			},
 		},
 	}
@@ -10,21 +10,29 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
 
 	"ecommerce/pkg/broker"
 	"ecommerce/pkg/database"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/fx"
+	"ecommerce/pkg/inbox"
 	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"ecommerce/pkg/outbox"
 	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/handler"
 	"ecommerce/services/order/internal/repository"
+	"ecommerce/services/order/internal/returns"
 	"ecommerce/services/order/internal/service"
+	"ecommerce/services/order/internal/tax"
+	"ecommerce/services/order/internal/tracking"
 
 	pb "ecommerce/pkg/protobufs/catalog"
 
@@ -67,13 +75,41 @@ func main() {
 	err = pg.DB.AutoMigrate(
 		&domain.Order{},
 		&domain.OrderItem{},
+		&domain.Fulfillment{},
+		&domain.Return{},
+		&domain.ReturnItem{},
+		&domain.OrderStatusHistory{},
 		&domain.CustomerProfile{},
 		&domain.Address{},
+		&domain.CheckoutSaga{},
+		&domain.Invoice{},
+		&domain.InvoiceSequence{},
+		&domain.CommissionRule{},
+		&fx.ExchangeRate{},
+		&inbox.ProcessedEvent{},
+		&outbox.OutboxEvent{},
 	)
 	if err != nil {
 		logger.Fatal("Failed to migrate database", zap.Error(err))
 	}
 
+	for _, legacy := range []struct{ table, column, prefix string }{
+		{"orders", "total_amount", "total_"},
+		{"order_items", "price", "price_"},
+	} {
+		err = database.MigrateDecimalMoney(pg.DB, legacy.table, legacy.column, legacy.prefix+"amount_minor", legacy.prefix+"currency", money.DefaultCurrency, 100)
+		if err != nil {
+			logger.Fatal("Failed to migrate order amounts", zap.String("table", legacy.table), zap.Error(err))
+		}
+	}
+
+	// Orders placed before multi-currency checkout were charged in the catalog
+	// currency at a rate of one.
+	err = pg.DB.Exec("UPDATE orders SET base_total_amount_minor = total_amount_minor, base_total_currency = total_currency WHERE base_total_amount_minor = 0 AND total_amount_minor <> 0").Error
+	if err != nil {
+		logger.Fatal("Failed to backfill order base totals", zap.Error(err))
+	}
+
 	err = utils.GetPublicKey()
 	if err != nil {
 		logger.Fatal("Failed to get public key", zap.Error(err))
@@ -82,6 +118,11 @@ func main() {
 	cartRepo := repository.NewCartRepository(rd.Redis)
 	customerRepo := repository.NewCustomerRepository(pg.DB)
 	orderRepo := repository.NewOrderRepository(pg.DB)
+	sagaRepo := repository.NewSagaRepository(pg.DB)
+	invoiceRepo := repository.NewInvoiceRepository(pg.DB)
+	fulfillmentRepo := repository.NewFulfillmentRepository(pg.DB)
+	returnRepo := repository.NewReturnRepository(pg.DB)
+	commissionRepo := repository.NewCommissionRepository(pg.DB)
 
 	catalogGrpcURL := os.Getenv("CATALOG_GRPC_URL")
 	if catalogGrpcURL == "" {
@@ -106,7 +147,33 @@ func main() {
 	}
 	defer paymentClient.Close()
 
-	cartSvc := service.NewCartService(cartRepo)
+	var rates fx.RateSource
+	if ratesFile := os.Getenv("FX_RATES_FILE"); ratesFile != "" {
+		rates, err = fx.LoadFile(ratesFile)
+		if err != nil {
+			logger.Fatal("Failed to load exchange rates", zap.String("file", ratesFile), zap.Error(err))
+		}
+	} else {
+		rates = fx.NewDBSource(pg.DB, 5*time.Minute)
+	}
+
+	cartSvc := service.NewCartService(cartRepo, rates)
+
+	taxSlabs, err := tax.NewSlabs(tax.DefaultRateBps, nil)
+	if slabsFile := os.Getenv("TAX_SLABS_FILE"); slabsFile != "" {
+		taxSlabs, err = tax.LoadSlabs(slabsFile)
+	}
+	if err != nil {
+		logger.Fatal("Failed to load GST slabs", zap.Error(err))
+	}
+
+	returnPolicy, err := returns.NewPolicy(returns.DefaultWindowDays, nil)
+	if policyFile := os.Getenv("RETURN_WINDOWS_FILE"); policyFile != "" {
+		returnPolicy, err = returns.LoadPolicy(policyFile)
+	}
+	if err != nil {
+		logger.Fatal("Failed to load return windows", zap.Error(err))
+	}
 
 	rabbitMQURL := os.Getenv("RABBIT_MQ_URL")
 	if rabbitMQURL == "" {
@@ -120,31 +187,73 @@ func main() {
 
 	defer rabbitMQ.Close()
 
-	err = rabbitMQ.DeclareExchange("user_events", "topic")
+	err = rabbitMQ.DeclareExchange(events.UserEventsExchange, "topic")
+	if err != nil {
+		log.Fatalf("Failed to declare exchange: %v", err)
+	}
+
+	err = rabbitMQ.DeclareExchange(events.OrderEventsExchange, "topic")
 	if err != nil {
 		log.Fatalf("Failed to declare exchange: %v", err)
 	}
 
-	customerSvc := service.NewCustomerService(customerRepo, rabbitMQ)
+	customerSvc := service.NewCustomerService(customerRepo, pg.DB)
 
-	orderSvc, err := service.NewOrderService(orderRepo, cartRepo, catalogClient, paymentClient)
+	commissionSvc, err := service.NewCommissionService(commissionRepo)
 	if err != nil {
-		logger.Fatal("Failed to initialize order service", zap.Error(err))
+		logger.Fatal("Failed to create commission service", zap.Error(err))
 	}
 
-	rabbitConn, err := amqp.Dial(rabbitMQURL)
+	defaultCommissionBps, err := strconv.ParseInt(os.Getenv("PLATFORM_COMMISSION_BPS"), 10, 64)
 	if err != nil {
-		logger.Fatal("Failed to dial RabbitMQ for consumer", zap.Error(err))
+		defaultCommissionBps = 1000
 	}
-	defer rabbitConn.Close()
+	if err = commissionSvc.SeedDefaultRule(ctx, defaultCommissionBps); err != nil {
+		logger.Fatal("Failed to seed default commission rule", zap.Error(err))
+	}
+
+	checkoutOrchestrator := service.NewCheckoutOrchestrator(sagaRepo, orderRepo, catalogClient, paymentClient, rates, tax.NewEngine(taxSlabs), commissionSvc)
 
-	rabbitChannel, err := rabbitConn.Channel()
+	orderSvc, err := service.NewOrderService(orderRepo, cartRepo, catalogClient, checkoutOrchestrator, pg.DB)
 	if err != nil {
-		logger.Fatal("Failed to open RabbitMQ channel", zap.Error(err))
+		logger.Fatal("Failed to initialize order service", zap.Error(err))
 	}
-	defer rabbitChannel.Close()
 
-	paymentConsumer := workers.NewPaymentConsumer(rabbitChannel, orderSvc, cartRepo)
+	fulfillmentSvc := service.NewFulfillmentService(fulfillmentRepo, catalogClient, returnPolicy, pg.DB)
+
+	mediaServiceURL := os.Getenv("MEDIA_SERVICE_URL")
+	if mediaServiceURL == "" {
+		mediaServiceURL = "http://localhost:8083/api/v1/media"
+	}
+
+	emailServiceURL := os.Getenv("EMAIL_SERVICE_URL")
+	if emailServiceURL == "" {
+		emailServiceURL = "http://localhost:8081/api/v1/email"
+	}
+
+	mediaClient := client.NewMediaClient(mediaServiceURL, os.Getenv("INTERNAL_API_TOKEN"))
+	emailClient := client.NewEmailClient(emailServiceURL, os.Getenv("INTERNAL_API_TOKEN"))
+
+	invoiceSvc := service.NewInvoiceService(invoiceRepo, orderRepo, catalogClient, mediaClient, emailClient)
+
+	returnSvc, err := service.NewReturnService(returnRepo, orderRepo, catalogClient, mediaClient, returnPolicy, pg.DB)
+	if err != nil {
+		logger.Fatal("Failed to initialize return service", zap.Error(err))
+	}
+
+	invoiceWorker := workers.NewInvoiceWorker(invoiceSvc, 5*time.Minute)
+	go invoiceWorker.StartInvoiceWorker(ctx)
+
+	stockWorker := workers.NewStockWorker(orderSvc, time.Minute)
+	go stockWorker.StartStockWorker(ctx)
+
+	sagaRecoveryWorker := workers.NewSagaRecoveryWorker(checkoutOrchestrator, time.Minute, 2*time.Minute)
+	go sagaRecoveryWorker.StartSagaRecoveryWorker(ctx)
+
+	outboxRelay := outbox.NewRelay(pg.DB, rabbitMQ, 2*time.Second, outbox.DefaultMaxAttempts)
+	go outboxRelay.Start(ctx)
+
+	paymentConsumer := workers.NewPaymentConsumer(rabbitMQ, orderSvc, invoiceSvc, cartRepo, inbox.New(pg.DB, "order_payment_events"))
 
 	go func() {
 		logger.Info("Starting Payment RabbitMQ Consumer...")
@@ -153,12 +262,39 @@ func main() {
 		}
 	}()
 
-	cartHandler := handler.NewCartHandler(cartSvc)
-	customerHandler := handler.NewCustomerHandler(customerSvc)
-	orderHandler := handler.NewOrderHandler(orderSvc)
+	logisticsConsumer := workers.NewLogisticsConsumer(rabbitMQ, orderSvc, fulfillmentSvc, returnSvc, inbox.New(pg.DB, "order_logistics_events"))
+
+	go func() {
+		logger.Info("Starting Logistics RabbitMQ Consumer...")
+		if err := logisticsConsumer.StartListening(ctx); err != nil {
+			logger.Error("Logistics consumer stopped unexpectedly", zap.Error(err))
+		}
+	}()
+
+	trackingHub := tracking.NewHub(16)
+
+	trackingConsumer := workers.NewTrackingConsumer(rabbitMQ, trackingHub)
+
+	go func() {
+		logger.Info("Starting Tracking RabbitMQ Consumer...")
+		if err := trackingConsumer.StartListening(ctx); err != nil {
+			logger.Error("Tracking consumer stopped unexpectedly", zap.Error(err))
+		}
+	}()
 
 	router := gin.Default()
-	handler.RegisterRoutes(router, cartHandler, customerHandler, orderHandler)
+	handler.RegisterRoutes(router, handler.Handlers{
+		Cart:               handler.NewCartHandler(cartSvc),
+		Customer:           handler.NewCustomerHandler(customerSvc),
+		Order:              handler.NewOrderHandler(orderSvc, invoiceSvc),
+		Fulfillment:        handler.NewFulfillmentHandler(fulfillmentSvc),
+		Tracking:           handler.NewTrackingHandler(orderSvc, trackingHub),
+		Return:             handler.NewReturnHandler(returnSvc),
+		Balance:            handler.NewBalanceHandler(paymentClient),
+		Commission:         handler.NewCommissionHandler(commissionSvc),
+		Outbox:             outbox.NewStore(pg.DB),
+		FulfillmentService: fulfillmentSvc,
+	})
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"ecommerce/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type InvoiceEmail struct {
+	To            string `json:"to"`
+	BuyerName     string `json:"buyer_name"`
+	OrderID       string `json:"order_id"`
+	InvoiceNumber string `json:"invoice_number"`
+	SellerName    string `json:"seller_name"`
+	Total         string `json:"total"`
+	Filename      string `json:"filename"`
+	Attachment    []byte `json:"attachment"`
+}
+
+type EmailClient interface {
+	SendInvoiceEmail(ctx context.Context, email InvoiceEmail) error
+}
+
+type emailClient struct {
+	baseUrl       string
+	internalToken string
+	client        *http.Client
+}
+
+func NewEmailClient(baseUrl string, internalToken string) EmailClient {
+	return &emailClient{
+		baseUrl:       baseUrl,
+		internalToken: internalToken,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (e *emailClient) SendInvoiceEmail(ctx context.Context, email InvoiceEmail) error {
+	body, err := json.Marshal(email)
+	if err != nil {
+		return fmt.Errorf("client: failed to marshal payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/invoice-email", e.baseUrl)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("client: failed to create invoice email request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Internal-Token", e.internalToken)
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("client: failed to send invoice email: %w", err)
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error("client: failed to close response body: ", zap.Error(err))
+		}
+	}(response.Body)
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("client: failed to send invoice email: %s", response.Status)
+	}
+
+	return nil
+}
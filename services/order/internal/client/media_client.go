@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"ecommerce/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type MediaClient interface {
+	UploadDocument(ctx context.Context, folder string, filename string, content []byte) (string, error)
+	UploadImage(ctx context.Context, folder string, filename string, content []byte) (string, error)
+}
+
+type mediaClient struct {
+	baseUrl       string
+	internalToken string
+	client        *http.Client
+}
+
+func NewMediaClient(baseUrl string, internalToken string) MediaClient {
+	return &mediaClient{
+		baseUrl:       baseUrl,
+		internalToken: internalToken,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (m *mediaClient) UploadDocument(ctx context.Context, folder string, filename string, content []byte) (string, error) {
+	return m.upload(ctx, "documents", folder, filename, content)
+}
+
+func (m *mediaClient) UploadImage(ctx context.Context, folder string, filename string, content []byte) (string, error) {
+	return m.upload(ctx, "images", folder, filename, content)
+}
+
+// upload posts a file to one of the media service's internal upload routes,
+// /internal/documents or /internal/images, and returns its public URL.
+func (m *mediaClient) upload(ctx context.Context, kind string, folder string, filename string, content []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	err := form.WriteField("folder", folder)
+	if err != nil {
+		return "", fmt.Errorf("client: failed to build upload form: %w", err)
+	}
+
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("client: failed to build upload form: %w", err)
+	}
+	if _, err = part.Write(content); err != nil {
+		return "", fmt.Errorf("client: failed to build upload form: %w", err)
+	}
+	if err = form.Close(); err != nil {
+		return "", fmt.Errorf("client: failed to build upload form: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/internal/%s", m.baseUrl, kind)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return "", fmt.Errorf("client: failed to create upload request: %w", err)
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	request.Header.Set("X-Internal-Token", m.internalToken)
+
+	response, err := m.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("client: failed to upload to %s: %w", kind, err)
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Error("client: failed to close response body: ", zap.Error(err))
+		}
+	}(response.Body)
+
+	if response.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("client: failed to upload to %s: %s", kind, response.Status)
+	}
+
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("client: failed to decode upload response: %w", err)
+	}
+
+	return result.URL, nil
+}
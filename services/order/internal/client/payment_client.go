@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
 	pb "ecommerce/pkg/protobufs/payment"
 
 	"go.uber.org/zap"
@@ -14,10 +15,20 @@ import (
 )
 
 type PaymentService interface {
-	InitiatePayment(ctx context.Context, orderID string, userID string, amount int64, currency string) (string, error)
+	InitiatePayment(ctx context.Context, orderID string, userID string, amount money.Money) (string, error)
+	CancelPayment(ctx context.Context, orderID string) error
+	GetSellerBalance(ctx context.Context, sellerID string) ([]SellerBalance, error)
 	Close() error
 }
 
+// SellerBalance is a seller's earnings in one currency, as tracked by the
+// payment service's ledger.
+type SellerBalance struct {
+	Pending   money.Money `json:"pending"`
+	Available money.Money `json:"available"`
+	PaidOut   money.Money `json:"paid_out"`
+}
+
 type paymentGRPCClient struct {
 	conn   *grpc.ClientConn
 	client pb.PaymentServiceClient
@@ -39,15 +50,14 @@ func NewPaymentClient(paymentServiceAddr string) (PaymentService, error) {
 	}, nil
 }
 
-func (p *paymentGRPCClient) InitiatePayment(ctx context.Context, orderID string, userID string, amount int64, currency string) (string, error) {
+func (p *paymentGRPCClient) InitiatePayment(ctx context.Context, orderID string, userID string, amount money.Money) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	req := &pb.CreatePaymentRequest{
-		OrderId:  orderID,
-		UserId:   userID,
-		Amount:   amount,
-		Currency: currency,
+		OrderId: orderID,
+		UserId:  userID,
+		Amount:  amount.ToProto(),
 	}
 
 	res, err := p.client.CreatePaymentSession(ctx, req)
@@ -58,6 +68,38 @@ func (p *paymentGRPCClient) InitiatePayment(ctx context.Context, orderID string,
 	return res.PaymentUrl, nil
 }
 
+func (p *paymentGRPCClient) CancelPayment(ctx context.Context, orderID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := p.client.CancelPaymentSession(ctx, &pb.CancelPaymentRequest{OrderId: orderID})
+	if err != nil {
+		return fmt.Errorf("client: gRPC call to cancel payment failed: %w", err)
+	}
+
+	return nil
+}
+
+func (p *paymentGRPCClient) GetSellerBalance(ctx context.Context, sellerID string) ([]SellerBalance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := p.client.GetSellerBalance(ctx, &pb.GetSellerBalanceRequest{SellerId: sellerID})
+	if err != nil {
+		return nil, fmt.Errorf("client: gRPC call to get seller balance failed: %w", err)
+	}
+
+	balances := make([]SellerBalance, 0, len(res.Balances))
+	for _, balance := range res.Balances {
+		balances = append(balances, SellerBalance{
+			Pending:   money.FromProto(balance.Pending),
+			Available: money.FromProto(balance.Available),
+			PaidOut:   money.FromProto(balance.PaidOut),
+		})
+	}
+	return balances, nil
+}
+
 func (p *paymentGRPCClient) Close() error {
 	return p.conn.Close()
 }
@@ -0,0 +1,113 @@
+// Package commission works out the platform's cut of each order line.
+//
+// A Rule charges a percentage of the line total plus a fixed fee per unit. It
+// applies to a category and everything beneath it, optionally to one seller
+// only, for a half-open range of time [EffectiveFrom, EffectiveTo). When
+// several rules cover a line, a seller's own rule beats a marketplace-wide one
+// and, after that, the most specific category wins.
+package commission
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+type Rule struct {
+	ID string
+	// CategoryPath is a catalog category path or an ancestor prefix of one;
+	// empty covers every category.
+	CategoryPath string
+	// SellerID limits the rule to one seller; empty covers every seller.
+	SellerID      string
+	RateBps       int64
+	FixedFee      money.Money
+	EffectiveFrom time.Time
+	// EffectiveTo is exclusive; nil leaves the rule open-ended.
+	EffectiveTo *time.Time
+}
+
+// NormalizePath trims the separators and spaces admins tend to leave on
+// category paths.
+func NormalizePath(path string) string {
+	return strings.Trim(strings.TrimSpace(path), ".")
+}
+
+func (r Rule) Validate() error {
+	if r.RateBps < 0 || r.RateBps > 10000 {
+		return fmt.Errorf("commission: invalid rate %d bps", r.RateBps)
+	}
+	if r.FixedFee.IsNegative() {
+		return fmt.Errorf("commission: fixed fee %s is negative", r.FixedFee)
+	}
+	if r.EffectiveFrom.IsZero() {
+		return fmt.Errorf("commission: rule needs a start date")
+	}
+	if r.EffectiveTo != nil && !r.EffectiveTo.After(r.EffectiveFrom) {
+		return fmt.Errorf("commission: rule must end after it starts")
+	}
+	return nil
+}
+
+func (r Rule) ActiveAt(t time.Time) bool {
+	if t.Before(r.EffectiveFrom) {
+		return false
+	}
+	return r.EffectiveTo == nil || t.Before(*r.EffectiveTo)
+}
+
+// Charge is the commission on a line of quantity units totalling lineTotal.
+// fee is the rule's fixed fee already converted to the order currency. The
+// charge never exceeds the line total.
+func (r Rule) Charge(lineTotal money.Money, quantity int, fee money.Money) (money.Money, error) {
+	charge, err := lineTotal.Percent(r.RateBps).Add(fee.Mul(int64(quantity)))
+	if err != nil {
+		return money.Money{}, fmt.Errorf("commission: %w", err)
+	}
+	if cmp, _ := charge.Cmp(lineTotal); cmp > 0 {
+		return lineTotal, nil
+	}
+	return charge, nil
+}
+
+// Schedule picks the rule for each order line out of a set of rules.
+type Schedule struct {
+	rules []Rule
+}
+
+func NewSchedule(rules []Rule) *Schedule {
+	return &Schedule{rules: rules}
+}
+
+// Find returns the rule covering a seller's line in categoryPath at time at.
+// ok is false when no rule applies.
+func (s *Schedule) Find(sellerID string, categoryPath string, at time.Time) (rule Rule, ok bool) {
+	path := NormalizePath(categoryPath)
+	bestScore := -1
+
+	for _, candidate := range s.rules {
+		if !candidate.ActiveAt(at) {
+			continue
+		}
+		if candidate.SellerID != "" && candidate.SellerID != sellerID {
+			continue
+		}
+
+		prefix := NormalizePath(candidate.CategoryPath)
+		if prefix != "" && path != prefix && !strings.HasPrefix(path, prefix+".") {
+			continue
+		}
+
+		score := len(prefix)
+		if candidate.SellerID != "" {
+			// A seller's rule outranks any marketplace rule, however specific.
+			score += 1 << 20
+		}
+		if score > bestScore {
+			rule, ok, bestScore = candidate, true, score
+		}
+	}
+	return rule, ok
+}
@@ -0,0 +1,93 @@
+package commission
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+var (
+	jan = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestScheduleFind(t *testing.T) {
+	schedule := NewSchedule([]Rule{
+		{ID: "all", RateBps: 500, EffectiveFrom: jan},
+		{ID: "fashion", CategoryPath: "cat_fashion", RateBps: 1500, EffectiveFrom: jan},
+		{ID: "shoes", CategoryPath: ".cat_fashion.cat_shoes.", RateBps: 1200, EffectiveFrom: jan},
+		{ID: "sel_1-all", SellerID: "sel_1", RateBps: 300, EffectiveFrom: jan},
+		{ID: "fashion-feb", CategoryPath: "cat_fashionista", RateBps: 2000, EffectiveFrom: feb, EffectiveTo: &mar},
+	})
+
+	tests := []struct {
+		seller string
+		path   string
+		at     time.Time
+		want   string
+	}{
+		{"sel_2", "cat_fashion.cat_shoes.cat_sneakers", jan, "shoes"},
+		{"sel_2", "cat_fashion.cat_shirts", jan, "fashion"},
+		{"sel_2", "cat_books", jan, "all"},
+		{"sel_1", "cat_fashion.cat_shoes", jan, "sel_1-all"},
+		{"sel_2", "cat_fashionista", feb, "fashion-feb"},
+		{"sel_2", "cat_fashionista", mar, "all"},
+	}
+	for _, tt := range tests {
+		rule, ok := schedule.Find(tt.seller, tt.path, tt.at)
+		if !ok || rule.ID != tt.want {
+			t.Errorf("Find(%s, %s, %s) = %q, %v; want %q", tt.seller, tt.path, tt.at.Format("Jan"), rule.ID, ok, tt.want)
+		}
+	}
+
+	if _, ok := NewSchedule(nil).Find("sel_1", "cat_books", jan); ok {
+		t.Error("expected no rule from an empty schedule")
+	}
+	if _, ok := schedule.Find("sel_2", "cat_books", jan.Add(-time.Hour)); ok {
+		t.Error("expected no rule before any takes effect")
+	}
+}
+
+func TestCharge(t *testing.T) {
+	rule := Rule{RateBps: 1000, FixedFee: money.New(2000, "INR")}
+
+	charge, err := rule.Charge(money.New(100000, "INR"), 2, money.New(2000, "INR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charge.Amount != 14000 {
+		t.Errorf("charge = %d, want 10%% of 100000 plus 2 x 2000", charge.Amount)
+	}
+
+	charge, err = rule.Charge(money.New(3000, "INR"), 3, money.New(2000, "INR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charge.Amount != 3000 {
+		t.Errorf("charge = %d, want it capped at the line total", charge.Amount)
+	}
+
+	if _, err = rule.Charge(money.New(3000, "INR"), 1, money.New(100, "USD")); err == nil {
+		t.Error("expected a fee in another currency to fail")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []Rule{
+		{RateBps: -1, EffectiveFrom: jan},
+		{RateBps: 10001, EffectiveFrom: jan},
+		{RateBps: 100, FixedFee: money.New(-1, "INR"), EffectiveFrom: jan},
+		{RateBps: 100},
+		{RateBps: 100, EffectiveFrom: feb, EffectiveTo: &jan},
+	}
+	for i, rule := range bad {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("rule %d: expected a validation error", i)
+		}
+	}
+	if err := (Rule{RateBps: 1000, FixedFee: money.New(500, "INR"), EffectiveFrom: jan, EffectiveTo: &feb}).Validate(); err != nil {
+		t.Errorf("valid rule: %v", err)
+	}
+}
@@ -1,12 +1,17 @@
 package domain
 
+import "ecommerce/pkg/money"
+
 type CartItem struct {
-	ProductVariantID string  `json:"product_variant_id"`
-	Quantity         int     `json:"quantity"`
-	Price            float64 `json:"price"`
+	ProductVariantID string      `json:"product_variant_id"`
+	Quantity         int         `json:"quantity"`
+	Price            money.Money `json:"price"`
 }
 
 type Cart struct {
-	UserID string     `json:"user_id"`
-	Items  []CartItem `json:"items"`
+	UserID string `json:"user_id"`
+	// Currency the buyer will be charged in. Empty means the catalog's own
+	// currency, money.DefaultCurrency.
+	Currency string     `json:"currency,omitempty"`
+	Items    []CartItem `json:"items"`
 }
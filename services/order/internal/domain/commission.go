@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+var (
+	ErrCommissionRuleNotFound = errors.New("commission rule not found")
+	ErrInvalidCommissionRule  = errors.New("invalid commission rule")
+	// ErrCommissionRuleOverlap means another rule for the same category and
+	// seller is in force for part of the same period.
+	ErrCommissionRuleOverlap = errors.New("commission rule overlaps an existing rule")
+	// ErrNoCommissionRule means no rule covers a line at checkout. The
+	// marketplace-wide rule seeded at startup normally rules this out.
+	ErrNoCommissionRule = errors.New("no commission rule covers the line")
+)
+
+// CommissionRule is an admin-managed commission rate. Rules are only read at
+// checkout; each order line keeps a snapshot of what it was charged, so
+// editing a rule never changes past earnings.
+type CommissionRule struct {
+	ID       string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	PublicID string `gorm:"type:varchar(24);uniqueIndex;not null" json:"id"`
+
+	// CategoryPath covers the category and everything beneath it; empty covers
+	// every category. SellerID, if set, makes this a seller's override.
+	CategoryPath string `gorm:"type:text;not null;default:'';index" json:"category_path"`
+	SellerID     string `gorm:"type:varchar(25);not null;default:'';index" json:"seller_id,omitempty"`
+
+	RateBps  int64       `gorm:"not null" json:"rate_bps"`
+	FixedFee money.Money `gorm:"embedded;embeddedPrefix:fixed_fee_" json:"fixed_fee"`
+
+	EffectiveFrom time.Time  `gorm:"not null;index" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"index" json:"effective_to,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
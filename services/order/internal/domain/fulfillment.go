@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+type FulfillmentStatus string
+
+const (
+	FulfillmentAwaitingPayment FulfillmentStatus = "awaiting_payment"
+	FulfillmentPending         FulfillmentStatus = "pending"
+	FulfillmentAccepted        FulfillmentStatus = "accepted"
+	FulfillmentRejected        FulfillmentStatus = "rejected"
+	FulfillmentPacked          FulfillmentStatus = "packed"
+	FulfillmentReadyToShip     FulfillmentStatus = "ready_to_ship"
+	FulfillmentShipped         FulfillmentStatus = "shipped"
+	FulfillmentOutForDelivery  FulfillmentStatus = "out_for_delivery"
+	FulfillmentDelivered       FulfillmentStatus = "delivered"
+	FulfillmentCancelled       FulfillmentStatus = "cancelled"
+)
+
+const (
+	ActorSeller    = "seller"
+	ActorLogistics = "logistics"
+)
+
+var (
+	ErrFulfillmentNotFound          = errors.New("fulfillment not found")
+	ErrInvalidFulfillmentTransition = errors.New("invalid fulfillment status transition")
+)
+
+// fulfillmentTransitions mirrors orderTransitions for a seller's part of an
+// order. Payment and cancellation are driven by the order, the steps up to
+// ready_to_ship by the seller and the rest by the logistics service.
+var fulfillmentTransitions = map[FulfillmentStatus][]FulfillmentStatus{
+	FulfillmentAwaitingPayment: {FulfillmentPending, FulfillmentCancelled},
+	FulfillmentPending:         {FulfillmentAccepted, FulfillmentRejected, FulfillmentCancelled},
+	FulfillmentAccepted:        {FulfillmentPacked, FulfillmentCancelled},
+	FulfillmentPacked:          {FulfillmentReadyToShip, FulfillmentCancelled},
+	FulfillmentReadyToShip:     {FulfillmentShipped, FulfillmentCancelled},
+	FulfillmentShipped:         {FulfillmentOutForDelivery, FulfillmentDelivered},
+	FulfillmentOutForDelivery:  {FulfillmentDelivered},
+}
+
+// fulfillmentStages maps a fulfillment status to the furthest order status it
+// allows. Statuses missing from the map hold the order where it is.
+var fulfillmentStages = map[FulfillmentStatus]OrderStatus{
+	FulfillmentAccepted:       OrderConfirmed,
+	FulfillmentPacked:         OrderPacked,
+	FulfillmentReadyToShip:    OrderPacked,
+	FulfillmentShipped:        OrderShipped,
+	FulfillmentOutForDelivery: OrderOutForDelivery,
+	FulfillmentDelivered:      OrderDelivered,
+}
+
+// orderProgression is the path an order takes while its sellers and couriers
+// work through it.
+var orderProgression = []OrderStatus{OrderPaid, OrderConfirmed, OrderPacked, OrderShipped, OrderOutForDelivery, OrderDelivered}
+
+func (s FulfillmentStatus) IsValid() bool {
+	switch s {
+	case FulfillmentAwaitingPayment, FulfillmentPending, FulfillmentAccepted, FulfillmentRejected,
+		FulfillmentPacked, FulfillmentReadyToShip, FulfillmentShipped, FulfillmentOutForDelivery,
+		FulfillmentDelivered, FulfillmentCancelled:
+		return true
+	}
+	return false
+}
+
+func (s FulfillmentStatus) CanTransitionTo(next FulfillmentStatus) bool {
+	for _, allowed := range fulfillmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Fulfillment is one seller's share of an order: the sub-order the seller
+// accepts or rejects, packs and hands over for shipping.
+type Fulfillment struct {
+	ID       string            `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	PublicID string            `gorm:"type:varchar(24);uniqueIndex;not null" json:"id"`
+	OrderID  string            `gorm:"type:varchar(20);not null;index" json:"order_id"`
+	SellerID string            `gorm:"type:varchar(25);not null;index" json:"seller_id"`
+	Status   FulfillmentStatus `gorm:"type:varchar(20);not null;default:'awaiting_payment';index" json:"status"`
+
+	// Subtotal is what the buyer paid for this seller's lines, in the order
+	// currency, and what is refunded if the seller rejects them.
+	Subtotal money.Money `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	// Commission is the platform's cut of Subtotal, summed from the lines.
+	Commission   money.Money `gorm:"embedded;embeddedPrefix:commission_" json:"-"`
+	StatusReason string      `gorm:"type:text" json:"status_reason,omitempty"`
+
+	AcceptedAt    *time.Time `json:"accepted_at,omitempty"`
+	RejectedAt    *time.Time `json:"rejected_at,omitempty"`
+	PackedAt      *time.Time `json:"packed_at,omitempty"`
+	ReadyToShipAt *time.Time `json:"ready_to_ship_at,omitempty"`
+	ShippedAt     *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Items []OrderItem `gorm:"-" json:"items,omitempty"`
+}
+
+// OrderStatusAfterFulfillments works out the order statuses implied by its
+// fulfillments, in the order they have to be applied. The order moves as far
+// as its slowest seller still on it: confirmed once they have all accepted,
+// packed once they have all packed, and so on through delivery. It is
+// cancelled if every seller rejected it.
+func OrderStatusAfterFulfillments(current OrderStatus, fulfillments []Fulfillment) []OrderStatus {
+	if len(fulfillments) == 0 {
+		return nil
+	}
+
+	target := OrderDelivered
+	active := 0
+	for _, f := range fulfillments {
+		if f.Status == FulfillmentRejected || f.Status == FulfillmentCancelled {
+			continue
+		}
+		active++
+
+		stage, ok := fulfillmentStages[f.Status]
+		if !ok {
+			return nil
+		}
+		if progressionIndex(stage) < progressionIndex(target) {
+			target = stage
+		}
+	}
+
+	if active == 0 {
+		if current.CanTransitionTo(OrderCancelled) {
+			return []OrderStatus{OrderCancelled}
+		}
+		return nil
+	}
+
+	// Take the direct route where the order allows one, as shipped to
+	// delivered does, and otherwise step through each status in between.
+	var steps []OrderStatus
+	status := current
+	for status != target {
+		if status.CanTransitionTo(target) {
+			return append(steps, target)
+		}
+
+		next := progressionIndex(status) + 1
+		if next == 0 || next >= len(orderProgression) || next > progressionIndex(target) {
+			return steps
+		}
+		status = orderProgression[next]
+		steps = append(steps, status)
+	}
+	return steps
+}
+
+func progressionIndex(status OrderStatus) int {
+	for i, s := range orderProgression {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
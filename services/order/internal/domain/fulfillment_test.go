@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderStatusAfterFulfillments(t *testing.T) {
+	fulfillments := func(statuses ...FulfillmentStatus) []Fulfillment {
+		var out []Fulfillment
+		for _, s := range statuses {
+			out = append(out, Fulfillment{Status: s})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		current      OrderStatus
+		fulfillments []Fulfillment
+		want         []OrderStatus
+	}{
+		{"one seller still deciding", OrderPaid, fulfillments(FulfillmentAccepted, FulfillmentPending), nil},
+		{"all accepted", OrderPaid, fulfillments(FulfillmentAccepted, FulfillmentAccepted), []OrderStatus{OrderConfirmed}},
+		{"rejected seller is ignored", OrderPaid, fulfillments(FulfillmentAccepted, FulfillmentRejected), []OrderStatus{OrderConfirmed}},
+		{"already confirmed", OrderConfirmed, fulfillments(FulfillmentAccepted, FulfillmentPacked), nil},
+		{"all packed", OrderConfirmed, fulfillments(FulfillmentPacked, FulfillmentReadyToShip), []OrderStatus{OrderPacked}},
+		{"packed straight from paid", OrderPaid, fulfillments(FulfillmentPacked), []OrderStatus{OrderConfirmed, OrderPacked}},
+		{"all rejected", OrderPaid, fulfillments(FulfillmentRejected, FulfillmentRejected), []OrderStatus{OrderCancelled}},
+		{"one parcel still at the seller", OrderPacked, fulfillments(FulfillmentShipped, FulfillmentReadyToShip), nil},
+		{"all picked up", OrderPacked, fulfillments(FulfillmentShipped, FulfillmentOutForDelivery), []OrderStatus{OrderShipped}},
+		{"delivered straight from shipped", OrderShipped, fulfillments(FulfillmentDelivered, FulfillmentDelivered), []OrderStatus{OrderDelivered}},
+		{"delivered with a rejected seller", OrderOutForDelivery, fulfillments(FulfillmentDelivered, FulfillmentRejected), []OrderStatus{OrderDelivered}},
+		{"all out for delivery", OrderShipped, fulfillments(FulfillmentOutForDelivery), []OrderStatus{OrderOutForDelivery}},
+		{"unpaid order", OrderPending, fulfillments(FulfillmentAwaitingPayment), nil},
+		{"no fulfillments", OrderPaid, nil, nil},
+	}
+
+	for _, tt := range tests {
+		if got := OrderStatusAfterFulfillments(tt.current, tt.fulfillments); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFulfillmentStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from FulfillmentStatus
+		to   FulfillmentStatus
+		want bool
+	}{
+		{FulfillmentAwaitingPayment, FulfillmentPending, true},
+		{FulfillmentAwaitingPayment, FulfillmentAccepted, false},
+		{FulfillmentPending, FulfillmentRejected, true},
+		{FulfillmentAccepted, FulfillmentRejected, false},
+		{FulfillmentAccepted, FulfillmentPacked, true},
+		{FulfillmentPacked, FulfillmentReadyToShip, true},
+		{FulfillmentRejected, FulfillmentAccepted, false},
+		{FulfillmentReadyToShip, FulfillmentShipped, true},
+		{FulfillmentShipped, FulfillmentDelivered, true},
+		{FulfillmentShipped, FulfillmentCancelled, false},
+		{FulfillmentDelivered, FulfillmentOutForDelivery, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: got %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+// Invoice is the tax invoice one seller issues for their lines of an order.
+// Amounts are totals over those lines.
+type Invoice struct {
+	ID       string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	OrderID  string `gorm:"type:varchar(20);not null;uniqueIndex:idx_invoice_order_seller" json:"order_id"`
+	SellerID string `gorm:"type:varchar(25);not null;uniqueIndex:idx_invoice_order_seller;uniqueIndex:idx_invoice_seller_number" json:"seller_id"`
+	Number   string `gorm:"type:varchar(16);not null;uniqueIndex:idx_invoice_seller_number" json:"number"`
+
+	SellerName  string `gorm:"type:varchar(100)" json:"seller_name"`
+	SellerGSTIN string `gorm:"type:varchar(15)" json:"seller_gstin"`
+	BuyerEmail  string `gorm:"type:varchar(255)" json:"-"`
+
+	TaxableValue money.Money `gorm:"embedded;embeddedPrefix:taxable_" json:"taxable_value"`
+	CGST         money.Money `gorm:"embedded;embeddedPrefix:cgst_" json:"cgst"`
+	SGST         money.Money `gorm:"embedded;embeddedPrefix:sgst_" json:"sgst"`
+	IGST         money.Money `gorm:"embedded;embeddedPrefix:igst_" json:"igst"`
+	Total        money.Money `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+
+	PDFURL    string     `gorm:"type:text" json:"pdf_url,omitempty"`
+	EmailedAt *time.Time `json:"emailed_at,omitempty"`
+
+	IssuedAt  time.Time `gorm:"not null" json:"issued_at"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// InvoiceSequence is the last invoice number a seller used in a financial
+// year. Numbers are taken in the transaction that writes the invoice, so a
+// rolled-back invoice does not leave a gap.
+type InvoiceSequence struct {
+	SellerID   string `gorm:"type:varchar(25);primaryKey"`
+	FiscalYear string `gorm:"type:varchar(4);primaryKey"`
+	LastNumber int64  `gorm:"not null;default:0"`
+}
@@ -1,17 +1,24 @@
 package domain
 
 import (
+	"ecommerce/pkg/money"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Order struct {
-	ID          string  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
-	PublicID    string  `gorm:"type:varchar(20);uniqueIndex;not null" json:"id"`
-	UserID      string  `gorm:"type:varchar(21);not null;index" json:"user_id"`
-	TotalAmount float64 `gorm:"not null" json:"total_amount"`
-	Status      string  `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	ID          string      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	PublicID    string      `gorm:"type:varchar(20);uniqueIndex;not null" json:"id"`
+	UserID      string      `gorm:"type:varchar(21);not null;index" json:"user_id"`
+	TotalAmount money.Money `gorm:"embedded;embeddedPrefix:total_" json:"total_amount"`
+	// BaseTotalAmount and ExchangeRate snapshot the catalog-currency total and
+	// the rate it was converted at when the order was placed.
+	BaseTotalAmount money.Money `gorm:"embedded;embeddedPrefix:base_total_" json:"base_total_amount"`
+	ExchangeRate    string      `gorm:"type:varchar(32);not null;default:'1'" json:"exchange_rate"`
+	// TaxAmount is the GST contained in TotalAmount; prices are tax-inclusive.
+	TaxAmount money.Money `gorm:"embedded;embeddedPrefix:tax_" json:"tax_amount"`
+	Status    OrderStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
 
 	ShippingName    string `gorm:"type:varchar(100);not null" json:"shipping_name"`
 	ShippingPhone   string `gorm:"type:varchar(20);not null" json:"shipping_phone"`
@@ -20,18 +27,53 @@ type Order struct {
 	ShippingState   string `gorm:"type:varchar(50);not null" json:"shipping_state"`
 	ShippingZip     string `gorm:"type:varchar(20);not null" json:"shipping_zip"`
 
+	// CustomerEmail is where the tax invoices for the order are sent.
+	CustomerEmail string `gorm:"type:varchar(255)" json:"-"`
+
+	// StockCommittedAt is when the catalog turned the units held for the order
+	// into a sale. A paid order without it is retried by the stock worker.
+	StockCommittedAt *time.Time `gorm:"index" json:"-"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Items []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
+	Items        []OrderItem   `gorm:"foreignKey:OrderID" json:"items"`
+	Fulfillments []Fulfillment `gorm:"foreignKey:OrderID;references:PublicID" json:"fulfillments,omitempty"`
 }
 
 type OrderItem struct {
 	ID      string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
 	OrderID string `gorm:"type:uuid;not null;index" json:"-"`
 
-	ProductID string  `gorm:"type:varchar(25);not null" json:"product_id"`
-	Quantity  int     `gorm:"not null" json:"quantity"`
-	Price     float64 `gorm:"not null" json:"price"`
+	ProductID string      `gorm:"type:varchar(25);not null" json:"product_id"`
+	Quantity  int         `gorm:"not null" json:"quantity"`
+	Price     money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+
+	// The catalog's name and HSN code for the product at checkout, kept so the
+	// invoice shows what was bought even if the listing changes later.
+	ProductName string `gorm:"type:varchar(500)" json:"product_name,omitempty"`
+	HSNCode     string `gorm:"type:varchar(8)" json:"hsn_code,omitempty"`
+
+	SellerID      string `gorm:"type:varchar(25);index" json:"seller_id,omitempty"`
+	FulfillmentID string `gorm:"type:varchar(24);index" json:"fulfillment_id,omitempty"`
+	SellerGSTIN   string `gorm:"type:varchar(15)" json:"seller_gstin,omitempty"`
+	CategoryPath  string `gorm:"type:text" json:"-"`
+
+	// GST for the whole line (Price x Quantity). TaxableValue plus the three
+	// components equals the line total.
+	SupplyType   string      `gorm:"type:varchar(20)" json:"supply_type,omitempty"`
+	TaxRateBps   int64       `gorm:"not null;default:0" json:"tax_rate_bps"`
+	TaxableValue money.Money `gorm:"embedded;embeddedPrefix:taxable_" json:"taxable_value"`
+	CGST         money.Money `gorm:"embedded;embeddedPrefix:cgst_" json:"cgst"`
+	SGST         money.Money `gorm:"embedded;embeddedPrefix:sgst_" json:"sgst"`
+	IGST         money.Money `gorm:"embedded;embeddedPrefix:igst_" json:"igst"`
+
+	// The platform's commission on the whole line, fixed at checkout from the
+	// rule in force then. CommissionFee is that rule's per-unit fee in the
+	// order currency.
+	CommissionRuleID  string      `gorm:"type:varchar(24)" json:"-"`
+	CommissionRateBps int64       `gorm:"not null;default:0" json:"-"`
+	CommissionFee     money.Money `gorm:"embedded;embeddedPrefix:commission_fee_" json:"-"`
+	Commission        money.Money `gorm:"embedded;embeddedPrefix:commission_" json:"-"`
 }
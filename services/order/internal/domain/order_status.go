@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderPending        OrderStatus = "pending"
+	OrderPaid           OrderStatus = "paid"
+	OrderConfirmed      OrderStatus = "confirmed"
+	OrderPacked         OrderStatus = "packed"
+	OrderShipped        OrderStatus = "shipped"
+	OrderOutForDelivery OrderStatus = "out_for_delivery"
+	OrderDelivered      OrderStatus = "delivered"
+	OrderCancelled      OrderStatus = "cancelled"
+	OrderRefunded       OrderStatus = "refunded"
+	OrderReturned       OrderStatus = "returned"
+)
+
+const (
+	ActorSystem   = "system"
+	ActorCustomer = "customer"
+	ActorPayment  = "payment"
+)
+
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+)
+
+// orderTransitions lists every status an order may move to from a given status.
+// Statuses missing from the map are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending:        {OrderPaid, OrderCancelled},
+	OrderPaid:           {OrderConfirmed, OrderCancelled, OrderRefunded},
+	OrderConfirmed:      {OrderPacked, OrderCancelled, OrderRefunded},
+	OrderPacked:         {OrderShipped, OrderCancelled, OrderRefunded},
+	OrderShipped:        {OrderOutForDelivery, OrderDelivered, OrderReturned},
+	OrderOutForDelivery: {OrderDelivered, OrderReturned},
+	OrderDelivered:      {OrderReturned},
+	OrderCancelled:      {OrderRefunded},
+	OrderReturned:       {OrderRefunded},
+}
+
+// InvoicedStatuses are the statuses that follow a captured payment. An order in
+// one of them is owed a tax invoice from each of its sellers.
+var InvoicedStatuses = []OrderStatus{
+	OrderPaid,
+	OrderConfirmed,
+	OrderPacked,
+	OrderShipped,
+	OrderOutForDelivery,
+	OrderDelivered,
+}
+
+func (s OrderStatus) IsInvoiced() bool {
+	for _, status := range InvoicedStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderPending, OrderPaid, OrderConfirmed, OrderPacked, OrderShipped,
+		OrderOutForDelivery, OrderDelivered, OrderCancelled, OrderRefunded, OrderReturned:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+type OrderStatusHistory struct {
+	ID         string      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	OrderID    string      `gorm:"type:varchar(20);not null;index" json:"order_id"`
+	FromStatus OrderStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus   OrderStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Actor      string      `gorm:"type:varchar(50);not null" json:"actor"`
+	Reason     string      `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time   `gorm:"index" json:"created_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
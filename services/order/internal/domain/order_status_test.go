@@ -0,0 +1,46 @@
+package domain
+
+import "testing"
+
+func TestOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from OrderStatus
+		to   OrderStatus
+		want bool
+	}{
+		{OrderPending, OrderPaid, true},
+		{OrderPending, OrderCancelled, true},
+		{OrderPending, OrderShipped, false},
+		{OrderPaid, OrderConfirmed, true},
+		{OrderPaid, OrderPending, false},
+		{OrderConfirmed, OrderPacked, true},
+		{OrderPacked, OrderShipped, true},
+		{OrderShipped, OrderOutForDelivery, true},
+		{OrderShipped, OrderCancelled, false},
+		{OrderOutForDelivery, OrderDelivered, true},
+		{OrderDelivered, OrderReturned, true},
+		{OrderDelivered, OrderCancelled, false},
+		{OrderCancelled, OrderRefunded, true},
+		{OrderCancelled, OrderPaid, false},
+		{OrderReturned, OrderRefunded, true},
+		{OrderRefunded, OrderPending, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: got %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestOrderStatusTerminal(t *testing.T) {
+	if !OrderRefunded.IsTerminal() {
+		t.Errorf("refunded should be terminal")
+	}
+	if OrderDelivered.IsTerminal() {
+		t.Errorf("delivered should not be terminal")
+	}
+	if OrderStatus("lost").IsValid() {
+		t.Errorf("unknown status should not be valid")
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+type ReturnStatus string
+
+const (
+	ReturnRequested ReturnStatus = "requested"
+	ReturnApproved  ReturnStatus = "approved"
+	ReturnRejected  ReturnStatus = "rejected"
+	ReturnPickedUp  ReturnStatus = "picked_up"
+	ReturnReceived  ReturnStatus = "received"
+)
+
+// MaxReturnPhotos caps the pictures a buyer can attach to one returned line.
+const MaxReturnPhotos = 5
+
+var (
+	ErrReturnNotFound          = errors.New("return not found")
+	ErrInvalidReturnTransition = errors.New("invalid return status transition")
+	ErrReturnNotAllowed        = errors.New("item cannot be returned")
+)
+
+// returnTransitions lists where a return may go next. The seller approves or
+// rejects it, the courier picks it up and the seller confirms it arrived; a
+// seller may also confirm receipt of a parcel the courier has not reported yet.
+var returnTransitions = map[ReturnStatus][]ReturnStatus{
+	ReturnRequested: {ReturnApproved, ReturnRejected},
+	ReturnApproved:  {ReturnPickedUp, ReturnReceived},
+	ReturnPickedUp:  {ReturnReceived},
+}
+
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnRequested, ReturnApproved, ReturnRejected, ReturnPickedUp, ReturnReceived:
+		return true
+	}
+	return false
+}
+
+func (s ReturnStatus) CanTransitionTo(next ReturnStatus) bool {
+	for _, allowed := range returnTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Return is a buyer's request to send back items from one fulfillment. An
+// order with several sellers gets one return per seller involved.
+type Return struct {
+	ID            string       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	PublicID      string       `gorm:"type:varchar(24);uniqueIndex;not null" json:"id"`
+	OrderID       string       `gorm:"type:varchar(20);not null;index" json:"order_id"`
+	FulfillmentID string       `gorm:"type:varchar(24);not null;index" json:"fulfillment_id"`
+	SellerID      string       `gorm:"type:varchar(25);not null;index" json:"seller_id"`
+	UserID        string       `gorm:"type:varchar(21);not null;index" json:"user_id"`
+	Status        ReturnStatus `gorm:"type:varchar(20);not null;default:'requested';index" json:"status"`
+
+	// RefundAmount is what the buyer paid for the returned units, in the order
+	// currency. It is refunded once the seller confirms they arrived.
+	RefundAmount money.Money `gorm:"embedded;embeddedPrefix:refund_" json:"refund_amount"`
+	SellerNote   string      `gorm:"type:text" json:"seller_note,omitempty"`
+
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+	RejectedAt *time.Time `json:"rejected_at,omitempty"`
+	PickedUpAt *time.Time `json:"picked_up_at,omitempty"`
+	ReceivedAt *time.Time `json:"received_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Items []ReturnItem `gorm:"foreignKey:ReturnID;references:PublicID" json:"items"`
+}
+
+type ReturnItem struct {
+	ID          string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	ReturnID    string `gorm:"type:varchar(24);not null;index" json:"-"`
+	OrderItemID string `gorm:"type:uuid;not null;index" json:"-"`
+
+	ProductID string      `gorm:"type:varchar(25);not null" json:"product_id"`
+	Quantity  int         `gorm:"not null" json:"quantity"`
+	UnitPrice money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	Reason    string      `gorm:"type:text;not null" json:"reason"`
+	PhotoURLs []string    `gorm:"type:jsonb;serializer:json" json:"photo_urls,omitempty"`
+}
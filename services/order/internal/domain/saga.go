@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"time"
+)
+
+const (
+	SagaRunning      = "running"
+	SagaCompleted    = "completed"
+	SagaCompensating = "compensating"
+	SagaCompensated  = "compensated"
+	SagaFailed       = "failed"
+)
+
+// CheckoutSaga is the persisted state of one checkout attempt. StepIndex points
+// at the step currently executing (or, while compensating, the newest step that
+// still has to be undone).
+type CheckoutSaga struct {
+	ID        string `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	OrderID   string `gorm:"type:varchar(20);uniqueIndex;not null" json:"order_id"`
+	UserID    string `gorm:"type:varchar(21);not null;index" json:"user_id"`
+	Status    string `gorm:"type:varchar(20);default:'running';index" json:"status"`
+	StepIndex int    `gorm:"not null;default:0" json:"step_index"`
+
+	State     CheckoutSagaState `gorm:"type:jsonb;serializer:json" json:"state"`
+	LastError string            `gorm:"type:text" json:"last_error,omitempty"`
+	Attempts  int               `gorm:"not null;default:0" json:"attempts"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CheckoutSagaState struct {
+	ShippingName    string  `json:"shipping_name"`
+	ShippingPhone   string  `json:"shipping_phone"`
+	ShippingAddress Address `json:"shipping_address"`
+	CustomerEmail   string  `json:"customer_email,omitempty"`
+
+	CartItems  []CartItem  `json:"cart_items"`
+	OrderItems []OrderItem `json:"order_items,omitempty"`
+
+	Currency        string      `json:"currency,omitempty"`
+	TotalAmount     money.Money `json:"total_amount"`
+	BaseTotalAmount money.Money `json:"base_total_amount"`
+	TaxAmount       money.Money `json:"tax_amount"`
+	ExchangeRate    string      `json:"exchange_rate,omitempty"`
+	PaymentURL      string      `json:"payment_url,omitempty"`
+}
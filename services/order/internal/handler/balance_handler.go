@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"ecommerce/pkg/logger"
+	"net/http"
+
+	"ecommerce/services/order/internal/client"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BalanceHandler shows sellers what they have earned. The figures live in the
+// payment service's ledger; this service only vouches for the seller.
+type BalanceHandler struct {
+	paymentClient client.PaymentService
+}
+
+func NewBalanceHandler(paymentClient client.PaymentService) *BalanceHandler {
+	return &BalanceHandler{paymentClient: paymentClient}
+}
+
+func (h *BalanceHandler) GetSellerBalance(c *gin.Context) {
+	balances, err := h.paymentClient.GetSellerBalance(c.Request.Context(), c.GetString("seller_id"))
+	if err != nil {
+		logger.Error("Failed to get seller balance.", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "could not load your balance, please try again"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"balances": balances})
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"ecommerce/pkg/fx"
 	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/service"
 
@@ -97,3 +99,33 @@ func (h *CartHandler) ClearCart(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "cart cleared successfully"})
 }
+
+type SetCurrencyRequest struct {
+	Currency string `json:"currency" binding:"required"`
+}
+
+func (h *CartHandler) SetCurrency(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req SetCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "currency is required"})
+		return
+	}
+
+	cart, err := h.cartService.SetCurrency(c.Request.Context(), userID, req.Currency)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported currency"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"ecommerce/pkg/logger"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce/pkg/money"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CommissionHandler struct {
+	commissionService service.CommissionService
+}
+
+func NewCommissionHandler(commissionService service.CommissionService) *CommissionHandler {
+	return &CommissionHandler{commissionService: commissionService}
+}
+
+type commissionRuleRequest struct {
+	CategoryPath  string       `json:"category_path"`
+	SellerID      string       `json:"seller_id"`
+	RateBps       int64        `json:"rate_bps" binding:"min=0,max=10000"`
+	FixedFee      *money.Money `json:"fixed_fee"`
+	EffectiveFrom *time.Time   `json:"effective_from"`
+	EffectiveTo   *time.Time   `json:"effective_to"`
+}
+
+func (r commissionRuleRequest) input() service.CommissionRuleInput {
+	input := service.CommissionRuleInput{
+		CategoryPath:  r.CategoryPath,
+		SellerID:      r.SellerID,
+		RateBps:       r.RateBps,
+		FixedFee:      money.Zero(money.DefaultCurrency),
+		EffectiveFrom: time.Now(),
+		EffectiveTo:   r.EffectiveTo,
+	}
+	if r.FixedFee != nil {
+		input.FixedFee = *r.FixedFee
+	}
+	if r.EffectiveFrom != nil {
+		input.EffectiveFrom = *r.EffectiveFrom
+	}
+	return input
+}
+
+func (h *CommissionHandler) CreateRule(c *gin.Context) {
+	var req commissionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid commission rule", "details": err.Error()})
+		return
+	}
+
+	rule, err := h.commissionService.CreateRule(c.Request.Context(), req.input())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *CommissionHandler) ListRules(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rules, err := h.commissionService.ListRules(c.Request.Context(), c.Query("seller_id"), c.Query("category_path"), page, limit)
+	if err != nil {
+		logger.Error("Failed to list commission rules.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list commission rules"})
+		return
+	}
+
+	if rules == nil {
+		rules = []domain.CommissionRule{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func (h *CommissionHandler) GetRule(c *gin.Context) {
+	rule, err := h.commissionService.GetRule(c.Request.Context(), c.Param("rule_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *CommissionHandler) UpdateRule(c *gin.Context) {
+	var req commissionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid commission rule", "details": err.Error()})
+		return
+	}
+	if req.EffectiveFrom == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from is required when updating a rule"})
+		return
+	}
+
+	rule, err := h.commissionService.UpdateRule(c.Request.Context(), c.Param("rule_id"), req.input())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *CommissionHandler) DeleteRule(c *gin.Context) {
+	if err := h.commissionService.DeleteRule(c.Request.Context(), c.Param("rule_id")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CommissionHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCommissionRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "commission rule not found"})
+	case errors.Is(err, domain.ErrInvalidCommissionRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCommissionRuleOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": "another rule for this category and seller is in force for part of that period"})
+	default:
+		logger.Error("Commission rule request failed.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"ecommerce/pkg/logger"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type FulfillmentHandler struct {
+	fulfillmentService service.FulfillmentService
+}
+
+func NewFulfillmentHandler(fulfillmentService service.FulfillmentService) *FulfillmentHandler {
+	return &FulfillmentHandler{fulfillmentService: fulfillmentService}
+}
+
+type rejectFulfillmentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (h *FulfillmentHandler) ListSellerOrders(c *gin.Context) {
+	sellerID := c.GetString("seller_id")
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	status := domain.FulfillmentStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status filter"})
+		return
+	}
+
+	fulfillments, err := h.fulfillmentService.ListSellerOrders(c.Request.Context(), sellerID, status, page, limit)
+	if err != nil {
+		logger.Error("Failed to list seller orders.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list orders"})
+		return
+	}
+
+	if fulfillments == nil {
+		fulfillments = []domain.Fulfillment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": fulfillments,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (h *FulfillmentHandler) GetSellerOrder(c *gin.Context) {
+	fulfillment, err := h.fulfillmentService.GetSellerOrder(c.Request.Context(), c.GetString("seller_id"), c.Param("fulfillment_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillment)
+}
+
+func (h *FulfillmentHandler) Accept(c *gin.Context) {
+	fulfillment, err := h.fulfillmentService.Accept(c.Request.Context(), c.GetString("seller_id"), c.Param("fulfillment_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillment)
+}
+
+func (h *FulfillmentHandler) Reject(c *gin.Context) {
+	var req rejectFulfillmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a rejection reason is required", "details": err.Error()})
+		return
+	}
+
+	fulfillment, err := h.fulfillmentService.Reject(c.Request.Context(), c.GetString("seller_id"), c.Param("fulfillment_id"), req.Reason)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Order rejected. The buyer will be refunded for these items.",
+		"order":   fulfillment,
+	})
+}
+
+func (h *FulfillmentHandler) Pack(c *gin.Context) {
+	fulfillment, err := h.fulfillmentService.Pack(c.Request.Context(), c.GetString("seller_id"), c.Param("fulfillment_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillment)
+}
+
+func (h *FulfillmentHandler) MarkReadyToShip(c *gin.Context) {
+	fulfillment, err := h.fulfillmentService.MarkReadyToShip(c.Request.Context(), c.GetString("seller_id"), c.Param("fulfillment_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillment)
+}
+
+func (h *FulfillmentHandler) writeError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrFulfillmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidFulfillmentTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This action is not allowed in the order's current state.", "details": err.Error()})
+		return
+	}
+
+	logger.Error("Failed to update seller order.", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update order"})
+}
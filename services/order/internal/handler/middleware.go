@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/service"
 	"ecommerce/services/order/internal/utils"
 	"net/http"
 	"strings"
 
+	"go.uber.org/zap"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,6 +44,37 @@ func RequireUser() gin.HandlerFunc {
 		}
 
 		c.Set("user_id", extractedUserID)
+		if email, ok := claims["email"].(string); ok {
+			c.Set("user_email", email)
+		}
+		if role, ok := claims["role"].(string); ok {
+			c.Set("user_role", role)
+		}
+		c.Next()
+	}
+}
+
+// RequireSeller runs after RequireUser and resolves the seller the user
+// operates as. Handlers find the catalog seller ID under "seller_id".
+func RequireSeller(fulfillmentService service.FulfillmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_role") != "seller" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden: seller access required"})
+			return
+		}
+
+		sellerID, err := fulfillmentService.ResolveSeller(c.Request.Context(), c.GetString("user_id"))
+		if err != nil {
+			logger.Error("Failed to resolve seller.", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not verify seller account, please try again"})
+			return
+		}
+		if sellerID == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "seller profile not found. please complete onboarding."})
+			return
+		}
+
+		c.Set("seller_id", sellerID)
 		c.Next()
 	}
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"ecommerce/pkg/logger"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -15,11 +16,16 @@ import (
 )
 
 type OrderHandler struct {
-	orderService service.OrderService
+	orderService   service.OrderService
+	invoiceService service.InvoiceService
 }
 
-func NewOrderHandler(orderService service.OrderService) *OrderHandler {
-	return &OrderHandler{orderService: orderService}
+func NewOrderHandler(orderService service.OrderService, invoiceService service.InvoiceService) *OrderHandler {
+	return &OrderHandler{orderService: orderService, invoiceService: invoiceService}
+}
+
+type cancelOrderRequest struct {
+	Reason string `json:"reason"`
 }
 
 type checkoutRequest struct {
@@ -51,7 +57,7 @@ func (h *OrderHandler) Checkout(c *gin.Context) {
 		ZipCode:     req.ZipCode,
 	}
 
-	order, paymentURL, err := h.orderService.Checkout(c.Request.Context(), userID, req.Name, req.Phone, shippingAddress)
+	order, paymentURL, err := h.orderService.Checkout(c.Request.Context(), userID, c.GetString("user_email"), req.Name, req.Phone, shippingAddress)
 	if err != nil {
 		if strings.Contains(err.Error(), "empty cart") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Your cart is empty. Please add items before checking out."})
@@ -135,3 +141,105 @@ func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 
 	c.JSON(http.StatusOK, orders)
 }
+
+func (h *OrderHandler) GetOrderTimeline(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	publicID := c.Param("public_id")
+	if publicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order id is required"})
+		return
+	}
+
+	timeline, err := h.orderService.GetOrderTimeline(c.Request.Context(), publicID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+
+	if timeline == nil {
+		timeline = []domain.OrderStatusHistory{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": publicID,
+		"timeline": timeline,
+	})
+}
+
+func (h *OrderHandler) GetOrderInvoices(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	publicID := c.Param("public_id")
+	if publicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order id is required"})
+		return
+	}
+
+	invoices, err := h.invoiceService.GetOrderInvoices(c.Request.Context(), publicID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+
+	if invoices == nil {
+		invoices = []domain.Invoice{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": publicID,
+		"invoices": invoices,
+	})
+}
+
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	publicID := c.Param("public_id")
+	if publicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order id is required"})
+		return
+	}
+
+	var req cancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cancellation request", "details": err.Error()})
+			return
+		}
+	}
+
+	order, err := h.orderService.CancelOrder(c.Request.Context(), publicID, userID, req.Reason)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This order can no longer be cancelled."})
+			return
+		}
+		if strings.Contains(err.Error(), "order not found") || strings.Contains(err.Error(), "failed to fetch order") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return
+		}
+
+		logger.Error("Failed to cancel order.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Order cancelled. Any payment made will be refunded to the original payment method.",
+		"order_id": order.PublicID,
+		"status":   order.Status,
+	})
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"ecommerce/pkg/logger"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxReturnPhotoSize matches the media service's image limit.
+const maxReturnPhotoSize = 5 << 20
+
+type ReturnHandler struct {
+	returnService service.ReturnService
+}
+
+func NewReturnHandler(returnService service.ReturnService) *ReturnHandler {
+	return &ReturnHandler{returnService: returnService}
+}
+
+type returnItemRequest struct {
+	ProductID string   `json:"product_id" binding:"required"`
+	Quantity  int      `json:"quantity" binding:"required,min=1"`
+	Reason    string   `json:"reason" binding:"required,max=500"`
+	PhotoURLs []string `json:"photo_urls" binding:"max=5,dive,url"`
+}
+
+type requestReturnRequest struct {
+	Items []returnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type rejectReturnRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (h *ReturnHandler) RequestReturn(c *gin.Context) {
+	var req requestReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "every item needs a product, a quantity and a reason", "details": err.Error()})
+		return
+	}
+
+	items := make([]service.ReturnItemRequest, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, service.ReturnItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Reason:    item.Reason,
+			PhotoURLs: item.PhotoURLs,
+		})
+	}
+
+	returnList, err := h.returnService.RequestReturn(c.Request.Context(), c.GetString("user_id"), c.Param("public_id"), items)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Return requested. The seller will review it shortly.",
+		"returns": returnList,
+	})
+}
+
+func (h *ReturnHandler) ListOrderReturns(c *gin.Context) {
+	returnList, err := h.returnService.ListOrderReturns(c.Request.Context(), c.GetString("user_id"), c.Param("public_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if returnList == nil {
+		returnList = []domain.Return{}
+	}
+	c.JSON(http.StatusOK, gin.H{"returns": returnList})
+}
+
+func (h *ReturnHandler) UploadReturnPhoto(c *gin.Context) {
+	file, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a photo is required"})
+		return
+	}
+	if file.Size > maxReturnPhotoSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "photo is larger than 5 MB"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read photo", "details": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read photo", "details": err.Error()})
+		return
+	}
+
+	url, err := h.returnService.UploadReturnPhoto(c.Request.Context(), c.GetString("user_id"), c.Param("public_id"), file.Filename, content)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"url": url})
+}
+
+func (h *ReturnHandler) ListSellerReturns(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	status := domain.ReturnStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status filter"})
+		return
+	}
+
+	returnList, err := h.returnService.ListSellerReturns(c.Request.Context(), c.GetString("seller_id"), status, page, limit)
+	if err != nil {
+		logger.Error("Failed to list seller returns.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list returns"})
+		return
+	}
+
+	if returnList == nil {
+		returnList = []domain.Return{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"returns": returnList,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+func (h *ReturnHandler) Approve(c *gin.Context) {
+	ret, err := h.returnService.Approve(c.Request.Context(), c.GetString("seller_id"), c.Param("return_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Return approved. A courier will collect the items from the buyer.",
+		"return":  ret,
+	})
+}
+
+func (h *ReturnHandler) Reject(c *gin.Context) {
+	var req rejectReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a rejection reason is required", "details": err.Error()})
+		return
+	}
+
+	ret, err := h.returnService.Reject(c.Request.Context(), c.GetString("seller_id"), c.Param("return_id"), req.Reason)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+func (h *ReturnHandler) ConfirmReceipt(c *gin.Context) {
+	ret, err := h.returnService.ConfirmReceipt(c.Request.Context(), c.GetString("seller_id"), c.Param("return_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Return received. The items are back in stock and the buyer will be refunded.",
+		"return":  ret,
+	})
+}
+
+func (h *ReturnHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+	case errors.Is(err, domain.ErrReturnNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "return not found"})
+	case errors.Is(err, domain.ErrReturnNotAllowed):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "These items cannot be returned.", "details": err.Error()})
+	case errors.Is(err, domain.ErrInvalidReturnTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "This action is not allowed in the return's current state.", "details": err.Error()})
+	default:
+		logger.Error("Failed to handle return.", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process return"})
+	}
+}
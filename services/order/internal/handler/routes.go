@@ -1,27 +1,92 @@
 package handler
 
 import (
+	"ecommerce/pkg/middleware"
+	"ecommerce/pkg/outbox"
+	"ecommerce/services/order/internal/service"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(router *gin.Engine, cartHandler *CartHandler, customerHandler *CustomerHandler, orderHandler *OrderHandler) {
+// Handlers holds everything RegisterRoutes wires up.
+type Handlers struct {
+	Cart        *CartHandler
+	Customer    *CustomerHandler
+	Order       *OrderHandler
+	Fulfillment *FulfillmentHandler
+	Tracking    *TrackingHandler
+	Return      *ReturnHandler
+	Balance     *BalanceHandler
+	Commission  *CommissionHandler
+
+	Outbox *outbox.Store
+
+	// FulfillmentService resolves the seller behind a request.
+	FulfillmentService service.FulfillmentService
+}
+
+func RegisterRoutes(router *gin.Engine, h Handlers) {
 
 	v1 := router.Group("/api/v1")
 
 	v1.Use(RequireUser())
 	//v1.Use(MockMiddleware())
 	{
-		v1.GET("/cart", cartHandler.GetCart)
-		v1.POST("/cart/add", cartHandler.AddItem)
-		v1.DELETE("/cart/remove/:product_id", cartHandler.RemoveItem)
-		v1.DELETE("/cart", cartHandler.ClearCart)
-
-		v1.GET("/profile", customerHandler.GetProfile)
-		v1.POST("/profile", customerHandler.CreateProfile)
-		v1.POST("/profile/addresses", customerHandler.AddAddress)
-
-		v1.POST("/checkout", orderHandler.Checkout)
-		v1.GET("/orders/:public_id", orderHandler.GetOrder)
-		v1.GET("/orders", orderHandler.GetUserOrders)
+		v1.GET("/cart", h.Cart.GetCart)
+		v1.POST("/cart/add", h.Cart.AddItem)
+		v1.DELETE("/cart/remove/:product_id", h.Cart.RemoveItem)
+		v1.DELETE("/cart", h.Cart.ClearCart)
+		v1.PUT("/cart/currency", h.Cart.SetCurrency)
+
+		v1.GET("/profile", h.Customer.GetProfile)
+		v1.POST("/profile", h.Customer.CreateProfile)
+		v1.POST("/profile/addresses", h.Customer.AddAddress)
+
+		v1.POST("/checkout", h.Order.Checkout)
+		v1.GET("/orders/:public_id", h.Order.GetOrder)
+		v1.GET("/orders/:public_id/timeline", h.Order.GetOrderTimeline)
+		v1.GET("/orders/:public_id/invoices", h.Order.GetOrderInvoices)
+		v1.GET("/orders/:public_id/tracking/stream", h.Tracking.StreamOrderTracking)
+		v1.POST("/orders/:public_id/cancel", h.Order.CancelOrder)
+		v1.POST("/orders/:public_id/returns", h.Return.RequestReturn)
+		v1.GET("/orders/:public_id/returns", h.Return.ListOrderReturns)
+		v1.POST("/orders/:public_id/returns/photos", h.Return.UploadReturnPhoto)
+		v1.GET("/orders", h.Order.GetUserOrders)
+	}
+
+	seller := v1.Group("/seller/orders")
+	seller.Use(RequireSeller(h.FulfillmentService))
+	{
+		seller.GET("", h.Fulfillment.ListSellerOrders)
+		seller.GET("/:fulfillment_id", h.Fulfillment.GetSellerOrder)
+		seller.POST("/:fulfillment_id/accept", h.Fulfillment.Accept)
+		seller.POST("/:fulfillment_id/reject", h.Fulfillment.Reject)
+		seller.POST("/:fulfillment_id/pack", h.Fulfillment.Pack)
+		seller.POST("/:fulfillment_id/ready-to-ship", h.Fulfillment.MarkReadyToShip)
+	}
+
+	sellerReturns := v1.Group("/seller/returns")
+	sellerReturns.Use(RequireSeller(h.FulfillmentService))
+	{
+		sellerReturns.GET("", h.Return.ListSellerReturns)
+		sellerReturns.POST("/:return_id/approve", h.Return.Approve)
+		sellerReturns.POST("/:return_id/reject", h.Return.Reject)
+		sellerReturns.POST("/:return_id/receive", h.Return.ConfirmReceipt)
+	}
+
+	v1.GET("/seller/balance", RequireSeller(h.FulfillmentService), h.Balance.GetSellerBalance)
+
+	// v1 asks for a user session, so the operations routes hang off the
+	// router instead.
+	internal := router.Group("/api/v1/internal")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		internal.POST("/commission-rules", h.Commission.CreateRule)
+		internal.GET("/commission-rules", h.Commission.ListRules)
+		internal.GET("/commission-rules/:rule_id", h.Commission.GetRule)
+		internal.PUT("/commission-rules/:rule_id", h.Commission.UpdateRule)
+		internal.DELETE("/commission-rules/:rule_id", h.Commission.DeleteRule)
+
+		outbox.RegisterRoutes(internal, h.Outbox)
 	}
 }
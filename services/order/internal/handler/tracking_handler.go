@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"ecommerce/services/order/internal/service"
+	"ecommerce/services/order/internal/tracking"
+
+	"github.com/gin-gonic/gin"
+)
+
+const trackingHeartbeat = 25 * time.Second
+
+type TrackingHandler struct {
+	orderService service.OrderService
+	hub          *tracking.Hub
+}
+
+func NewTrackingHandler(orderService service.OrderService, hub *tracking.Hub) *TrackingHandler {
+	return &TrackingHandler{orderService: orderService, hub: hub}
+}
+
+// StreamOrderTracking pushes shipment updates for the buyer's order as
+// Server-Sent Events. The stream opens with the order as it stands, then sends
+// a "shipment" event per status change or agent checkpoint, with comment
+// heartbeats in between so proxies keep the connection open.
+func (h *TrackingHandler) StreamOrderTracking(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("public_id"), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+
+	updates, unsubscribe := h.hub.Subscribe(order.PublicID)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(trackingHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("order", gin.H{"order_id": order.PublicID, "status": order.Status})
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case update, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("shipment", update)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
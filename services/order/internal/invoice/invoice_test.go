@@ -0,0 +1,88 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+func TestFiscalYear(t *testing.T) {
+	tests := map[string]string{
+		"2026-03-31T19:00:00Z": "2627", // already 1 April in IST
+		"2026-03-31T12:00:00Z": "2526",
+		"2026-10-18T00:00:00Z": "2627",
+		"2099-12-31T00:00:00Z": "9900",
+	}
+	for at, want := range tests {
+		ts, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FiscalYear(ts); got != want {
+			t.Errorf("FiscalYear(%s) = %s, want %s", at, got, want)
+		}
+	}
+
+	if n := FormatNumber("2627", 42); n != "INV-2627-000042" || len(n) > 16 {
+		t.Fatalf("unexpected invoice number %q", n)
+	}
+}
+
+func TestRender(t *testing.T) {
+	inv := Invoice{
+		Number:        "INV-2627-000001",
+		IssuedAt:      time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		OrderID:       "ORD-abc12345",
+		PlaceOfSupply: "Maharashtra (27)",
+		Currency:      "INR",
+		Seller:        Party{Name: "Acme (India) Pvt Ltd", GSTIN: "27AAPFU0939F1ZV", Address: "12 Industrial Estate, Pune"},
+		Buyer:         Party{Name: "Asha Rao", Address: "Flat 4, MG Road, Mumbai 400001", State: "Maharashtra"},
+	}
+	for i := 0; i < 60; i++ {
+		inv.Lines = append(inv.Lines, Line{
+			Description:  fmt.Sprintf("Widget %d", i),
+			HSNCode:      "85171300",
+			Quantity:     1,
+			UnitPrice:    money.New(118000, "INR"),
+			RateBps:      1800,
+			TaxableValue: money.New(100000, "INR"),
+			CGST:         money.New(9000, "INR"),
+			SGST:         money.New(9000, "INR"),
+			IGST:         money.Zero("INR"),
+		})
+	}
+
+	pdf, err := Render(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("output is not framed as a PDF")
+	}
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Fatal("expected 60 lines to spill onto a second page")
+	}
+	if !bytes.Contains(pdf, []byte("(85171300)")) {
+		t.Fatal("expected the HSN code to be printed on each line")
+	}
+	if !bytes.Contains(pdf, []byte(`Acme \(India\) Pvt Ltd`)) {
+		t.Fatal("expected parentheses in text to be escaped")
+	}
+
+	// Every xref entry must point at the object it names.
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(pdf)
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(pdf[offset:], -1)
+	for i, entry := range entries {
+		at, _ := strconv.Atoi(string(entry[1]))
+		if !bytes.HasPrefix(pdf[at:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Fatalf("xref entry %d points at the wrong offset", i+1)
+		}
+	}
+}
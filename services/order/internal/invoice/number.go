@@ -0,0 +1,25 @@
+package invoice
+
+import (
+	"fmt"
+	"time"
+)
+
+var ist = time.FixedZone("IST", 5*60*60+30*60)
+
+// FiscalYear returns the Indian financial year (April to March) containing t,
+// e.g. "2627" for FY 2026-27.
+func FiscalYear(t time.Time) string {
+	t = t.In(ist)
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%02d%02d", start%100, (start+1)%100)
+}
+
+// FormatNumber builds the invoice number printed on the PDF. GST rules allow at
+// most 16 characters, unique per seller per financial year.
+func FormatNumber(fiscalYear string, sequence int64) string {
+	return fmt.Sprintf("INV-%s-%06d", fiscalYear, sequence)
+}
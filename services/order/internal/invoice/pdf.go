@@ -0,0 +1,158 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 in PDF points.
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// document is a minimal PDF 1.4 writer: text in the two standard Helvetica
+// faces, lines and filled rectangles. Standard fonts need no embedding, so the
+// output stays small and needs nothing beyond the standard library.
+type document struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func newDocument() *document {
+	d := &document{}
+	d.addPage()
+	return d
+}
+
+func (d *document) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+}
+
+// text draws s with its baseline starting at (x, y), y measured from the top.
+func (d *document) text(x, y float64, font string, size float64, s string) {
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pageHeight-y, escape(s))
+}
+
+// textRight draws s so that it ends at x.
+func (d *document) textRight(x, y float64, font string, size float64, s string) {
+	d.text(x-textWidth(s, font, size), y, font, size, s)
+}
+
+func (d *document) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page, "0.6 w %.2f %.2f m %.2f %.2f l S\n", x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// fill paints a grey rectangle whose top-left corner is (x, y).
+func (d *document) fill(x, y, w, h float64, grey float64) {
+	fmt.Fprintf(d.page, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", grey, x, pageHeight-y-h, w, h)
+}
+
+func (d *document) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are fixed; each page then takes a page object followed by
+	// its content stream.
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape converts s to a WinAnsi PDF string literal body. Characters outside
+// Latin-1 have no glyph in the standard fonts and become '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths holds the Helvetica advance widths, in 1/1000 em, of the
+// printable ASCII characters starting at space.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth measures s in points. Bold is approximated from the regular
+// metrics, which is close enough for right-aligning figures.
+func textWidth(s string, font string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			total += helveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if font == fontBold {
+		width *= 1.05
+	}
+	return width
+}
+
+// truncate shortens s with an ellipsis so it fits in width points.
+func truncate(s string, font string, size float64, width float64) string {
+	if textWidth(s, font, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", font, size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
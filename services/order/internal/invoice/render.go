@@ -0,0 +1,245 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce/pkg/money"
+)
+
+type Party struct {
+	Name    string
+	GSTIN   string
+	Address string
+	State   string
+	Phone   string
+	Email   string
+}
+
+type Line struct {
+	Description  string
+	HSNCode      string
+	Quantity     int
+	UnitPrice    money.Money
+	RateBps      int64
+	TaxableValue money.Money
+	CGST         money.Money
+	SGST         money.Money
+	IGST         money.Money
+}
+
+func (l Line) Total() money.Money {
+	return money.New(l.TaxableValue.Amount+l.CGST.Amount+l.SGST.Amount+l.IGST.Amount, l.TaxableValue.Currency)
+}
+
+// Invoice is everything printed on one seller's tax invoice for an order.
+type Invoice struct {
+	Number        string
+	IssuedAt      time.Time
+	OrderID       string
+	PlaceOfSupply string
+	Currency      string
+	Seller        Party
+	Buyer         Party
+	Lines         []Line
+}
+
+const (
+	margin    = 40.0
+	rowHeight = 16.0
+	pageLimit = pageHeight - 80
+)
+
+type column struct {
+	title string
+	right float64
+}
+
+// Render lays the invoice out on A4 pages and returns the PDF.
+func Render(inv Invoice) ([]byte, error) {
+	if len(inv.Lines) == 0 {
+		return nil, fmt.Errorf("invoice: %s has no lines", inv.Number)
+	}
+
+	intraState := false
+	for _, line := range inv.Lines {
+		if line.CGST.Amount != 0 || line.SGST.Amount != 0 {
+			intraState = true
+		}
+	}
+
+	columns := []column{{"HSN", 205}, {"Qty", 250}, {"Unit price", 310}, {"Taxable", 370}, {"GST %", 405}}
+	if intraState {
+		columns = append(columns, column{"CGST", 455}, column{"SGST", 505})
+	} else {
+		columns = append(columns, column{"IGST", 505})
+	}
+	columns = append(columns, column{"Total", pageWidth - margin})
+
+	d := newDocument()
+	y := drawHeader(d, inv)
+	y = drawTableHeader(d, y, columns)
+
+	var taxable, cgst, sgst, igst, total int64
+	for i, line := range inv.Lines {
+		if y > pageLimit {
+			d.addPage()
+			d.text(margin, 50, fontBold, 10, fmt.Sprintf("Tax invoice %s (continued)", inv.Number))
+			y = drawTableHeader(d, 70, columns)
+		}
+
+		d.text(margin+4, y, fontRegular, 8, fmt.Sprint(i+1))
+		d.text(62, y, fontRegular, 8, truncate(line.Description, fontRegular, 8, 100))
+
+		values := []string{
+			line.HSNCode,
+			fmt.Sprint(line.Quantity),
+			line.UnitPrice.Decimal(),
+			line.TaxableValue.Decimal(),
+			formatRate(line.RateBps),
+		}
+		if intraState {
+			values = append(values, line.CGST.Decimal(), line.SGST.Decimal())
+		} else {
+			values = append(values, line.IGST.Decimal())
+		}
+		values = append(values, line.Total().Decimal())
+
+		for j, value := range values {
+			d.textRight(columns[j].right, y, fontRegular, 8, value)
+		}
+
+		d.line(margin, y+5, pageWidth-margin, y+5)
+		y += rowHeight
+
+		taxable += line.TaxableValue.Amount
+		cgst += line.CGST.Amount
+		sgst += line.SGST.Amount
+		igst += line.IGST.Amount
+		total += line.Total().Amount
+	}
+
+	totals := [][2]string{{"Taxable value", money.New(taxable, inv.Currency).Decimal()}}
+	if intraState {
+		totals = append(totals,
+			[2]string{"CGST", money.New(cgst, inv.Currency).Decimal()},
+			[2]string{"SGST", money.New(sgst, inv.Currency).Decimal()},
+		)
+	} else {
+		totals = append(totals, [2]string{"IGST", money.New(igst, inv.Currency).Decimal()})
+	}
+
+	if y+float64(len(totals)+3)*rowHeight > pageLimit {
+		d.addPage()
+		y = 70
+	}
+
+	y += 8
+	for _, row := range totals {
+		d.text(380, y, fontRegular, 9, row[0])
+		d.textRight(pageWidth-margin, y, fontRegular, 9, row[1])
+		y += rowHeight
+	}
+	d.line(380, y-10, pageWidth-margin, y-10)
+	d.text(380, y+2, fontBold, 10, "Invoice total ("+inv.Currency+")")
+	d.textRight(pageWidth-margin, y+2, fontBold, 10, money.New(total, inv.Currency).Decimal())
+
+	d.text(margin, pageHeight-50, fontRegular, 7, "Prices are inclusive of GST. This is a computer generated invoice and does not require a signature.")
+
+	return d.bytes(), nil
+}
+
+func drawHeader(d *document, inv Invoice) float64 {
+	d.text(margin, 50, fontBold, 16, "TAX INVOICE")
+
+	meta := [][2]string{
+		{"Invoice no.", inv.Number},
+		{"Invoice date", inv.IssuedAt.Format("02 Jan 2006")},
+		{"Order no.", inv.OrderID},
+		{"Place of supply", inv.PlaceOfSupply},
+	}
+	my := 50.0
+	for _, row := range meta {
+		d.text(360, my, fontRegular, 8, row[0])
+		d.text(430, my, fontBold, 8, truncate(row[1], fontBold, 8, pageWidth-margin-430))
+		my += 12
+	}
+
+	y := 80.0
+	y = drawParty(d, y, "Sold by", inv.Seller)
+	y += 10
+	y = drawParty(d, y, "Bill to / Ship to", inv.Buyer)
+
+	if my > y {
+		y = my
+	}
+	return y + 16
+}
+
+func drawParty(d *document, y float64, title string, p Party) float64 {
+	d.text(margin, y, fontRegular, 8, strings.ToUpper(title))
+	y += 13
+	d.text(margin, y, fontBold, 10, truncate(p.Name, fontBold, 10, 300))
+	y += 12
+
+	for _, line := range wrap(p.Address, fontRegular, 8, 300) {
+		d.text(margin, y, fontRegular, 8, line)
+		y += 10
+	}
+	if p.State != "" {
+		d.text(margin, y, fontRegular, 8, "State: "+p.State)
+		y += 10
+	}
+	if p.GSTIN != "" {
+		d.text(margin, y, fontBold, 8, "GSTIN: "+p.GSTIN)
+		y += 10
+	}
+	if p.Phone != "" {
+		d.text(margin, y, fontRegular, 8, "Phone: "+p.Phone)
+		y += 10
+	}
+	if p.Email != "" {
+		d.text(margin, y, fontRegular, 8, "Email: "+p.Email)
+		y += 10
+	}
+	return y
+}
+
+func drawTableHeader(d *document, y float64, columns []column) float64 {
+	d.fill(margin, y-11, pageWidth-2*margin, 16, 0.9)
+	d.text(margin+4, y, fontBold, 8, "#")
+	d.text(62, y, fontBold, 8, "Item")
+	for _, c := range columns {
+		d.textRight(c.right, y, fontBold, 8, c.title)
+	}
+	return y + rowHeight + 2
+}
+
+func formatRate(bps int64) string {
+	if bps%100 == 0 {
+		return fmt.Sprintf("%d%%", bps/100)
+	}
+	return fmt.Sprintf("%d.%02d%%", bps/100, bps%100)
+}
+
+func wrap(s string, font string, size float64, width float64) []string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && textWidth(candidate, font, size) > width {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommissionRepository interface {
+	CreateRule(ctx context.Context, rule *domain.CommissionRule) error
+	UpdateRule(ctx context.Context, rule *domain.CommissionRule) error
+	GetRule(ctx context.Context, publicID string) (*domain.CommissionRule, error)
+	ListRules(ctx context.Context, sellerID string, categoryPath string, limit int, offset int) ([]domain.CommissionRule, error)
+	ListRulesActiveAt(ctx context.Context, at time.Time) ([]domain.CommissionRule, error)
+	DeleteRule(ctx context.Context, publicID string) error
+	SeedRule(ctx context.Context, rule *domain.CommissionRule) (bool, error)
+	BackfillCommissions(ctx context.Context, at time.Time) (int64, error)
+}
+
+type commissionRepository struct {
+	db *gorm.DB
+}
+
+func NewCommissionRepository(db *gorm.DB) CommissionRepository {
+	return &commissionRepository{db: db}
+}
+
+func (r *commissionRepository) CreateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if innerErr := checkRuleOverlap(tx, rule); innerErr != nil {
+			return innerErr
+		}
+		return tx.Create(rule).Error
+	})
+	if err != nil {
+		return fmt.Errorf("repository: failed to create commission rule: %w", err)
+	}
+	return nil
+}
+
+func (r *commissionRepository) UpdateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing domain.CommissionRule
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", rule.PublicID).
+			Take(&existing).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrCommissionRuleNotFound, rule.PublicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock commission rule: %w", innerErr)
+		}
+
+		if innerErr = checkRuleOverlap(tx, rule); innerErr != nil {
+			return innerErr
+		}
+
+		rule.ID = existing.ID
+		rule.CreatedAt = existing.CreatedAt
+		return tx.Save(rule).Error
+	})
+	if err != nil {
+		return fmt.Errorf("repository: failed to update commission rule: %w", err)
+	}
+	return nil
+}
+
+func (r *commissionRepository) GetRule(ctx context.Context, publicID string) (*domain.CommissionRule, error) {
+	rule, err := gorm.G[domain.CommissionRule](r.db).Where("public_id = ?", publicID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrCommissionRuleNotFound, publicID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get commission rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *commissionRepository) ListRules(ctx context.Context, sellerID string, categoryPath string, limit int, offset int) ([]domain.CommissionRule, error) {
+	query := r.db.WithContext(ctx)
+	if sellerID != "" {
+		query = query.Where("seller_id = ?", sellerID)
+	}
+	if categoryPath != "" {
+		query = query.Where("category_path = ?", categoryPath)
+	}
+
+	var rules []domain.CommissionRule
+	err := query.Order("category_path asc, seller_id asc, effective_from desc").Limit(limit).Offset(offset).Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list commission rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *commissionRepository) ListRulesActiveAt(ctx context.Context, at time.Time) ([]domain.CommissionRule, error) {
+	rules, err := gorm.G[domain.CommissionRule](r.db).
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", at, at).
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list active commission rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *commissionRepository) DeleteRule(ctx context.Context, publicID string) error {
+	rows, err := gorm.G[domain.CommissionRule](r.db).Where("public_id = ?", publicID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete commission rule: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("repository: %w: %s", domain.ErrCommissionRuleNotFound, publicID)
+	}
+	return nil
+}
+
+// SeedRule creates rule unless its category and seller already have a rule
+// for any period, and reports whether it did.
+func (r *commissionRepository) SeedRule(ctx context.Context, rule *domain.CommissionRule) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if innerErr := lockRuleScope(tx, rule); innerErr != nil {
+			return innerErr
+		}
+
+		var existing int64
+		innerErr := tx.Model(&domain.CommissionRule{}).
+			Where("category_path = ? AND seller_id = ?", rule.CategoryPath, rule.SellerID).
+			Count(&existing).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not look for existing rules: %w", innerErr)
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		created = true
+		return tx.Create(rule).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to seed commission rule: %w", err)
+	}
+	return created, nil
+}
+
+// BackfillCommissions charges order lines from before commissions were
+// snapshotted at the rate of the marketplace-wide rule in force at at, and sums
+// their fulfillments again. The rule's fixed fee is left out, as it may be in
+// another currency than the order. It reports how many lines it charged.
+func (r *commissionRepository) BackfillCommissions(ctx context.Context, at time.Time) (int64, error) {
+	var charged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rule domain.CommissionRule
+		innerErr := tx.Where("category_path = '' AND seller_id = ''").
+			Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", at, at).
+			Take(&rule).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return nil
+		} else if innerErr != nil {
+			return fmt.Errorf("could not find the marketplace-wide rule: %w", innerErr)
+		}
+
+		var fulfillmentIDs []string
+		innerErr = tx.Raw(`
+			UPDATE order_items SET
+				commission_rule_id = ?,
+				commission_rate_bps = ?,
+				commission_amount_minor = ROUND(price_amount_minor * quantity * ? / 10000.0),
+				commission_currency = price_currency,
+				commission_fee_amount_minor = 0,
+				commission_fee_currency = price_currency
+			WHERE commission_rule_id IS NULL OR commission_rule_id = ''
+			RETURNING fulfillment_id`, rule.PublicID, rule.RateBps, rule.RateBps).
+			Scan(&fulfillmentIDs).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not charge order lines: %w", innerErr)
+		}
+		charged = int64(len(fulfillmentIDs))
+		if charged == 0 {
+			return nil
+		}
+
+		innerErr = tx.Exec(`
+			UPDATE fulfillments f SET
+				commission_amount_minor = (
+					SELECT COALESCE(SUM(oi.commission_amount_minor), 0) FROM order_items oi
+					WHERE oi.fulfillment_id = f.public_id
+				),
+				commission_currency = f.subtotal_currency
+			WHERE f.public_id IN ?`, fulfillmentIDs).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not sum fulfillment commissions: %w", innerErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to backfill commissions: %w", err)
+	}
+	return charged, nil
+}
+
+// lockRuleScope takes a transaction-scoped advisory lock on rule's category and
+// seller, serialising every writer for that scope.
+func lockRuleScope(tx *gorm.DB, rule *domain.CommissionRule) error {
+	err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "commission:"+rule.CategoryPath+"|"+rule.SellerID).Error
+	if err != nil {
+		return fmt.Errorf("could not lock commission rules: %w", err)
+	}
+	return nil
+}
+
+// checkRuleOverlap fails if another rule for the same category and seller is
+// in force at any point of rule's period. Writers for one category and seller
+// take an advisory lock first, so two overlapping rules cannot both slip in.
+func checkRuleOverlap(tx *gorm.DB, rule *domain.CommissionRule) error {
+	if err := lockRuleScope(tx, rule); err != nil {
+		return err
+	}
+
+	query := tx.Model(&domain.CommissionRule{}).
+		Where("category_path = ? AND seller_id = ? AND public_id <> ?", rule.CategoryPath, rule.SellerID, rule.PublicID).
+		Where("effective_to IS NULL OR effective_to > ?", rule.EffectiveFrom)
+	if rule.EffectiveTo != nil {
+		query = query.Where("effective_from < ?", *rule.EffectiveTo)
+	}
+
+	var clash domain.CommissionRule
+	err := query.Take(&clash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not check for overlapping rules: %w", err)
+	}
+	return fmt.Errorf("%w: %s", domain.ErrCommissionRuleOverlap, clash.PublicID)
+}
@@ -14,6 +14,7 @@ type CustomerRepository interface {
 	CreateProfile(ctx context.Context, profile *domain.CustomerProfile) error
 	GetProfile(ctx context.Context, userID string) (*domain.CustomerProfile, error)
 	AddAddress(ctx context.Context, address *domain.Address) error
+	WithTx(tx *gorm.DB) CustomerRepository
 }
 
 type customerRepository struct {
@@ -24,6 +25,10 @@ func NewCustomerRepository(db *gorm.DB) CustomerRepository {
 	return &customerRepository{db: db}
 }
 
+func (r *customerRepository) WithTx(tx *gorm.DB) CustomerRepository {
+	return &customerRepository{db: tx}
+}
+
 func (r *customerRepository) CreateProfile(ctx context.Context, profile *domain.CustomerProfile) error {
 	err := r.db.WithContext(ctx).
 		Where("user_id = ?", profile.UserID).
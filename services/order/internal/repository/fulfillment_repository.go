@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fulfillmentTimestamps names the column that records when a fulfillment
+// reached a milestone.
+var fulfillmentTimestamps = map[domain.FulfillmentStatus]string{
+	domain.FulfillmentAccepted:    "accepted_at",
+	domain.FulfillmentRejected:    "rejected_at",
+	domain.FulfillmentPacked:      "packed_at",
+	domain.FulfillmentReadyToShip: "ready_to_ship_at",
+	domain.FulfillmentShipped:     "shipped_at",
+	domain.FulfillmentDelivered:   "delivered_at",
+}
+
+var rollUpReasons = map[domain.OrderStatus]string{
+	domain.OrderConfirmed:      "accepted by all sellers",
+	domain.OrderPacked:         "packed by all sellers",
+	domain.OrderShipped:        "picked up by courier",
+	domain.OrderOutForDelivery: "out for delivery",
+	domain.OrderDelivered:      "delivered",
+	domain.OrderCancelled:      "rejected by all sellers",
+}
+
+type FulfillmentRepository interface {
+	ListSellerFulfillments(ctx context.Context, sellerID string, status domain.FulfillmentStatus, limit int, offset int) ([]domain.Fulfillment, error)
+	GetSellerFulfillment(ctx context.Context, publicID string, sellerID string) (*domain.Fulfillment, error)
+	TransitionFulfillment(ctx context.Context, publicID string, sellerID string, to domain.FulfillmentStatus, actor string, reason string) (*domain.Fulfillment, *domain.Order, error)
+	WithTx(tx *gorm.DB) FulfillmentRepository
+}
+
+type fulfillmentRepository struct {
+	db *gorm.DB
+}
+
+func NewFulfillmentRepository(db *gorm.DB) FulfillmentRepository {
+	return &fulfillmentRepository{db: db}
+}
+
+func (r *fulfillmentRepository) WithTx(tx *gorm.DB) FulfillmentRepository {
+	return &fulfillmentRepository{db: tx}
+}
+
+// ListSellerFulfillments returns the seller's newest fulfillments first. An
+// empty status lists everything except orders that have not been paid yet.
+func (r *fulfillmentRepository) ListSellerFulfillments(ctx context.Context, sellerID string, status domain.FulfillmentStatus, limit int, offset int) ([]domain.Fulfillment, error) {
+	query := gorm.G[domain.Fulfillment](r.db).Where("seller_id = ?", sellerID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status <> ?", domain.FulfillmentAwaitingPayment)
+	}
+
+	fulfillments, err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list seller fulfillments: %w", err)
+	}
+
+	if err = r.loadItems(ctx, fulfillments); err != nil {
+		return nil, err
+	}
+	return fulfillments, nil
+}
+
+func (r *fulfillmentRepository) GetSellerFulfillment(ctx context.Context, publicID string, sellerID string) (*domain.Fulfillment, error) {
+	fulfillment, err := gorm.G[domain.Fulfillment](r.db).
+		Where("public_id = ? AND seller_id = ?", publicID, sellerID).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrFulfillmentNotFound, publicID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get fulfillment: %w", err)
+	}
+
+	fulfillments := []domain.Fulfillment{fulfillment}
+	if err = r.loadItems(ctx, fulfillments); err != nil {
+		return nil, err
+	}
+	return &fulfillments[0], nil
+}
+
+// TransitionFulfillment applies a seller's or courier's action to a fulfillment
+// and, in the same transaction, moves the order along once every seller has
+// caught up. An empty sellerID skips the ownership check, for updates that come
+// from the logistics service. The order row is locked first, as
+// TransitionStatus does, so concurrent updates on one order are serialised.
+func (r *fulfillmentRepository) TransitionFulfillment(ctx context.Context, publicID string, sellerID string, to domain.FulfillmentStatus, actor string, reason string) (*domain.Fulfillment, *domain.Order, error) {
+	var fulfillment domain.Fulfillment
+	var order domain.Order
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("public_id = ?", publicID)
+		if sellerID != "" {
+			query = query.Where("seller_id = ?", sellerID)
+		}
+
+		innerErr := query.Take(&fulfillment).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrFulfillmentNotFound, publicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not get fulfillment: %w", innerErr)
+		}
+
+		innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", fulfillment.OrderID).
+			Take(&order).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not lock order: %w", innerErr)
+		}
+
+		innerErr = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", fulfillment.ID).
+			Take(&fulfillment).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not lock fulfillment: %w", innerErr)
+		}
+
+		if !fulfillment.Status.CanTransitionTo(to) {
+			return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidFulfillmentTransition, fulfillment.Status, to)
+		}
+
+		now := time.Now()
+		updates := map[string]any{"status": to, "status_reason": reason}
+		if column, ok := fulfillmentTimestamps[to]; ok {
+			updates[column] = now
+		}
+
+		innerErr = tx.Model(&domain.Fulfillment{}).Where("id = ?", fulfillment.ID).Updates(updates).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not update fulfillment status: %w", innerErr)
+		}
+
+		var siblings []domain.Fulfillment
+		innerErr = tx.Where("order_id = ?", order.PublicID).Find(&siblings).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not load order fulfillments: %w", innerErr)
+		}
+
+		for _, step := range domain.OrderStatusAfterFulfillments(order.Status, siblings) {
+			innerErr = transitionOrder(tx, &order, step, actor, rollUpReasons[step])
+			if innerErr != nil {
+				return innerErr
+			}
+		}
+
+		innerErr = tx.Where("id = ?", fulfillment.ID).Take(&fulfillment).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not reload fulfillment: %w", innerErr)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository: failed to transition fulfillment: %w", err)
+	}
+
+	fulfillments := []domain.Fulfillment{fulfillment}
+	if err = r.loadItems(ctx, fulfillments); err != nil {
+		return nil, nil, err
+	}
+	return &fulfillments[0], &order, nil
+}
+
+func (r *fulfillmentRepository) loadItems(ctx context.Context, fulfillments []domain.Fulfillment) error {
+	if len(fulfillments) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(fulfillments))
+	for _, f := range fulfillments {
+		ids = append(ids, f.PublicID)
+	}
+
+	items, err := gorm.G[domain.OrderItem](r.db).Where("fulfillment_id IN ?", ids).Find(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: failed to load fulfillment items: %w", err)
+	}
+
+	byFulfillment := make(map[string][]domain.OrderItem)
+	for _, item := range items {
+		byFulfillment[item.FulfillmentID] = append(byFulfillment[item.FulfillmentID], item)
+	}
+	for i := range fulfillments {
+		fulfillments[i].Items = byFulfillment[fulfillments[i].PublicID]
+	}
+	return nil
+}
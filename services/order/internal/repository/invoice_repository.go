@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/invoice"
+
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	IssueInvoice(ctx context.Context, inv *domain.Invoice) (*domain.Invoice, error)
+	GetOrderInvoices(ctx context.Context, orderID string) ([]domain.Invoice, error)
+	SetPDFURL(ctx context.Context, id string, url string) error
+	MarkEmailed(ctx context.Context, id string, at time.Time) error
+	ListOrdersAwaitingInvoices(ctx context.Context, paidAfter time.Time, limit int) ([]string, error)
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// IssueInvoice stores inv under the seller's next invoice number. If the seller
+// already invoiced the order, that invoice is returned unchanged instead.
+func (r *invoiceRepository) IssueInvoice(ctx context.Context, inv *domain.Invoice) (*domain.Invoice, error) {
+	var issued domain.Invoice
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, innerErr := gorm.G[domain.Invoice](tx).
+			Where("order_id = ? AND seller_id = ?", inv.OrderID, inv.SellerID).
+			First(ctx)
+		if innerErr == nil {
+			issued = existing
+			return nil
+		}
+		if !errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return innerErr
+		}
+
+		fiscalYear := invoice.FiscalYear(inv.IssuedAt)
+
+		var sequence int64
+		innerErr = tx.Raw(`
+			INSERT INTO invoice_sequences (seller_id, fiscal_year, last_number) VALUES (?, ?, 1)
+			ON CONFLICT (seller_id, fiscal_year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+			RETURNING last_number`, inv.SellerID, fiscalYear).
+			Scan(&sequence).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not allocate invoice number: %w", innerErr)
+		}
+
+		inv.Number = invoice.FormatNumber(fiscalYear, sequence)
+		innerErr = gorm.G[domain.Invoice](tx).Create(ctx, inv)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		issued = *inv
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to issue invoice: %w", err)
+	}
+	return &issued, nil
+}
+
+func (r *invoiceRepository) GetOrderInvoices(ctx context.Context, orderID string) ([]domain.Invoice, error) {
+	invoices, err := gorm.G[domain.Invoice](r.db).
+		Where("order_id = ?", orderID).
+		Order("issued_at asc").
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get order invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepository) SetPDFURL(ctx context.Context, id string, url string) error {
+	_, err := gorm.G[domain.Invoice](r.db).Where("id = ?", id).Update(ctx, "pdf_url", url)
+	if err != nil {
+		return fmt.Errorf("repository: failed to save invoice pdf url: %w", err)
+	}
+	return nil
+}
+
+func (r *invoiceRepository) MarkEmailed(ctx context.Context, id string, at time.Time) error {
+	_, err := gorm.G[domain.Invoice](r.db).Where("id = ?", id).Update(ctx, "emailed_at", at)
+	if err != nil {
+		return fmt.Errorf("repository: failed to mark invoice emailed: %w", err)
+	}
+	return nil
+}
+
+// ListOrdersAwaitingInvoices returns paid orders with a seller who has not
+// invoiced yet, or with an invoice that was not stored or mailed.
+func (r *invoiceRepository) ListOrdersAwaitingInvoices(ctx context.Context, paidAfter time.Time, limit int) ([]string, error) {
+	var orderIDs []string
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT o.public_id FROM orders o
+		WHERE o.status IN ? AND o.updated_at > ? AND o.deleted_at IS NULL
+		AND (
+			(SELECT COUNT(DISTINCT oi.seller_id) FROM order_items oi WHERE oi.order_id = o.id AND oi.seller_id <> '')
+				> (SELECT COUNT(*) FROM invoices i WHERE i.order_id = o.public_id)
+			OR EXISTS (
+				SELECT 1 FROM invoices i WHERE i.order_id = o.public_id
+				AND (i.pdf_url = '' OR i.pdf_url IS NULL OR (i.emailed_at IS NULL AND i.buyer_email <> ''))
+			)
+		)
+		ORDER BY o.updated_at ASC
+		LIMIT ?`, domain.InvoicedStatuses, paidAfter, limit).
+		Scan(&orderIDs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list orders awaiting invoices: %w", err)
+	}
+	return orderIDs, nil
+}
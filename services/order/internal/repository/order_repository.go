@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"ecommerce/services/order/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
@@ -14,6 +17,12 @@ type OrderRepository interface {
 	GetOrderByPublicID(ctx context.Context, publicID string) (*domain.Order, error)
 	GetUserOrders(ctx context.Context, userID string) ([]domain.Order, error)
 	UpdateOrder(ctx context.Context, order *domain.Order) error
+	TransitionStatus(ctx context.Context, publicID string, to domain.OrderStatus, actor string, reason string) (*domain.Order, bool, error)
+	GetStatusHistory(ctx context.Context, publicID string) ([]domain.OrderStatusHistory, error)
+	AddStatusNote(ctx context.Context, publicID string, actor string, note string) error
+	MarkStockCommitted(ctx context.Context, publicID string) error
+	ListOrdersAwaitingStockCommit(ctx context.Context, createdAfter time.Time, limit int) ([]string, error)
+	WithTx(tx *gorm.DB) OrderRepository
 }
 
 type orderRepository struct {
@@ -24,9 +33,23 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
+func (r *orderRepository) WithTx(tx *gorm.DB) OrderRepository {
+	return &orderRepository{db: tx}
+}
+
 func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		return gorm.G[domain.Order](tx).Create(ctx, order)
+		innerErr := gorm.G[domain.Order](tx).Create(ctx, order)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		return gorm.G[domain.OrderStatusHistory](tx).Create(ctx, &domain.OrderStatusHistory{
+			OrderID:  order.PublicID,
+			ToStatus: order.Status,
+			Actor:    domain.ActorCustomer,
+			Reason:   "order placed",
+		})
 	})
 
 	if err != nil {
@@ -38,6 +61,7 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 func (r *orderRepository) GetOrderByPublicID(ctx context.Context, publicID string) (*domain.Order, error) {
 	order, err := gorm.G[domain.Order](r.db).
 		Preload("Items", nil).
+		Preload("Fulfillments", nil).
 		Where("public_id = ?", publicID).
 		First(ctx)
 
@@ -60,6 +84,33 @@ func (r *orderRepository) GetUserOrders(ctx context.Context, userID string) ([]d
 	return orders, nil
 }
 
+func (r *orderRepository) MarkStockCommitted(ctx context.Context, publicID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&domain.Order{}).
+		Where("public_id = ? AND stock_committed_at IS NULL", publicID).
+		Update("stock_committed_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("repository: failed to mark order stock committed: %w", err)
+	}
+	return nil
+}
+
+// ListOrdersAwaitingStockCommit returns paid orders whose held units have not
+// been turned into a sale yet, oldest first.
+func (r *orderRepository) ListOrdersAwaitingStockCommit(ctx context.Context, createdAfter time.Time, limit int) ([]string, error) {
+	var orderIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.Order{}).
+		Where("status IN ? AND stock_committed_at IS NULL AND created_at > ?", domain.InvoicedStatuses, createdAfter).
+		Order("created_at asc").
+		Limit(limit).
+		Pluck("public_id", &orderIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list orders awaiting stock commit: %w", err)
+	}
+	return orderIDs, nil
+}
+
 func (r *orderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
 	_, err := gorm.G[*domain.Order](r.db).Where("public_id = ?", order.PublicID).Updates(ctx, order)
 	if err != nil {
@@ -67,3 +118,112 @@ func (r *orderRepository) UpdateOrder(ctx context.Context, order *domain.Order)
 	}
 	return nil
 }
+
+// TransitionStatus locks the order row, validates the move against the order
+// lifecycle and records it in the status history within one transaction.
+// Moving to the status the order already has is a no-op so redelivered events
+// stay harmless; changed is false then.
+func (r *orderRepository) TransitionStatus(ctx context.Context, publicID string, to domain.OrderStatus, actor string, reason string) (*domain.Order, bool, error) {
+	var order domain.Order
+	changed := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			Take(&order).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrOrderNotFound, publicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock order: %w", innerErr)
+		}
+
+		if order.Status == to {
+			return nil
+		}
+		changed = true
+		return transitionOrder(tx, &order, to, actor, reason)
+	})
+
+	if err != nil {
+		return nil, false, fmt.Errorf("repository: failed to transition order status: %w", err)
+	}
+	return &order, changed, nil
+}
+
+// transitionOrder moves a locked order to a new status inside tx. The seller
+// fulfillments follow: payment opens them for approval and cancellation or a
+// refund closes whatever is still open.
+func transitionOrder(tx *gorm.DB, order *domain.Order, to domain.OrderStatus, actor string, reason string) error {
+	if !order.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidTransition, order.Status, to)
+	}
+
+	err := tx.Model(&domain.Order{}).
+		Where("id = ?", order.ID).
+		Update("status", to).Error
+	if err != nil {
+		return fmt.Errorf("could not update order status: %w", err)
+	}
+
+	err = tx.Create(&domain.OrderStatusHistory{
+		OrderID:    order.PublicID,
+		FromStatus: order.Status,
+		ToStatus:   to,
+		Actor:      actor,
+		Reason:     reason,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("could not record status history: %w", err)
+	}
+
+	switch to {
+	case domain.OrderPaid:
+		err = tx.Model(&domain.Fulfillment{}).
+			Where("order_id = ? AND status = ?", order.PublicID, domain.FulfillmentAwaitingPayment).
+			Update("status", domain.FulfillmentPending).Error
+	case domain.OrderCancelled, domain.OrderRefunded:
+		err = tx.Model(&domain.Fulfillment{}).
+			Where("order_id = ? AND status NOT IN ?", order.PublicID, []domain.FulfillmentStatus{domain.FulfillmentRejected, domain.FulfillmentCancelled}).
+			Updates(map[string]any{"status": domain.FulfillmentCancelled, "status_reason": reason}).Error
+	}
+	if err != nil {
+		return fmt.Errorf("could not update order fulfillments: %w", err)
+	}
+
+	order.Status = to
+	return nil
+}
+
+// AddStatusNote records an event on the order timeline without changing its status.
+func (r *orderRepository) AddStatusNote(ctx context.Context, publicID string, actor string, note string) error {
+	order, err := gorm.G[domain.Order](r.db).Where("public_id = ?", publicID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("repository: %w: %s", domain.ErrOrderNotFound, publicID)
+	} else if err != nil {
+		return fmt.Errorf("repository: failed to get order: %w", err)
+	}
+
+	err = gorm.G[domain.OrderStatusHistory](r.db).Create(ctx, &domain.OrderStatusHistory{
+		OrderID:    order.PublicID,
+		FromStatus: order.Status,
+		ToStatus:   order.Status,
+		Actor:      actor,
+		Reason:     note,
+	})
+	if err != nil {
+		return fmt.Errorf("repository: failed to add order note: %w", err)
+	}
+	return nil
+}
+
+func (r *orderRepository) GetStatusHistory(ctx context.Context, publicID string) ([]domain.OrderStatusHistory, error) {
+	history, err := gorm.G[domain.OrderStatusHistory](r.db).
+		Where("order_id = ?", publicID).
+		Order("created_at asc").
+		Find(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to fetch order status history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// returnTimestamps names the column that records when a return reached a
+// milestone.
+var returnTimestamps = map[domain.ReturnStatus]string{
+	domain.ReturnApproved: "approved_at",
+	domain.ReturnRejected: "rejected_at",
+	domain.ReturnPickedUp: "picked_up_at",
+	domain.ReturnReceived: "received_at",
+}
+
+type ReturnRepository interface {
+	CreateReturns(ctx context.Context, orderID string, returns []domain.Return) error
+	ListOrderReturns(ctx context.Context, orderID string) ([]domain.Return, error)
+	ListSellerReturns(ctx context.Context, sellerID string, status domain.ReturnStatus, limit int, offset int) ([]domain.Return, error)
+	GetSellerReturn(ctx context.Context, publicID string, sellerID string) (*domain.Return, error)
+	TransitionReturn(ctx context.Context, publicID string, sellerID string, to domain.ReturnStatus, actor string, note string) (*domain.Return, error)
+	WithTx(tx *gorm.DB) ReturnRepository
+}
+
+type returnRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnRepository(db *gorm.DB) ReturnRepository {
+	return &returnRepository{db: db}
+}
+
+func (r *returnRepository) WithTx(tx *gorm.DB) ReturnRepository {
+	return &returnRepository{db: tx}
+}
+
+// CreateReturns saves a buyer's return requests for an order. The order row is
+// locked while the quantities are checked, so two requests racing for the same
+// line cannot return more units than were bought.
+func (r *returnRepository) CreateReturns(ctx context.Context, orderID string, returns []domain.Return) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order domain.Order
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", orderID).
+			Take(&order).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrOrderNotFound, orderID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock order: %w", innerErr)
+		}
+
+		var items []domain.OrderItem
+		innerErr = tx.Where("order_id = ?", order.ID).Find(&items).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not load order items: %w", innerErr)
+		}
+		remaining := make(map[string]int, len(items))
+		for _, item := range items {
+			remaining[item.ID] = item.Quantity
+		}
+
+		var returned []struct {
+			OrderItemID string
+			Quantity    int
+		}
+		innerErr = tx.Model(&domain.ReturnItem{}).
+			Select("return_items.order_item_id, SUM(return_items.quantity) AS quantity").
+			Joins("JOIN returns ON returns.public_id = return_items.return_id").
+			Where("returns.order_id = ? AND returns.status <> ?", orderID, domain.ReturnRejected).
+			Group("return_items.order_item_id").
+			Scan(&returned).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not load returned quantities: %w", innerErr)
+		}
+		for _, line := range returned {
+			remaining[line.OrderItemID] -= line.Quantity
+		}
+
+		for _, ret := range returns {
+			for _, item := range ret.Items {
+				remaining[item.OrderItemID] -= item.Quantity
+				if remaining[item.OrderItemID] < 0 {
+					return fmt.Errorf("%w: %s has fewer units left to return", domain.ErrReturnNotAllowed, item.ProductID)
+				}
+			}
+		}
+
+		for i := range returns {
+			innerErr = tx.Create(&returns[i]).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not save return: %w", innerErr)
+			}
+
+			innerErr = tx.Create(&domain.OrderStatusHistory{
+				OrderID:    order.PublicID,
+				FromStatus: order.Status,
+				ToStatus:   order.Status,
+				Actor:      domain.ActorCustomer,
+				Reason:     "return " + returns[i].PublicID + " requested",
+			}).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not record return on order timeline: %w", innerErr)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to create returns: %w", err)
+	}
+	return nil
+}
+
+func (r *returnRepository) ListOrderReturns(ctx context.Context, orderID string) ([]domain.Return, error) {
+	returns, err := gorm.G[domain.Return](r.db).
+		Preload("Items", nil).
+		Where("order_id = ?", orderID).
+		Order("created_at desc").
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list order returns: %w", err)
+	}
+	return returns, nil
+}
+
+func (r *returnRepository) ListSellerReturns(ctx context.Context, sellerID string, status domain.ReturnStatus, limit int, offset int) ([]domain.Return, error) {
+	query := gorm.G[domain.Return](r.db).Preload("Items", nil).Where("seller_id = ?", sellerID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	returns, err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list seller returns: %w", err)
+	}
+	return returns, nil
+}
+
+func (r *returnRepository) GetSellerReturn(ctx context.Context, publicID string, sellerID string) (*domain.Return, error) {
+	ret, err := gorm.G[domain.Return](r.db).
+		Preload("Items", nil).
+		Where("public_id = ? AND seller_id = ?", publicID, sellerID).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrReturnNotFound, publicID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: failed to get return: %w", err)
+	}
+	return &ret, nil
+}
+
+// TransitionReturn moves a return along and notes it on the order timeline.
+// An empty sellerID skips the ownership check, for updates that come from the
+// logistics service.
+func (r *returnRepository) TransitionReturn(ctx context.Context, publicID string, sellerID string, to domain.ReturnStatus, actor string, note string) (*domain.Return, error) {
+	var ret domain.Return
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("public_id = ?", publicID)
+		if sellerID != "" {
+			query = query.Where("seller_id = ?", sellerID)
+		}
+
+		innerErr := query.Take(&ret).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", domain.ErrReturnNotFound, publicID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock return: %w", innerErr)
+		}
+
+		if !ret.Status.CanTransitionTo(to) {
+			return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidReturnTransition, ret.Status, to)
+		}
+
+		updates := map[string]any{"status": to}
+		if column, ok := returnTimestamps[to]; ok {
+			updates[column] = time.Now()
+		}
+		if to == domain.ReturnRejected {
+			updates["seller_note"] = note
+		}
+
+		innerErr = tx.Model(&domain.Return{}).Where("id = ?", ret.ID).Updates(updates).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not update return status: %w", innerErr)
+		}
+
+		var order domain.Order
+		innerErr = tx.Where("public_id = ?", ret.OrderID).Take(&order).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not get order: %w", innerErr)
+		}
+
+		reason := "return " + ret.PublicID + " " + string(to)
+		if note != "" {
+			reason += ": " + note
+		}
+		innerErr = tx.Create(&domain.OrderStatusHistory{
+			OrderID:    order.PublicID,
+			FromStatus: order.Status,
+			ToStatus:   order.Status,
+			Actor:      actor,
+			Reason:     reason,
+		}).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not record return on order timeline: %w", innerErr)
+		}
+
+		return tx.Preload("Items").Where("id = ?", ret.ID).Take(&ret).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to transition return: %w", err)
+	}
+	return &ret, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type SagaRepository interface {
+	CreateSaga(ctx context.Context, saga *domain.CheckoutSaga) error
+	SaveSaga(ctx context.Context, saga *domain.CheckoutSaga) error
+	ListStaleSagas(ctx context.Context, status string, olderThan time.Time, limit int) ([]domain.CheckoutSaga, error)
+}
+
+type sagaRepository struct {
+	db *gorm.DB
+}
+
+func NewSagaRepository(db *gorm.DB) SagaRepository {
+	return &sagaRepository{db: db}
+}
+
+func (r *sagaRepository) CreateSaga(ctx context.Context, saga *domain.CheckoutSaga) error {
+	err := gorm.G[domain.CheckoutSaga](r.db).Create(ctx, saga)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create checkout saga: %w", err)
+	}
+	return nil
+}
+
+func (r *sagaRepository) SaveSaga(ctx context.Context, saga *domain.CheckoutSaga) error {
+	err := r.db.WithContext(ctx).Save(saga).Error
+	if err != nil {
+		return fmt.Errorf("repository: failed to save checkout saga: %w", err)
+	}
+	return nil
+}
+
+func (r *sagaRepository) ListStaleSagas(ctx context.Context, status string, olderThan time.Time, limit int) ([]domain.CheckoutSaga, error) {
+	sagas, err := gorm.G[domain.CheckoutSaga](r.db).
+		Where("status = ? AND updated_at < ?", status, olderThan).
+		Order("updated_at asc").
+		Limit(limit).
+		Find(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list stale sagas: %w", err)
+	}
+	return sagas, nil
+}
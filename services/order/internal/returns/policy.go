@@ -0,0 +1,111 @@
+package returns
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultWindowDays applies to categories no rule matches.
+const DefaultWindowDays = 7
+
+// Rule sets how many days after delivery a buyer may return items from a
+// category and everything beneath it. CategoryPath works as it does for GST
+// slabs, e.g. "cat_fashion" or "cat_electronics.cat_mobiles". A window of zero
+// makes the category non-returnable.
+type Rule struct {
+	CategoryPath string `json:"category_path"`
+	WindowDays   int    `json:"window_days"`
+}
+
+type Policy struct {
+	defaultDays int
+	byPath      map[string]int
+}
+
+func NewPolicy(defaultDays int, rules []Rule) (*Policy, error) {
+	if defaultDays < 0 {
+		return nil, fmt.Errorf("returns: invalid default window of %d days", defaultDays)
+	}
+
+	p := &Policy{defaultDays: defaultDays, byPath: make(map[string]int, len(rules))}
+	for _, rule := range rules {
+		path := strings.Trim(strings.TrimSpace(rule.CategoryPath), ".")
+		if path == "" {
+			return nil, fmt.Errorf("returns: rule without category path")
+		}
+		if rule.WindowDays < 0 {
+			return nil, fmt.Errorf("returns: invalid window of %d days for %s", rule.WindowDays, path)
+		}
+		p.byPath[path] = rule.WindowDays
+	}
+	return p, nil
+}
+
+type policyFile struct {
+	DefaultWindowDays *int   `json:"default_window_days"`
+	Rules             []Rule `json:"rules"`
+}
+
+// LoadPolicy reads a JSON return policy:
+//
+//	{"default_window_days": 7, "rules": [{"category_path": "cat_grocery", "window_days": 0}]}
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("returns: failed to read policy file: %w", err)
+	}
+
+	var file policyFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("returns: failed to parse policy file %s: %w", path, err)
+	}
+
+	defaultDays := DefaultWindowDays
+	if file.DefaultWindowDays != nil {
+		defaultDays = *file.DefaultWindowDays
+	}
+	return NewPolicy(defaultDays, file.Rules)
+}
+
+// WindowDays returns the window of the most specific rule covering
+// categoryPath.
+func (p *Policy) WindowDays(categoryPath string) int {
+	path := strings.Trim(categoryPath, ".")
+	for path != "" {
+		if days, ok := p.byPath[path]; ok {
+			return days
+		}
+		cut := strings.LastIndex(path, ".")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return p.defaultDays
+}
+
+// Deadline is the last moment an item delivered at deliveredAt can be
+// returned. ok is false for non-returnable categories.
+func (p *Policy) Deadline(categoryPath string, deliveredAt time.Time) (deadline time.Time, ok bool) {
+	days := p.WindowDays(categoryPath)
+	if days == 0 {
+		return time.Time{}, false
+	}
+	return deliveredAt.AddDate(0, 0, days), true
+}
+
+// WindowClosesAt is when the last return window among the given categories
+// closes for items delivered at deliveredAt. Non-returnable categories close
+// on delivery.
+func (p *Policy) WindowClosesAt(categoryPaths []string, deliveredAt time.Time) time.Time {
+	closesAt := deliveredAt
+	for _, path := range categoryPaths {
+		if deadline, ok := p.Deadline(path, deliveredAt); ok && deadline.After(closesAt) {
+			closesAt = deadline
+		}
+	}
+	return closesAt
+}
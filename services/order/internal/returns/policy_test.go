@@ -0,0 +1,103 @@
+package returns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWindowDays(t *testing.T) {
+	policy, err := NewPolicy(DefaultWindowDays, []Rule{
+		{CategoryPath: "cat_grocery", WindowDays: 0},
+		{CategoryPath: "cat_fashion", WindowDays: 30},
+		{CategoryPath: "cat_fashion.cat_innerwear", WindowDays: 0},
+		{CategoryPath: ".cat_electronics.", WindowDays: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		days int
+	}{
+		{"cat_fashion.cat_shoes", 30},
+		{"cat_fashion.cat_innerwear.cat_socks", 0},
+		{"cat_electronics", 10},
+		{"cat_grocery.cat_snacks", 0},
+		{"cat_toys", DefaultWindowDays},
+		{"", DefaultWindowDays},
+	}
+	for _, tt := range tests {
+		if got := policy.WindowDays(tt.path); got != tt.days {
+			t.Errorf("WindowDays(%q) = %d, want %d", tt.path, got, tt.days)
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	policy, err := NewPolicy(7, []Rule{{CategoryPath: "cat_grocery", WindowDays: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := time.Date(2026, 3, 28, 15, 0, 0, 0, time.UTC)
+	deadline, ok := policy.Deadline("cat_toys", delivered)
+	if !ok || !deadline.Equal(time.Date(2026, 4, 4, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Deadline = %v, %v; want 2026-04-04 15:00, true", deadline, ok)
+	}
+
+	if _, ok = policy.Deadline("cat_grocery.cat_dairy", delivered); ok {
+		t.Error("grocery should not be returnable")
+	}
+}
+
+func TestWindowClosesAt(t *testing.T) {
+	policy, err := NewPolicy(7, []Rule{
+		{CategoryPath: "cat_grocery", WindowDays: 0},
+		{CategoryPath: "cat_fashion", WindowDays: 30},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	if got := policy.WindowClosesAt([]string{"cat_books", "cat_fashion.cat_shoes", "cat_grocery"}, delivered); !got.Equal(delivered.AddDate(0, 0, 30)) {
+		t.Errorf("mixed order closes at %v, want the 30 day fashion window", got)
+	}
+	if got := policy.WindowClosesAt([]string{"cat_grocery"}, delivered); !got.Equal(delivered) {
+		t.Errorf("non-returnable order closes at %v, want delivery time", got)
+	}
+}
+
+func TestNewPolicyRejectsBadRules(t *testing.T) {
+	if _, err := NewPolicy(-1, nil); err == nil {
+		t.Error("expected an error for a negative default window")
+	}
+	if _, err := NewPolicy(7, []Rule{{CategoryPath: " ", WindowDays: 3}}); err == nil {
+		t.Error("expected an error for a rule without a category path")
+	}
+	if _, err := NewPolicy(7, []Rule{{CategoryPath: "cat_toys", WindowDays: -2}}); err == nil {
+		t.Error("expected an error for a negative window")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "returns.json")
+	err := os.WriteFile(path, []byte(`{"rules": [{"category_path": "cat_fashion", "window_days": 30}]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := policy.WindowDays("cat_books"); got != DefaultWindowDays {
+		t.Errorf("default window = %d, want %d", got, DefaultWindowDays)
+	}
+	if got := policy.WindowDays("cat_fashion.cat_shirts"); got != 30 {
+		t.Errorf("fashion window = %d, want 30", got)
+	}
+}
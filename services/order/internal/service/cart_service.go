@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	"ecommerce/pkg/fx"
+	"ecommerce/pkg/money"
 
 	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/repository"
@@ -13,14 +17,16 @@ type CartService interface {
 	AddItem(ctx context.Context, userID string, item domain.CartItem) (*domain.Cart, error)
 	RemoveItem(ctx context.Context, userID string, productID string) (*domain.Cart, error)
 	ClearCart(ctx context.Context, userID string) error
+	SetCurrency(ctx context.Context, userID string, currency string) (*domain.Cart, error)
 }
 
 type cartService struct {
 	cartRepo repository.CartRepository
+	rates    fx.RateSource
 }
 
-func NewCartService(cartRepo repository.CartRepository) CartService {
-	return &cartService{cartRepo: cartRepo}
+func NewCartService(cartRepo repository.CartRepository, rates fx.RateSource) CartService {
+	return &cartService{cartRepo: cartRepo, rates: rates}
 }
 
 func (s *cartService) GetCart(ctx context.Context, userID string) (*domain.Cart, error) {
@@ -81,3 +87,30 @@ func (s *cartService) RemoveItem(ctx context.Context, userID string, productID s
 func (s *cartService) ClearCart(ctx context.Context, userID string) error {
 	return s.cartRepo.ClearCart(ctx, userID)
 }
+
+// SetCurrency selects the currency the cart is charged in at checkout. Only
+// currencies with a rate from the catalog currency are accepted.
+func (s *cartService) SetCurrency(ctx context.Context, userID string, currency string) (*domain.Cart, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return nil, fmt.Errorf("service: %w: %q", fx.ErrRateNotFound, currency)
+	}
+
+	_, _, err := fx.Convert(ctx, s.rates, money.Zero(money.DefaultCurrency), currency)
+	if err != nil {
+		return nil, fmt.Errorf("service: unsupported currency: %w", err)
+	}
+
+	cart, err := s.cartRepo.GetCart(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get cart: %w", err)
+	}
+
+	cart.Currency = currency
+	err = s.cartRepo.SaveCart(ctx, cart)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to save cart: %w", err)
+	}
+
+	return cart, nil
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/fx"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"ecommerce/services/order/internal/client"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/repository"
+	"ecommerce/services/order/internal/tax"
+
+	pb "ecommerce/pkg/protobufs/catalog"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// stockReservationTTL must outlive the payment session (30 minutes) so a buyer
+	// who pays at the last moment still finds their units held.
+	stockReservationTTL = 35 * time.Minute
+
+	compensationTimeout     = 15 * time.Second
+	maxCompensationAttempts = 10
+)
+
+type CheckoutOrchestrator interface {
+	Run(ctx context.Context, saga *domain.CheckoutSaga) (*domain.Order, string, error)
+	Recover(ctx context.Context, staleAfter time.Duration) (int, error)
+}
+
+// checkoutRun carries values produced by one step that later steps need but
+// that are not worth persisting in the saga state.
+type checkoutRun struct {
+	saga  *domain.CheckoutSaga
+	order *domain.Order
+}
+
+type sagaStep struct {
+	name       string
+	execute    func(ctx context.Context, run *checkoutRun) error
+	compensate func(ctx context.Context, run *checkoutRun) error
+}
+
+type checkoutOrchestrator struct {
+	sagaRepo      repository.SagaRepository
+	orderRepo     repository.OrderRepository
+	catalogClient pb.CatalogServiceClient
+	paymentClient client.PaymentService
+	rates         fx.RateSource
+	taxes         *tax.Engine
+	commissions   CommissionService
+
+	steps []sagaStep
+}
+
+func NewCheckoutOrchestrator(
+	sagaRepo repository.SagaRepository,
+	orderRepo repository.OrderRepository,
+	catalogClient pb.CatalogServiceClient,
+	paymentClient client.PaymentService,
+	rates fx.RateSource,
+	taxes *tax.Engine,
+	commissions CommissionService,
+) CheckoutOrchestrator {
+	o := &checkoutOrchestrator{
+		sagaRepo:      sagaRepo,
+		orderRepo:     orderRepo,
+		catalogClient: catalogClient,
+		paymentClient: paymentClient,
+		rates:         rates,
+		taxes:         taxes,
+		commissions:   commissions,
+	}
+
+	o.steps = []sagaStep{
+		{name: "validate_prices", execute: o.validatePrices, compensate: noCompensation},
+		{name: "reserve_stock", execute: o.reserveStock, compensate: o.releaseStock},
+		{name: "create_order", execute: o.createOrder, compensate: o.cancelOrder},
+		{name: "create_payment_session", execute: o.createPaymentSession, compensate: o.cancelPaymentSession},
+	}
+	return o
+}
+
+func (o *checkoutOrchestrator) Run(ctx context.Context, saga *domain.CheckoutSaga) (*domain.Order, string, error) {
+	saga.Status = domain.SagaRunning
+	saga.StepIndex = 0
+
+	if err := o.sagaRepo.CreateSaga(ctx, saga); err != nil {
+		return nil, "", fmt.Errorf("service: failed to start checkout saga: %w", err)
+	}
+
+	run := &checkoutRun{saga: saga}
+
+	for i, step := range o.steps {
+		saga.StepIndex = i
+		if err := o.sagaRepo.SaveSaga(ctx, saga); err != nil {
+			o.compensate(ctx, run, i-1)
+			return nil, "", fmt.Errorf("service: failed to persist checkout saga: %w", err)
+		}
+
+		if err := step.execute(ctx, run); err != nil {
+			saga.LastError = fmt.Sprintf("%s: %v", step.name, err)
+			// The failed step may have partly applied (e.g. a timeout after the
+			// remote side committed), so it is compensated along with its predecessors.
+			o.compensate(ctx, run, i)
+			return nil, "", err
+		}
+	}
+
+	saga.StepIndex = len(o.steps)
+	saga.Status = domain.SagaCompleted
+	if err := o.sagaRepo.SaveSaga(ctx, saga); err != nil {
+		// A saga left running is compensated by Recover, so the buyer must not
+		// get a payment link for it.
+		o.compensate(ctx, run, len(o.steps)-1)
+		return nil, "", fmt.Errorf("service: failed to mark checkout saga completed: %w", err)
+	}
+
+	return run.order, saga.State.PaymentURL, nil
+}
+
+// Recover picks up sagas abandoned by a crashed instance. A saga that was still
+// running is compensated rather than resumed, because finishing it would help
+// nobody. Run only hands out the payment link after the saga is saved as
+// completed, so the buyer of a running saga never got one, and their request
+// is gone, so they never will. Resuming would also not be safe: creating an
+// order and a payment session are not idempotent, so redoing the step that was
+// in flight could leave a second payment session open for the same order.
+func (o *checkoutOrchestrator) Recover(ctx context.Context, staleAfter time.Duration) (int, error) {
+	olderThan := time.Now().Add(-staleAfter)
+	recovered := 0
+
+	for _, status := range []string{domain.SagaRunning, domain.SagaCompensating} {
+		sagas, err := o.sagaRepo.ListStaleSagas(ctx, status, olderThan, 50)
+		if err != nil {
+			return recovered, fmt.Errorf("service: failed to list stale sagas: %w", err)
+		}
+
+		for i := range sagas {
+			saga := &sagas[i]
+			from := saga.StepIndex
+			if from >= len(o.steps) {
+				from = len(o.steps) - 1
+			}
+
+			logger.Info("service: recovering checkout saga",
+				zap.String("order_id", saga.OrderID),
+				zap.String("status", saga.Status),
+				zap.Int("step_index", saga.StepIndex),
+			)
+
+			if o.compensate(ctx, &checkoutRun{saga: saga}, from) {
+				recovered++
+			}
+		}
+	}
+
+	return recovered, nil
+}
+
+// compensate undoes steps from..0 in reverse order. Progress is saved before
+// each compensation so a crash midway resumes where it stopped. It reports
+// whether the saga reached the compensated state.
+func (o *checkoutOrchestrator) compensate(ctx context.Context, run *checkoutRun, from int) bool {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	saga := run.saga
+	saga.Status = domain.SagaCompensating
+
+	for i := from; i >= 0; i-- {
+		saga.StepIndex = i
+		if err := o.sagaRepo.SaveSaga(ctx, saga); err != nil {
+			logger.Error("service: failed to persist saga compensation progress", zap.String("order_id", saga.OrderID), zap.Error(err))
+			return false
+		}
+
+		step := o.steps[i]
+		if err := step.compensate(ctx, run); err != nil {
+			saga.Attempts++
+			saga.LastError = fmt.Sprintf("compensate %s: %v", step.name, err)
+			if saga.Attempts >= maxCompensationAttempts {
+				saga.Status = domain.SagaFailed
+			}
+
+			logger.Error("service: checkout saga compensation failed",
+				zap.String("order_id", saga.OrderID),
+				zap.String("step", step.name),
+				zap.Int("attempts", saga.Attempts),
+				zap.Error(err),
+			)
+
+			if saveErr := o.sagaRepo.SaveSaga(ctx, saga); saveErr != nil {
+				logger.Error("service: failed to persist saga compensation failure", zap.String("order_id", saga.OrderID), zap.Error(saveErr))
+			}
+			return false
+		}
+	}
+
+	saga.Status = domain.SagaCompensated
+	if err := o.sagaRepo.SaveSaga(ctx, saga); err != nil {
+		logger.Error("service: failed to mark checkout saga compensated", zap.String("order_id", saga.OrderID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func (o *checkoutOrchestrator) validatePrices(ctx context.Context, run *checkoutRun) error {
+	state := &run.saga.State
+
+	var productIDs []string
+	for _, item := range state.CartItems {
+		productIDs = append(productIDs, item.ProductVariantID)
+	}
+
+	//gRPC call to get real product info if any of them are updated
+	catalogResp, err := o.catalogClient.CheckPrices(ctx, &pb.CheckPricesRequest{
+		ProductIds: productIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("service: failed to communicate with catalog: %w", err)
+	}
+
+	verifiedProducts := make(map[string]*pb.ProductCheck)
+	for _, p := range catalogResp.Products {
+		verifiedProducts[p.ProductId] = p
+	}
+
+	currency := state.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	pricedAt := time.Now()
+	commissions, err := o.commissions.Schedule(ctx, pricedAt)
+	if err != nil {
+		return err
+	}
+
+	totalAmount := money.Zero(currency)
+	taxAmount := money.Zero(currency)
+	var baseTotalAmount money.Money
+	var rate fx.Rate
+	var orderItems []domain.OrderItem
+
+	for _, item := range state.CartItems {
+		vp, exists := verifiedProducts[item.ProductVariantID]
+
+		if !exists || !vp.IsAvailable {
+			return fmt.Errorf("service: product %s is currently unavailable", item.ProductVariantID)
+		}
+		basePrice := money.FromProto(vp.Price)
+
+		// One rate is snapshotted per order, so every line must share the
+		// catalog currency of the first.
+		if rate.Value == nil {
+			_, rate, err = fx.Convert(ctx, o.rates, money.Zero(basePrice.Currency), currency)
+			if err != nil {
+				return fmt.Errorf("service: cannot price order in %s: %w", currency, err)
+			}
+			baseTotalAmount = money.Zero(basePrice.Currency)
+		}
+
+		baseTotalAmount, err = baseTotalAmount.Add(basePrice.Mul(int64(item.Quantity)))
+		if err != nil {
+			return fmt.Errorf("service: product %s is priced in %s: %w", item.ProductVariantID, basePrice.Currency, err)
+		}
+
+		price, err := rate.Convert(basePrice)
+		if err != nil {
+			return fmt.Errorf("service: failed to convert price of %s: %w", item.ProductVariantID, err)
+		}
+
+		totalAmount, err = totalAmount.Add(price.Mul(int64(item.Quantity)))
+		if err != nil {
+			return fmt.Errorf("service: failed to total order: %w", err)
+		}
+
+		breakdown, err := o.taxes.Calculate(tax.Line{
+			CategoryPath: vp.CategoryPath,
+			SellerGSTIN:  vp.SellerGstin,
+			Quantity:     item.Quantity,
+			UnitPrice:    price,
+		}, state.ShippingAddress.State)
+		if err != nil {
+			return fmt.Errorf("service: failed to calculate tax for %s: %w", item.ProductVariantID, err)
+		}
+
+		taxAmount, err = taxAmount.Add(breakdown.Tax())
+		if err != nil {
+			return fmt.Errorf("service: failed to total order tax: %w", err)
+		}
+
+		orderItem := domain.OrderItem{
+			ProductID:     item.ProductVariantID,
+			ProductName:   vp.Name,
+			HSNCode:       vp.HsnCode,
+			Quantity:      item.Quantity,
+			Price:         price,
+			SellerID:      vp.SellerId,
+			SellerGSTIN:   vp.SellerGstin,
+			CategoryPath:  vp.CategoryPath,
+			SupplyType:    breakdown.SupplyType,
+			TaxRateBps:    breakdown.RateBps,
+			TaxableValue:  breakdown.TaxableValue,
+			CGST:          breakdown.CGST,
+			SGST:          breakdown.SGST,
+			IGST:          breakdown.IGST,
+			CommissionFee: money.Zero(currency),
+			Commission:    money.Zero(currency),
+		}
+
+		// The commission snapshotted here is the only one settlement uses, so
+		// a line no rule covers cannot be sold.
+		rule, ok := commissions.Find(vp.SellerId, vp.CategoryPath, pricedAt)
+		if !ok {
+			return fmt.Errorf("service: %w: %s", domain.ErrNoCommissionRule, item.ProductVariantID)
+		}
+
+		if rule.FixedFee.IsPositive() {
+			orderItem.CommissionFee, _, err = fx.Convert(ctx, o.rates, rule.FixedFee, currency)
+			if err != nil {
+				return fmt.Errorf("service: cannot convert commission fee of rule %s to %s: %w", rule.ID, currency, err)
+			}
+		}
+
+		orderItem.Commission, err = rule.Charge(price.Mul(int64(item.Quantity)), item.Quantity, orderItem.CommissionFee)
+		if err != nil {
+			return fmt.Errorf("service: failed to calculate commission for %s: %w", item.ProductVariantID, err)
+		}
+		orderItem.CommissionRuleID = rule.ID
+		orderItem.CommissionRateBps = rule.RateBps
+
+		orderItems = append(orderItems, orderItem)
+	}
+
+	state.Currency = currency
+	state.OrderItems = orderItems
+	state.TotalAmount = totalAmount
+	state.BaseTotalAmount = baseTotalAmount
+	state.TaxAmount = taxAmount
+	state.ExchangeRate = rate.String()
+	return nil
+}
+
+func (o *checkoutOrchestrator) reserveStock(ctx context.Context, run *checkoutRun) error {
+	var stockItems []*pb.StockItem
+	for _, item := range run.saga.State.OrderItems {
+		stockItems = append(stockItems, &pb.StockItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	_, err := o.catalogClient.ReserveStock(ctx, &pb.ReserveStockRequest{
+		OrderId:    run.saga.OrderID,
+		Items:      stockItems,
+		TtlSeconds: int32(stockReservationTTL.Seconds()),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && (st.Code() == codes.FailedPrecondition || st.Code() == codes.NotFound) {
+			return fmt.Errorf("service: one or more products are currently unavailable: %s", st.Message())
+		}
+		return fmt.Errorf("service: failed to reserve stock: %w", err)
+	}
+	return nil
+}
+
+func (o *checkoutOrchestrator) releaseStock(ctx context.Context, run *checkoutRun) error {
+	_, err := o.catalogClient.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{
+		OrderId: run.saga.OrderID,
+		Reason:  "checkout aborted",
+	})
+	if err != nil {
+		return fmt.Errorf("service: failed to release stock reservation: %w", err)
+	}
+	return nil
+}
+
+func (o *checkoutOrchestrator) createOrder(ctx context.Context, run *checkoutRun) error {
+	saga := run.saga
+	state := saga.State
+
+	items, fulfillments := splitFulfillments(saga.OrderID, state.OrderItems, state.TotalAmount.Currency)
+
+	order := &domain.Order{
+		PublicID:        saga.OrderID,
+		UserID:          saga.UserID,
+		TotalAmount:     state.TotalAmount,
+		BaseTotalAmount: state.BaseTotalAmount,
+		ExchangeRate:    state.ExchangeRate,
+		TaxAmount:       state.TaxAmount,
+		Status:          domain.OrderPending,
+		ShippingName:    state.ShippingName,
+		ShippingPhone:   state.ShippingPhone,
+		ShippingAddress: state.ShippingAddress.AddressLine,
+		ShippingCity:    state.ShippingAddress.City,
+		ShippingState:   state.ShippingAddress.State,
+		ShippingZip:     state.ShippingAddress.ZipCode,
+		CustomerEmail:   state.CustomerEmail,
+		Items:           items,
+		Fulfillments:    fulfillments,
+	}
+
+	if err := o.orderRepo.CreateOrder(ctx, order); err != nil {
+		return fmt.Errorf("service: failed to save order: %w", err)
+	}
+
+	run.order = order
+	return nil
+}
+
+// splitFulfillments gives each seller on the order a fulfillment, numbered
+// after the order in the order the seller first appears (ORD-XXXXXXXX-1), and
+// tags that seller's lines with it.
+func splitFulfillments(orderID string, items []domain.OrderItem, currency string) ([]domain.OrderItem, []domain.Fulfillment) {
+	tagged := make([]domain.OrderItem, len(items))
+	copy(tagged, items)
+
+	var fulfillments []domain.Fulfillment
+	bySeller := make(map[string]int)
+
+	for i := range tagged {
+		item := &tagged[i]
+		if item.SellerID == "" {
+			continue
+		}
+
+		idx, ok := bySeller[item.SellerID]
+		if !ok {
+			idx = len(fulfillments)
+			bySeller[item.SellerID] = idx
+			fulfillments = append(fulfillments, domain.Fulfillment{
+				PublicID:   fmt.Sprintf("%s-%d", orderID, idx+1),
+				OrderID:    orderID,
+				SellerID:   item.SellerID,
+				Status:     domain.FulfillmentAwaitingPayment,
+				Subtotal:   money.Zero(currency),
+				Commission: money.Zero(currency),
+			})
+		}
+
+		item.FulfillmentID = fulfillments[idx].PublicID
+		fulfillments[idx].Subtotal.Amount += item.Price.Mul(int64(item.Quantity)).Amount
+		fulfillments[idx].Commission.Amount += item.Commission.Amount
+	}
+
+	return tagged, fulfillments
+}
+
+func (o *checkoutOrchestrator) cancelOrder(ctx context.Context, run *checkoutRun) error {
+	_, _, err := o.orderRepo.TransitionStatus(ctx, run.saga.OrderID, domain.OrderCancelled, domain.ActorSystem, "checkout aborted")
+	// Nothing to undo if the order row was never written.
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+func (o *checkoutOrchestrator) createPaymentSession(ctx context.Context, run *checkoutRun) error {
+	saga := run.saga
+
+	paymentURL, err := o.paymentClient.InitiatePayment(ctx, saga.OrderID, saga.UserID, saga.State.TotalAmount)
+	if err != nil {
+		return fmt.Errorf("service: failed to initiate payment gateway: %w", err)
+	}
+
+	saga.State.PaymentURL = paymentURL
+	return nil
+}
+
+func (o *checkoutOrchestrator) cancelPaymentSession(ctx context.Context, run *checkoutRun) error {
+	err := o.paymentClient.CancelPayment(ctx, run.saga.OrderID)
+	if err != nil {
+		return fmt.Errorf("service: failed to cancel payment session: %w", err)
+	}
+	return nil
+}
+
+func noCompensation(context.Context, *checkoutRun) error {
+	return nil
+}
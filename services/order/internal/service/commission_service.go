@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"ecommerce/services/order/internal/commission"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/repository"
+
+	"github.com/sixafter/nanoid"
+	"go.uber.org/zap"
+)
+
+// CommissionRuleInput is everything an admin sets on a commission rule.
+type CommissionRuleInput struct {
+	CategoryPath  string
+	SellerID      string
+	RateBps       int64
+	FixedFee      money.Money
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+}
+
+type CommissionService interface {
+	CreateRule(ctx context.Context, input CommissionRuleInput) (*domain.CommissionRule, error)
+	UpdateRule(ctx context.Context, ruleID string, input CommissionRuleInput) (*domain.CommissionRule, error)
+	GetRule(ctx context.Context, ruleID string) (*domain.CommissionRule, error)
+	ListRules(ctx context.Context, sellerID string, categoryPath string, page int, limit int) ([]domain.CommissionRule, error)
+	DeleteRule(ctx context.Context, ruleID string) error
+	Schedule(ctx context.Context, at time.Time) (*commission.Schedule, error)
+	SeedDefaultRule(ctx context.Context, rateBps int64) error
+}
+
+type commissionService struct {
+	commissionRepo repository.CommissionRepository
+	nanoGen        nanoid.Interface
+}
+
+func NewCommissionService(commissionRepo repository.CommissionRepository) (CommissionService, error) {
+	gen, err := nanoid.NewGenerator(nanoid.WithAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to initialize nanoid generator: %w", err)
+	}
+
+	return &commissionService{commissionRepo: commissionRepo, nanoGen: gen}, nil
+}
+
+func (s *commissionService) CreateRule(ctx context.Context, input CommissionRuleInput) (*domain.CommissionRule, error) {
+	id, err := s.nanoGen.NewWithLength(8)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to generate commission rule ID: %w", err)
+	}
+
+	rule, err := buildCommissionRule(fmt.Sprintf("COM-%s", id), input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.commissionRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("service: failed to create commission rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *commissionService) UpdateRule(ctx context.Context, ruleID string, input CommissionRuleInput) (*domain.CommissionRule, error) {
+	rule, err := buildCommissionRule(ruleID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.commissionRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("service: failed to update commission rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *commissionService) GetRule(ctx context.Context, ruleID string) (*domain.CommissionRule, error) {
+	rule, err := s.commissionRepo.GetRule(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get commission rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *commissionService) ListRules(ctx context.Context, sellerID string, categoryPath string, page int, limit int) ([]domain.CommissionRule, error) {
+	rules, err := s.commissionRepo.ListRules(ctx, sellerID, commission.NormalizePath(categoryPath), limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list commission rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *commissionService) DeleteRule(ctx context.Context, ruleID string) error {
+	if err := s.commissionRepo.DeleteRule(ctx, ruleID); err != nil {
+		return fmt.Errorf("service: failed to delete commission rule: %w", err)
+	}
+	return nil
+}
+
+// SeedDefaultRule makes sure a marketplace-wide rule covers every line, since
+// checkout refuses lines no rule covers. It charges rateBps from now on and is
+// only created if there has never been a marketplace-wide rule; after that,
+// admins manage it like any other. Lines sold before commissions were
+// snapshotted are then charged at that rule's rate, so every fulfillment
+// reaches settlement with a commission.
+func (s *commissionService) SeedDefaultRule(ctx context.Context, rateBps int64) error {
+	id, err := s.nanoGen.NewWithLength(8)
+	if err != nil {
+		return fmt.Errorf("service: failed to generate commission rule ID: %w", err)
+	}
+
+	rule, err := buildCommissionRule(fmt.Sprintf("COM-%s", id), CommissionRuleInput{
+		RateBps:       rateBps,
+		FixedFee:      money.Zero(money.DefaultCurrency),
+		EffectiveFrom: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	created, err := s.commissionRepo.SeedRule(ctx, rule)
+	if err != nil {
+		return fmt.Errorf("service: failed to seed default commission rule: %w", err)
+	}
+	if created {
+		logger.Info("service: seeded marketplace-wide commission rule", zap.String("rule_id", rule.PublicID), zap.Int64("rate_bps", rateBps))
+	}
+
+	charged, err := s.commissionRepo.BackfillCommissions(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("service: failed to charge commission on earlier order lines: %w", err)
+	}
+	if charged > 0 {
+		logger.Info("service: charged commission on order lines from before commission rules", zap.Int64("line_count", charged))
+	}
+	return nil
+}
+
+// Schedule loads the rules in force at a moment, for pricing a checkout.
+func (s *commissionService) Schedule(ctx context.Context, at time.Time) (*commission.Schedule, error) {
+	rules, err := s.commissionRepo.ListRulesActiveAt(ctx, at)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to load commission rules: %w", err)
+	}
+
+	converted := make([]commission.Rule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, toCommissionRule(rule))
+	}
+	return commission.NewSchedule(converted), nil
+}
+
+func buildCommissionRule(publicID string, input CommissionRuleInput) (*domain.CommissionRule, error) {
+	rule := &domain.CommissionRule{
+		PublicID:      publicID,
+		CategoryPath:  commission.NormalizePath(input.CategoryPath),
+		SellerID:      input.SellerID,
+		RateBps:       input.RateBps,
+		FixedFee:      input.FixedFee,
+		EffectiveFrom: input.EffectiveFrom,
+		EffectiveTo:   input.EffectiveTo,
+	}
+
+	if err := toCommissionRule(*rule).Validate(); err != nil {
+		return nil, fmt.Errorf("service: %w: %v", domain.ErrInvalidCommissionRule, err)
+	}
+	return rule, nil
+}
+
+func toCommissionRule(rule domain.CommissionRule) commission.Rule {
+	return commission.Rule{
+		ID:            rule.PublicID,
+		CategoryPath:  rule.CategoryPath,
+		SellerID:      rule.SellerID,
+		RateBps:       rule.RateBps,
+		FixedFee:      rule.FixedFee,
+		EffectiveFrom: rule.EffectiveFrom,
+		EffectiveTo:   rule.EffectiveTo,
+	}
+}
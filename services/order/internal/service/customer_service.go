@@ -2,15 +2,13 @@ package service
 
 import (
 	"context"
-	"ecommerce/pkg/broker"
-	"ecommerce/pkg/logger"
-	"encoding/json"
+	"ecommerce/pkg/events"
 	"fmt"
 
 	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/repository"
 
-	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type CustomerService interface {
@@ -21,11 +19,11 @@ type CustomerService interface {
 
 type customerService struct {
 	customerRepo repository.CustomerRepository
-	broker       *broker.RabbitMQClient
+	db           *gorm.DB
 }
 
-func NewCustomerService(customerRepo repository.CustomerRepository, client *broker.RabbitMQClient) CustomerService {
-	return &customerService{customerRepo: customerRepo, broker: client}
+func NewCustomerService(customerRepo repository.CustomerRepository, db *gorm.DB) CustomerService {
+	return &customerService{customerRepo: customerRepo, db: db}
 }
 
 func (s *customerService) CreateProfile(ctx context.Context, userID, name, phone string) (*domain.CustomerProfile, error) {
@@ -35,33 +33,19 @@ func (s *customerService) CreateProfile(ctx context.Context, userID, name, phone
 		Phone:  phone,
 	}
 
-	err := s.customerRepo.CreateProfile(ctx, profile)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if innerErr := s.customerRepo.WithTx(tx).CreateProfile(ctx, profile); innerErr != nil {
+			return innerErr
+		}
+		return enqueueEvent(ctx, tx, events.UserEventsExchange, events.CustomerOnboardedKey, events.UserOnboarded{
+			UserID: userID,
+			Status: "onboarded",
+		})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to create profile: %w", err)
 	}
 
-	type CustomerOnboardedEvent struct {
-		UserID string `json:"user_id"`
-		Status string `json:"status"`
-	}
-
-	event := CustomerOnboardedEvent{
-		UserID: userID,
-		Status: "onboarded",
-	}
-
-	payload, err := json.Marshal(event)
-	if err != nil {
-		logger.Error("service: failed to marshal onboard event:", zap.Error(err))
-	} else {
-		err = s.broker.Publish(ctx, "user_events", "customer.onboarded", payload)
-		if err != nil {
-			logger.Error("service: failed to publish onboard event", zap.Error(err))
-		} else {
-			logger.Info("service: published onboard event")
-		}
-	}
-
 	return profile, nil
 }
 
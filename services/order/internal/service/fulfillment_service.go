@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/repository"
+	"ecommerce/services/order/internal/returns"
+
+	pb "ecommerce/pkg/protobufs/catalog"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type FulfillmentService interface {
+	ResolveSeller(ctx context.Context, userID string) (string, error)
+	ListSellerOrders(ctx context.Context, sellerID string, status domain.FulfillmentStatus, page int, limit int) ([]domain.Fulfillment, error)
+	GetSellerOrder(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error)
+	Accept(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error)
+	Reject(ctx context.Context, sellerID string, fulfillmentID string, reason string) (*domain.Fulfillment, error)
+	Pack(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error)
+	MarkReadyToShip(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error)
+	ApplyShipmentStatus(ctx context.Context, fulfillmentID string, to domain.FulfillmentStatus, note string) (*domain.Fulfillment, error)
+	WithTx(tx *gorm.DB) FulfillmentService
+}
+
+type fulfillmentService struct {
+	fulfillmentRepo repository.FulfillmentRepository
+	catalogClient   pb.CatalogServiceClient
+	policy          *returns.Policy
+	db              *gorm.DB
+}
+
+func NewFulfillmentService(fulfillmentRepo repository.FulfillmentRepository, catalogClient pb.CatalogServiceClient, policy *returns.Policy, db *gorm.DB) FulfillmentService {
+	return &fulfillmentService{
+		fulfillmentRepo: fulfillmentRepo,
+		catalogClient:   catalogClient,
+		policy:          policy,
+		db:              db,
+	}
+}
+
+// WithTx returns a copy of the service whose fulfillment writes join tx.
+func (s *fulfillmentService) WithTx(tx *gorm.DB) FulfillmentService {
+	clone := *s
+	clone.fulfillmentRepo = s.fulfillmentRepo.WithTx(tx)
+	clone.db = tx
+	return &clone
+}
+
+// ResolveSeller maps the user behind a seller token to the catalog's seller ID,
+// which is what order lines are tagged with.
+func (s *fulfillmentService) ResolveSeller(ctx context.Context, userID string) (string, error) {
+	seller, err := s.catalogClient.GetSellerByUser(ctx, &pb.GetSellerByUserRequest{UserId: userID})
+	if status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("service: failed to resolve seller: %w", err)
+	}
+	return seller.SellerId, nil
+}
+
+func (s *fulfillmentService) ListSellerOrders(ctx context.Context, sellerID string, status domain.FulfillmentStatus, page int, limit int) ([]domain.Fulfillment, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("service: unknown fulfillment status %q", status)
+	}
+
+	fulfillments, err := s.fulfillmentRepo.ListSellerFulfillments(ctx, sellerID, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list seller orders: %w", err)
+	}
+	return fulfillments, nil
+}
+
+func (s *fulfillmentService) GetSellerOrder(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error) {
+	fulfillment, err := s.fulfillmentRepo.GetSellerFulfillment(ctx, fulfillmentID, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get seller order: %w", err)
+	}
+	return fulfillment, nil
+}
+
+func (s *fulfillmentService) Accept(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error) {
+	return s.transition(ctx, sellerID, fulfillmentID, domain.FulfillmentAccepted, domain.ActorSeller, "accepted by seller")
+}
+
+// Reject drops the seller's lines from the order and puts their units back on
+// sale. The payment service refunds the fulfillment's subtotal when it sees the
+// fulfillment.rejected event. If no seller is left the order is cancelled, and
+// the order.cancelled event has the payment service refund whatever remains.
+func (s *fulfillmentService) Reject(ctx context.Context, sellerID string, fulfillmentID string, reason string) (*domain.Fulfillment, error) {
+	if reason == "" {
+		reason = "rejected by seller"
+	}
+
+	fulfillment, err := s.transition(ctx, sellerID, fulfillmentID, domain.FulfillmentRejected, domain.ActorSeller, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]string, 0, len(fulfillment.Items))
+	for _, item := range fulfillment.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	_, err = s.catalogClient.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{
+		OrderId:    fulfillment.OrderID,
+		Reason:     "rejected by seller",
+		ProductIds: productIDs,
+	})
+	if err != nil {
+		logger.Error("service: failed to release stock for rejected fulfillment", zap.String("fulfillment_id", fulfillmentID), zap.Error(err))
+	}
+	return fulfillment, nil
+}
+
+func (s *fulfillmentService) Pack(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error) {
+	return s.transition(ctx, sellerID, fulfillmentID, domain.FulfillmentPacked, domain.ActorSeller, "packed by seller")
+}
+
+func (s *fulfillmentService) MarkReadyToShip(ctx context.Context, sellerID string, fulfillmentID string) (*domain.Fulfillment, error) {
+	return s.transition(ctx, sellerID, fulfillmentID, domain.FulfillmentReadyToShip, domain.ActorSeller, "ready to ship")
+}
+
+// ApplyShipmentStatus moves a fulfillment along as the logistics service
+// reports progress on its shipment.
+func (s *fulfillmentService) ApplyShipmentStatus(ctx context.Context, fulfillmentID string, to domain.FulfillmentStatus, note string) (*domain.Fulfillment, error) {
+	return s.transition(ctx, "", fulfillmentID, to, domain.ActorLogistics, note)
+}
+
+func (s *fulfillmentService) transition(ctx context.Context, sellerID string, fulfillmentID string, to domain.FulfillmentStatus, actor string, reason string) (*domain.Fulfillment, error) {
+	var fulfillment *domain.Fulfillment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order *domain.Order
+		var innerErr error
+		fulfillment, order, innerErr = s.fulfillmentRepo.WithTx(tx).TransitionFulfillment(ctx, fulfillmentID, sellerID, to, actor, reason)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		innerErr = enqueueEvent(ctx, tx, events.OrderEventsExchange, events.FulfillmentEventKey(string(to)), s.fulfillmentEvent(fulfillment, order, reason))
+		if innerErr != nil {
+			return innerErr
+		}
+
+		// Only a rejection can cancel the order through the roll-up, and
+		// sellers can only reject before accepting, so the order was still paid.
+		if to != domain.FulfillmentRejected || order.Status != domain.OrderCancelled {
+			return nil
+		}
+		return enqueueEvent(ctx, tx, events.OrderEventsExchange, events.OrderCancelledKey, events.OrderCancelled{
+			OrderID:        order.PublicID,
+			UserID:         order.UserID,
+			PreviousStatus: string(domain.OrderPaid),
+			Reason:         "rejected by all sellers",
+			CancelledAt:    time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update seller order: %w", err)
+	}
+	return fulfillment, nil
+}
+
+func (s *fulfillmentService) fulfillmentEvent(fulfillment *domain.Fulfillment, order *domain.Order, reason string) events.FulfillmentStatusChanged {
+	commission := fulfillment.Commission
+	event := events.FulfillmentStatusChanged{
+		FulfillmentID: fulfillment.PublicID,
+		OrderID:       fulfillment.OrderID,
+		SellerID:      fulfillment.SellerID,
+		UserID:        order.UserID,
+		CustomerEmail: order.CustomerEmail,
+		Status:        string(fulfillment.Status),
+		OrderStatus:   string(order.Status),
+		Reason:        reason,
+		Amount:        fulfillment.Subtotal,
+		Commission:    &commission,
+		OccurredAt:    time.Now(),
+
+		ShippingName:    order.ShippingName,
+		ShippingPhone:   order.ShippingPhone,
+		ShippingAddress: order.ShippingAddress,
+		ShippingCity:    order.ShippingCity,
+		ShippingState:   order.ShippingState,
+		ShippingZip:     order.ShippingZip,
+	}
+	categoryPaths := make([]string, 0, len(fulfillment.Items))
+	for _, item := range fulfillment.Items {
+		event.Items = append(event.Items, events.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+		categoryPaths = append(categoryPaths, item.CategoryPath)
+	}
+	if fulfillment.Status == domain.FulfillmentDelivered && fulfillment.DeliveredAt != nil {
+		closesAt := s.policy.WindowClosesAt(categoryPaths, *fulfillment.DeliveredAt)
+		event.ReturnWindowClosesAt = &closesAt
+	}
+	return event
+}
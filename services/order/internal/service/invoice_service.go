@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"fmt"
+	"sort"
+	"time"
+
+	"ecommerce/services/order/internal/client"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/invoice"
+	"ecommerce/services/order/internal/repository"
+	"ecommerce/services/order/internal/tax"
+
+	pb "ecommerce/pkg/protobufs/catalog"
+
+	"go.uber.org/zap"
+)
+
+// invoiceRetryWindow bounds how far back RetryPending looks for orders whose
+// invoices were not stored or mailed.
+const invoiceRetryWindow = 7 * 24 * time.Hour
+
+type InvoiceService interface {
+	IssueInvoices(ctx context.Context, orderID string) error
+	RetryPending(ctx context.Context) (int, error)
+	GetOrderInvoices(ctx context.Context, publicID string, userID string) ([]domain.Invoice, error)
+}
+
+type invoiceService struct {
+	invoiceRepo   repository.InvoiceRepository
+	orderRepo     repository.OrderRepository
+	catalogClient pb.CatalogServiceClient
+	mediaClient   client.MediaClient
+	emailClient   client.EmailClient
+}
+
+func NewInvoiceService(
+	invoiceRepo repository.InvoiceRepository,
+	orderRepo repository.OrderRepository,
+	catalogClient pb.CatalogServiceClient,
+	mediaClient client.MediaClient,
+	emailClient client.EmailClient,
+) InvoiceService {
+	return &invoiceService{
+		invoiceRepo:   invoiceRepo,
+		orderRepo:     orderRepo,
+		catalogClient: catalogClient,
+		mediaClient:   mediaClient,
+		emailClient:   emailClient,
+	}
+}
+
+// IssueInvoices gives every seller on a paid order a numbered invoice, stores
+// its PDF and mails it to the buyer. Each step is skipped once it has been
+// done, so the call can be repeated until it succeeds.
+func (s *invoiceService) IssueInvoices(ctx context.Context, orderID string) error {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("service: failed to fetch order for invoicing: %w", err)
+	}
+	if !order.Status.IsInvoiced() {
+		return nil
+	}
+
+	itemsBySeller := make(map[string][]domain.OrderItem)
+	var sellerIDs []string
+	for _, item := range order.Items {
+		if item.SellerID == "" {
+			continue
+		}
+		if _, ok := itemsBySeller[item.SellerID]; !ok {
+			sellerIDs = append(sellerIDs, item.SellerID)
+		}
+		itemsBySeller[item.SellerID] = append(itemsBySeller[item.SellerID], item)
+	}
+	if len(sellerIDs) == 0 {
+		return nil
+	}
+	sort.Strings(sellerIDs)
+
+	resp, err := s.catalogClient.GetSellers(ctx, &pb.GetSellersRequest{SellerIds: sellerIDs})
+	if err != nil {
+		return fmt.Errorf("service: failed to fetch sellers for invoicing: %w", err)
+	}
+	sellers := make(map[string]*pb.Seller, len(resp.Sellers))
+	for _, seller := range resp.Sellers {
+		sellers[seller.SellerId] = seller
+	}
+
+	var failed error
+	for _, sellerID := range sellerIDs {
+		seller, ok := sellers[sellerID]
+		if !ok {
+			failed = fmt.Errorf("service: seller %s not found for invoicing", sellerID)
+			continue
+		}
+
+		err = s.issueSellerInvoice(ctx, order, seller, itemsBySeller[sellerID])
+		if err != nil {
+			logger.Error("service: failed to issue invoice", zap.String("order_id", order.PublicID), zap.String("seller_id", sellerID), zap.Error(err))
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (s *invoiceService) issueSellerInvoice(ctx context.Context, order *domain.Order, seller *pb.Seller, items []domain.OrderItem) error {
+	currency := order.TotalAmount.Currency
+	record := &domain.Invoice{
+		OrderID:      order.PublicID,
+		SellerID:     seller.SellerId,
+		SellerName:   seller.Name,
+		SellerGSTIN:  items[0].SellerGSTIN,
+		BuyerEmail:   order.CustomerEmail,
+		TaxableValue: money.Zero(currency),
+		CGST:         money.Zero(currency),
+		SGST:         money.Zero(currency),
+		IGST:         money.Zero(currency),
+		Total:        money.Zero(currency),
+		IssuedAt:     time.Now(),
+	}
+
+	lines := make([]invoice.Line, 0, len(items))
+	for _, item := range items {
+		description := item.ProductName
+		if description == "" {
+			description = item.ProductID
+		}
+
+		line := invoice.Line{
+			Description:  description,
+			HSNCode:      item.HSNCode,
+			Quantity:     item.Quantity,
+			UnitPrice:    item.Price,
+			RateBps:      item.TaxRateBps,
+			TaxableValue: item.TaxableValue,
+			CGST:         item.CGST,
+			SGST:         item.SGST,
+			IGST:         item.IGST,
+		}
+		lines = append(lines, line)
+
+		record.TaxableValue.Amount += line.TaxableValue.Amount
+		record.CGST.Amount += line.CGST.Amount
+		record.SGST.Amount += line.SGST.Amount
+		record.IGST.Amount += line.IGST.Amount
+		record.Total.Amount += line.Total().Amount
+	}
+
+	issued, err := s.invoiceRepo.IssueInvoice(ctx, record)
+	if err != nil {
+		return fmt.Errorf("service: failed to issue invoice: %w", err)
+	}
+
+	if issued.PDFURL != "" && (issued.EmailedAt != nil || issued.BuyerEmail == "") {
+		return nil
+	}
+
+	pdf, err := invoice.Render(invoice.Invoice{
+		Number:        issued.Number,
+		IssuedAt:      issued.IssuedAt,
+		OrderID:       order.PublicID,
+		PlaceOfSupply: placeOfSupply(order.ShippingState),
+		Currency:      currency,
+		Seller: invoice.Party{
+			Name:    seller.Name,
+			GSTIN:   issued.SellerGSTIN,
+			Address: seller.RegisteredAddress,
+			Phone:   seller.SupportPhone,
+			Email:   seller.SupportEmail,
+		},
+		Buyer: invoice.Party{
+			Name:    order.ShippingName,
+			Address: fmt.Sprintf("%s, %s %s", order.ShippingAddress, order.ShippingCity, order.ShippingZip),
+			State:   order.ShippingState,
+			Phone:   order.ShippingPhone,
+		},
+		Lines: lines,
+	})
+	if err != nil {
+		return fmt.Errorf("service: failed to render invoice %s: %w", issued.Number, err)
+	}
+
+	filename := issued.Number + ".pdf"
+
+	if issued.PDFURL == "" {
+		url, err := s.mediaClient.UploadDocument(ctx, "invoices/"+seller.SellerId, filename, pdf)
+		if err != nil {
+			return fmt.Errorf("service: failed to store invoice %s: %w", issued.Number, err)
+		}
+		if err = s.invoiceRepo.SetPDFURL(ctx, issued.ID, url); err != nil {
+			return err
+		}
+	}
+
+	if issued.EmailedAt == nil && issued.BuyerEmail != "" {
+		err = s.emailClient.SendInvoiceEmail(ctx, client.InvoiceEmail{
+			To:            issued.BuyerEmail,
+			BuyerName:     order.ShippingName,
+			OrderID:       order.PublicID,
+			InvoiceNumber: issued.Number,
+			SellerName:    seller.Name,
+			Total:         issued.Total.String(),
+			Filename:      filename,
+			Attachment:    pdf,
+		})
+		if err != nil {
+			return fmt.Errorf("service: failed to email invoice %s: %w", issued.Number, err)
+		}
+		if err = s.invoiceRepo.MarkEmailed(ctx, issued.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RetryPending finishes invoicing for recently paid orders that a failed
+// upload, email or catalog call left incomplete.
+func (s *invoiceService) RetryPending(ctx context.Context) (int, error) {
+	orderIDs, err := s.invoiceRepo.ListOrdersAwaitingInvoices(ctx, time.Now().Add(-invoiceRetryWindow), 50)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to list orders awaiting invoices: %w", err)
+	}
+
+	completed := 0
+	for _, orderID := range orderIDs {
+		if err = s.IssueInvoices(ctx, orderID); err != nil {
+			logger.Error("service: failed to complete order invoices", zap.String("order_id", orderID), zap.Error(err))
+			continue
+		}
+		completed++
+	}
+	return completed, nil
+}
+
+func (s *invoiceService) GetOrderInvoices(ctx context.Context, publicID string, userID string) ([]domain.Invoice, error) {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, publicID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch order: %w", err)
+	}
+	if order == nil || order.UserID != userID {
+		return nil, fmt.Errorf("service: order not found")
+	}
+
+	invoices, err := s.invoiceRepo.GetOrderInvoices(ctx, publicID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch order invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+func placeOfSupply(state string) string {
+	code, ok := tax.StateCode(state)
+	if !ok {
+		return state
+	}
+	return fmt.Sprintf("%s (%s)", tax.StateName(code), code)
+}
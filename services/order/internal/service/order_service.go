@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
-	"ecommerce/services/order/internal/client"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/outbox"
 	"fmt"
-	"math"
+	"time"
 
 	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/repository"
@@ -12,13 +14,28 @@ import (
 	pb "ecommerce/pkg/protobufs/catalog"
 
 	"github.com/sixafter/nanoid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
+// stockCommitRetryWindow is how far back the stock worker looks for paid orders
+// whose stock was never committed.
+const stockCommitRetryWindow = 7 * 24 * time.Hour
+
 type OrderService interface {
-	Checkout(ctx context.Context, userID string, name, phone string, address domain.Address) (*domain.Order, string, error)
+	Checkout(ctx context.Context, userID string, email string, name, phone string, address domain.Address) (*domain.Order, string, error)
 	GetOrder(ctx context.Context, publicID string, userID string) (*domain.Order, error)
 	GetUserOrders(ctx context.Context, userID string) ([]domain.Order, error)
-	UpdateOrderStatus(ctx context.Context, id string, status string) error
+	TransitionOrderStatus(ctx context.Context, orderID string, to domain.OrderStatus, actor string, reason string) error
+	GetOrderTimeline(ctx context.Context, publicID string, userID string) ([]domain.OrderStatusHistory, error)
+	AddOrderNote(ctx context.Context, orderID string, actor string, note string) error
+	CommitStockReservation(ctx context.Context, orderID string) error
+	RetryStockCommits(ctx context.Context) (int, error)
+	ReleaseStockReservation(ctx context.Context, orderID string, reason string) error
+	CancelOrder(ctx context.Context, publicID string, userID string, reason string) (*domain.Order, error)
+	WithTx(tx *gorm.DB) OrderService
 }
 
 type orderService struct {
@@ -27,14 +44,16 @@ type orderService struct {
 
 	nanoGen       nanoid.Interface
 	catalogClient pb.CatalogServiceClient
-	paymentClient client.PaymentService
+	checkout      CheckoutOrchestrator
+	db            *gorm.DB
 }
 
 func NewOrderService(
 	orderRepo repository.OrderRepository,
 	cartRepo repository.CartRepository,
 	catalogClient pb.CatalogServiceClient,
-	paymentClient client.PaymentService,
+	checkout CheckoutOrchestrator,
+	db *gorm.DB,
 ) (OrderService, error) {
 
 	gen, err := nanoid.NewGenerator(nanoid.WithAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
@@ -47,11 +66,20 @@ func NewOrderService(
 		cartRepo:      cartRepo,
 		nanoGen:       gen,
 		catalogClient: catalogClient,
-		paymentClient: paymentClient,
+		checkout:      checkout,
+		db:            db,
 	}, nil
 }
 
-func (s *orderService) Checkout(ctx context.Context, userID string, name, phone string, address domain.Address) (*domain.Order, string, error) {
+// WithTx returns a copy of the service whose order writes join tx.
+func (s *orderService) WithTx(tx *gorm.DB) OrderService {
+	clone := *s
+	clone.orderRepo = s.orderRepo.WithTx(tx)
+	clone.db = tx
+	return &clone
+}
+
+func (s *orderService) Checkout(ctx context.Context, userID string, email string, name, phone string, address domain.Address) (*domain.Order, string, error) {
 	cart, err := s.cartRepo.GetCart(ctx, userID)
 	if err != nil {
 		return nil, "", fmt.Errorf("service: failed to get cart for checkout: %w", err)
@@ -61,104 +89,226 @@ func (s *orderService) Checkout(ctx context.Context, userID string, name, phone
 		return nil, "", fmt.Errorf("service: cannot checkout with an empty cart")
 	}
 
-	var productIDs []string
-	for _, item := range cart.Items {
-		productIDs = append(productIDs, item.ProductVariantID)
+	id, err := s.nanoGen.NewWithLength(8)
+	if err != nil {
+		return nil, "", fmt.Errorf("service: failed to generate order number: %w", err)
 	}
 
-	//gRPC call to get real product info if any of them are updated
-	catalogResp, err := s.catalogClient.CheckPrices(ctx, &pb.CheckPricesRequest{
-		ProductIds: productIDs,
-	})
+	saga := &domain.CheckoutSaga{
+		OrderID: fmt.Sprintf("ORD-%s", id),
+		UserID:  userID,
+		State: domain.CheckoutSagaState{
+			ShippingName:    name,
+			ShippingPhone:   phone,
+			ShippingAddress: address,
+			CustomerEmail:   email,
+			CartItems:       cart.Items,
+			Currency:        cart.Currency,
+		},
+	}
+
+	return s.checkout.Run(ctx, saga)
+}
+
+func (s *orderService) GetOrder(ctx context.Context, publicID string, userID string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, publicID)
 	if err != nil {
-		return nil, "", fmt.Errorf("service: failed to communicate with catalog: %w", err)
+		return nil, fmt.Errorf("service: failed to fetch order: %w", err)
+	}
+	if order == nil || order.UserID != userID {
+		return nil, fmt.Errorf("service: order not found")
 	}
+	return order, nil
+}
 
-	verifiedProducts := make(map[string]*pb.ProductCheck)
-	for _, p := range catalogResp.Products {
-		verifiedProducts[p.ProductId] = p
+func (s *orderService) GetUserOrders(ctx context.Context, userID string) ([]domain.Order, error) {
+	orders, err := s.orderRepo.GetUserOrders(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch user orders: %w", err)
 	}
+	return orders, nil
+}
 
-	var totalAmount float64 = 0
-	var orderItems []domain.OrderItem
+func (s *orderService) TransitionOrderStatus(ctx context.Context, orderID string, to domain.OrderStatus, actor string, reason string) error {
+	if !to.IsValid() {
+		return fmt.Errorf("service: %w: unknown status %q", domain.ErrInvalidTransition, to)
+	}
 
-	for _, item := range cart.Items {
-		vp, exists := verifiedProducts[item.ProductVariantID]
+	_, _, err := s.orderRepo.TransitionStatus(ctx, orderID, to, actor, reason)
+	if err != nil {
+		return fmt.Errorf("service: failed to update order status: %w", err)
+	}
+	return nil
+}
 
-		if !exists || !vp.IsAvailable {
-			return nil, "", fmt.Errorf("service: product %s is currently unavailable", item.ProductVariantID)
-		}
-		totalAmount += vp.Price * float64(item.Quantity)
+func (s *orderService) AddOrderNote(ctx context.Context, orderID string, actor string, note string) error {
+	err := s.orderRepo.AddStatusNote(ctx, orderID, actor, note)
+	if err != nil {
+		return fmt.Errorf("service: failed to add order note: %w", err)
+	}
+	return nil
+}
 
-		orderItems = append(orderItems, domain.OrderItem{
-			ProductID: item.ProductVariantID,
-			Quantity:  item.Quantity,
-			Price:     vp.Price,
-		})
+func (s *orderService) GetOrderTimeline(ctx context.Context, publicID string, userID string) ([]domain.OrderStatusHistory, error) {
+	if _, err := s.GetOrder(ctx, publicID, userID); err != nil {
+		return nil, err
 	}
 
-	id, err := s.nanoGen.NewWithLength(8)
+	history, err := s.orderRepo.GetStatusHistory(ctx, publicID)
 	if err != nil {
-		return nil, "", fmt.Errorf("service: failed to generate order number: %w", err)
+		return nil, fmt.Errorf("service: failed to fetch order timeline: %w", err)
+	}
+	return history, nil
+}
+
+// CommitStockReservation turns the units held for a paid order into a sale.
+// If the hold ran out before the payment arrived, the units are reserved again;
+// if they have been sold in the meantime the order is cancelled, and the
+// payment service refunds it on the order.cancelled event. The order records
+// the commit, so one that failed is retried by RetryStockCommits.
+func (s *orderService) CommitStockReservation(ctx context.Context, orderID string) error {
+	_, err := s.catalogClient.CommitReservation(ctx, &pb.CommitReservationRequest{OrderId: orderID})
+	if status.Code(err) == codes.FailedPrecondition {
+		return s.reinstateStockReservation(ctx, orderID)
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to commit stock reservation: %w", err)
 	}
-	orderNumber := fmt.Sprintf("ORD-%s", id)
+	return s.orderRepo.MarkStockCommitted(ctx, orderID)
+}
 
-	order := &domain.Order{
-		PublicID:        orderNumber,
-		UserID:          userID,
-		TotalAmount:     totalAmount,
-		Status:          "pending",
-		ShippingName:    name,
-		ShippingPhone:   phone,
-		ShippingAddress: address.AddressLine,
-		ShippingCity:    address.City,
-		ShippingState:   address.State,
-		ShippingZip:     address.ZipCode,
-		Items:           orderItems,
+// RetryStockCommits commits the stock of recently paid orders whose commit
+// failed when the payment arrived.
+func (s *orderService) RetryStockCommits(ctx context.Context) (int, error) {
+	orderIDs, err := s.orderRepo.ListOrdersAwaitingStockCommit(ctx, time.Now().Add(-stockCommitRetryWindow), 50)
+	if err != nil {
+		return 0, fmt.Errorf("service: failed to list orders awaiting stock commit: %w", err)
 	}
 
-	if err = s.orderRepo.CreateOrder(ctx, order); err != nil {
-		return nil, "", fmt.Errorf("service: failed to save order: %w", err)
+	committed := 0
+	for _, orderID := range orderIDs {
+		if err = s.CommitStockReservation(ctx, orderID); err != nil {
+			logger.Error("service: failed to commit stock for paid order", zap.String("order_id", orderID), zap.Error(err))
+			continue
+		}
+		committed++
 	}
+	return committed, nil
+}
 
-	amountInPaise := int64(math.Round(totalAmount * 100))
+func (s *orderService) reinstateStockReservation(ctx context.Context, orderID string) error {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("service: failed to fetch order to reserve its stock again: %w", err)
+	}
+	if order == nil {
+		return fmt.Errorf("service: %w: %s", domain.ErrOrderNotFound, orderID)
+	}
 
-	paymentURL, err := s.paymentClient.InitiatePayment(ctx, orderNumber, userID, amountInPaise, "inr")
+	var stockItems []*pb.StockItem
+	for _, item := range order.Items {
+		stockItems = append(stockItems, &pb.StockItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	_, err = s.catalogClient.ReserveStock(ctx, &pb.ReserveStockRequest{
+		OrderId:    orderID,
+		Items:      stockItems,
+		TtlSeconds: int32(stockReservationTTL.Seconds()),
+	})
+	if code := status.Code(err); code == codes.FailedPrecondition || code == codes.NotFound {
+		logger.Error("service: stock for paid order ran out after its reservation expired, cancelling it",
+			zap.String("order_id", orderID),
+			zap.Error(err),
+		)
+		_, err = s.cancel(ctx, order, domain.ActorSystem, "items sold out before payment arrived")
+		return err
+	}
 	if err != nil {
-		return nil, "", fmt.Errorf("service: failed to initiate payment gateway: %w", err)
+		return fmt.Errorf("service: failed to reserve stock again: %w", err)
 	}
 
-	return order, paymentURL, nil
+	_, err = s.catalogClient.CommitReservation(ctx, &pb.CommitReservationRequest{OrderId: orderID})
+	if err != nil {
+		return fmt.Errorf("service: failed to commit stock reservation: %w", err)
+	}
+	return s.orderRepo.MarkStockCommitted(ctx, orderID)
 }
 
-func (s *orderService) GetOrder(ctx context.Context, publicID string, userID string) (*domain.Order, error) {
-	order, err := s.orderRepo.GetOrderByPublicID(ctx, publicID)
+func (s *orderService) ReleaseStockReservation(ctx context.Context, orderID string, reason string) error {
+	_, err := s.catalogClient.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{OrderId: orderID, Reason: reason})
 	if err != nil {
-		return nil, fmt.Errorf("service: failed to fetch order: %w", err)
+		return fmt.Errorf("service: failed to release stock reservation: %w", err)
 	}
-	if order == nil || order.UserID != userID {
-		return nil, fmt.Errorf("service: order not found")
+	return nil
+}
+
+// CancelOrder is only allowed before the order ships. Stock goes back to the
+// catalog right away; the refund (or closing an unpaid payment session) is left
+// to the payment service, which reacts to the order.cancelled event.
+func (s *orderService) CancelOrder(ctx context.Context, publicID string, userID string, reason string) (*domain.Order, error) {
+	order, err := s.GetOrder(ctx, publicID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := order.Status
+	if !previousStatus.CanTransitionTo(domain.OrderCancelled) {
+		return nil, fmt.Errorf("service: %w: order is %s and can no longer be cancelled", domain.ErrInvalidTransition, previousStatus)
 	}
+
+	if reason == "" {
+		reason = "cancelled by buyer"
+	}
+
+	updated, err := s.cancel(ctx, order, domain.ActorCustomer, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = updated.Status
 	return order, nil
 }
 
-func (s *orderService) GetUserOrders(ctx context.Context, userID string) ([]domain.Order, error) {
-	orders, err := s.orderRepo.GetUserOrders(ctx, userID)
+// cancel moves order to cancelled together with its order.cancelled event and
+// gives back any stock still held for it.
+func (s *orderService) cancel(ctx context.Context, order *domain.Order, actor string, reason string) (*domain.Order, error) {
+	var updated *domain.Order
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var changed bool
+		var innerErr error
+		updated, changed, innerErr = s.orderRepo.WithTx(tx).TransitionStatus(ctx, order.PublicID, domain.OrderCancelled, actor, reason)
+		if innerErr != nil || !changed {
+			return innerErr
+		}
+
+		return enqueueEvent(ctx, tx, events.OrderEventsExchange, events.OrderCancelledKey, events.OrderCancelled{
+			OrderID:        order.PublicID,
+			UserID:         order.UserID,
+			PreviousStatus: string(order.Status),
+			Reason:         reason,
+			CancelledAt:    time.Now(),
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("service: failed to fetch user orders: %w", err)
+		return nil, fmt.Errorf("service: failed to cancel order: %w", err)
 	}
-	return orders, nil
-}
 
-func (s *orderService) UpdateOrderStatus(ctx context.Context, orderId string, status string) error {
-	order, err := s.orderRepo.GetOrderByPublicID(ctx, orderId)
+	err = s.ReleaseStockReservation(ctx, order.PublicID, "order cancelled")
 	if err != nil {
-		return fmt.Errorf("service: failed to fetch order: %w", err)
+		logger.Error("service: failed to release stock for cancelled order", zap.String("order_id", order.PublicID), zap.Error(err))
 	}
-	order.Status = status
-	err = s.orderRepo.UpdateOrder(ctx, order)
+	return updated, nil
+}
+
+// enqueueEvent saves an event to the outbox in tx, to be published once tx
+// commits.
+func enqueueEvent(ctx context.Context, tx *gorm.DB, exchange string, eventType string, data events.Event) error {
+	event, err := events.New(ctx, domain.EventProducer, eventType, data)
 	if err != nil {
-		return fmt.Errorf("service: failed to update order: %w", err)
+		return err
 	}
-	return nil
+	return outbox.Enqueue(tx, exchange, event)
 }
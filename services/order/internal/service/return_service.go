@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/money"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/services/order/internal/client"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/repository"
+	"ecommerce/services/order/internal/returns"
+
+	pb "ecommerce/pkg/protobufs/catalog"
+
+	"github.com/sixafter/nanoid"
+	"gorm.io/gorm"
+)
+
+// ReturnItemRequest is one order line a buyer wants to send back. PhotoURLs
+// come from UploadReturnPhoto.
+type ReturnItemRequest struct {
+	ProductID string
+	Quantity  int
+	Reason    string
+	PhotoURLs []string
+}
+
+type ReturnService interface {
+	RequestReturn(ctx context.Context, userID string, orderID string, items []ReturnItemRequest) ([]domain.Return, error)
+	ListOrderReturns(ctx context.Context, userID string, orderID string) ([]domain.Return, error)
+	UploadReturnPhoto(ctx context.Context, userID string, orderID string, filename string, content []byte) (string, error)
+
+	ListSellerReturns(ctx context.Context, sellerID string, status domain.ReturnStatus, page int, limit int) ([]domain.Return, error)
+	Approve(ctx context.Context, sellerID string, returnID string) (*domain.Return, error)
+	Reject(ctx context.Context, sellerID string, returnID string, reason string) (*domain.Return, error)
+	ConfirmReceipt(ctx context.Context, sellerID string, returnID string) (*domain.Return, error)
+
+	ApplyPickup(ctx context.Context, returnID string, note string) (*domain.Return, error)
+	WithTx(tx *gorm.DB) ReturnService
+}
+
+type returnService struct {
+	returnRepo    repository.ReturnRepository
+	orderRepo     repository.OrderRepository
+	catalogClient pb.CatalogServiceClient
+	mediaClient   client.MediaClient
+	policy        *returns.Policy
+	nanoGen       nanoid.Interface
+	db            *gorm.DB
+}
+
+func NewReturnService(
+	returnRepo repository.ReturnRepository,
+	orderRepo repository.OrderRepository,
+	catalogClient pb.CatalogServiceClient,
+	mediaClient client.MediaClient,
+	policy *returns.Policy,
+	db *gorm.DB,
+) (ReturnService, error) {
+
+	gen, err := nanoid.NewGenerator(nanoid.WithAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to initialize nanoid generator: %w", err)
+	}
+
+	return &returnService{
+		returnRepo:    returnRepo,
+		orderRepo:     orderRepo,
+		catalogClient: catalogClient,
+		mediaClient:   mediaClient,
+		policy:        policy,
+		nanoGen:       gen,
+		db:            db,
+	}, nil
+}
+
+// WithTx returns a copy of the service whose return writes join tx.
+func (s *returnService) WithTx(tx *gorm.DB) ReturnService {
+	clone := *s
+	clone.returnRepo = s.returnRepo.WithTx(tx)
+	clone.orderRepo = s.orderRepo.WithTx(tx)
+	clone.db = tx
+	return &clone
+}
+
+// RequestReturn opens a return for each seller whose delivered lines the buyer
+// wants to send back. Every line has to be inside its category's return
+// window, counted from when that seller's fulfillment was delivered.
+func (s *returnService) RequestReturn(ctx context.Context, userID string, orderID string, items []ReturnItemRequest) ([]domain.Return, error) {
+	order, err := s.getUserOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	fulfillments := make(map[string]domain.Fulfillment, len(order.Fulfillments))
+	for _, f := range order.Fulfillments {
+		fulfillments[f.PublicID] = f
+	}
+
+	now := time.Now()
+	byFulfillment := make(map[string]*domain.Return)
+	var created []*domain.Return
+	seen := make(map[string]bool, len(items))
+
+	for _, req := range items {
+		if seen[req.ProductID] {
+			return nil, fmt.Errorf("service: %w: %s is listed more than once", domain.ErrReturnNotAllowed, req.ProductID)
+		}
+		seen[req.ProductID] = true
+
+		if req.Quantity <= 0 {
+			return nil, fmt.Errorf("service: %w: quantity for %s must be positive", domain.ErrReturnNotAllowed, req.ProductID)
+		}
+		if len(req.PhotoURLs) > domain.MaxReturnPhotos {
+			return nil, fmt.Errorf("service: %w: at most %d photos per item", domain.ErrReturnNotAllowed, domain.MaxReturnPhotos)
+		}
+
+		line, ok := findOrderLine(order.Items, req.ProductID)
+		if !ok {
+			return nil, fmt.Errorf("service: %w: %s is not part of this order", domain.ErrReturnNotAllowed, req.ProductID)
+		}
+
+		fulfillment, ok := fulfillments[line.FulfillmentID]
+		if !ok || fulfillment.Status != domain.FulfillmentDelivered || fulfillment.DeliveredAt == nil {
+			return nil, fmt.Errorf("service: %w: %s has not been delivered", domain.ErrReturnNotAllowed, req.ProductID)
+		}
+
+		deadline, ok := s.policy.Deadline(line.CategoryPath, *fulfillment.DeliveredAt)
+		if !ok {
+			return nil, fmt.Errorf("service: %w: %s is not returnable", domain.ErrReturnNotAllowed, req.ProductID)
+		}
+		if now.After(deadline) {
+			return nil, fmt.Errorf("service: %w: the return window for %s closed on %s", domain.ErrReturnNotAllowed, req.ProductID, deadline.Format("2 Jan 2006"))
+		}
+
+		ret, ok := byFulfillment[fulfillment.PublicID]
+		if !ok {
+			id, innerErr := s.nanoGen.NewWithLength(8)
+			if innerErr != nil {
+				return nil, fmt.Errorf("service: failed to generate return number: %w", innerErr)
+			}
+			ret = &domain.Return{
+				PublicID:      fmt.Sprintf("RET-%s", id),
+				OrderID:       order.PublicID,
+				FulfillmentID: fulfillment.PublicID,
+				SellerID:      fulfillment.SellerID,
+				UserID:        userID,
+				Status:        domain.ReturnRequested,
+				RefundAmount:  money.Zero(line.Price.Currency),
+			}
+			byFulfillment[fulfillment.PublicID] = ret
+			created = append(created, ret)
+		}
+
+		ret.RefundAmount, err = ret.RefundAmount.Add(line.Price.Mul(int64(req.Quantity)))
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to total return: %w", err)
+		}
+		ret.Items = append(ret.Items, domain.ReturnItem{
+			OrderItemID: line.ID,
+			ProductID:   line.ProductID,
+			Quantity:    req.Quantity,
+			UnitPrice:   line.Price,
+			Reason:      req.Reason,
+			PhotoURLs:   req.PhotoURLs,
+		})
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("service: %w: no items to return", domain.ErrReturnNotAllowed)
+	}
+
+	returnList := make([]domain.Return, 0, len(created))
+	for _, ret := range created {
+		returnList = append(returnList, *ret)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if innerErr := s.returnRepo.WithTx(tx).CreateReturns(ctx, order.PublicID, returnList); innerErr != nil {
+			return innerErr
+		}
+		for i := range returnList {
+			if innerErr := enqueueReturnEvent(ctx, tx, &returnList[i], order, ""); innerErr != nil {
+				return innerErr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to request return: %w", err)
+	}
+	return returnList, nil
+}
+
+func (s *returnService) ListOrderReturns(ctx context.Context, userID string, orderID string) ([]domain.Return, error) {
+	if _, err := s.getUserOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	returnList, err := s.returnRepo.ListOrderReturns(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list returns: %w", err)
+	}
+	return returnList, nil
+}
+
+// UploadReturnPhoto stores a picture of an item the buyer is about to return
+// and hands back its URL for the return request.
+func (s *returnService) UploadReturnPhoto(ctx context.Context, userID string, orderID string, filename string, content []byte) (string, error) {
+	if _, err := s.getUserOrder(ctx, userID, orderID); err != nil {
+		return "", err
+	}
+
+	url, err := s.mediaClient.UploadImage(ctx, "returns/"+orderID, filename, content)
+	if err != nil {
+		return "", fmt.Errorf("service: failed to upload return photo: %w", err)
+	}
+	return url, nil
+}
+
+func (s *returnService) ListSellerReturns(ctx context.Context, sellerID string, status domain.ReturnStatus, page int, limit int) ([]domain.Return, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("service: unknown return status %q", status)
+	}
+
+	returnList, err := s.returnRepo.ListSellerReturns(ctx, sellerID, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list seller returns: %w", err)
+	}
+	return returnList, nil
+}
+
+// Approve accepts a return. The logistics service schedules a pickup from the
+// buyer when it sees the return.approved event.
+func (s *returnService) Approve(ctx context.Context, sellerID string, returnID string) (*domain.Return, error) {
+	return s.transition(ctx, sellerID, returnID, domain.ReturnApproved, domain.ActorSeller, "")
+}
+
+func (s *returnService) Reject(ctx context.Context, sellerID string, returnID string, reason string) (*domain.Return, error) {
+	if reason == "" {
+		reason = "rejected by seller"
+	}
+	return s.transition(ctx, sellerID, returnID, domain.ReturnRejected, domain.ActorSeller, reason)
+}
+
+// ConfirmReceipt closes a return once the seller has the items back. They go
+// back on sale first, so a failed restock leaves the return open to retry;
+// the payment service refunds the buyer when it sees return.received.
+func (s *returnService) ConfirmReceipt(ctx context.Context, sellerID string, returnID string) (*domain.Return, error) {
+	ret, err := s.returnRepo.GetSellerReturn(ctx, returnID, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get return: %w", err)
+	}
+	if !ret.Status.CanTransitionTo(domain.ReturnReceived) {
+		return nil, fmt.Errorf("service: %w: %s -> %s", domain.ErrInvalidReturnTransition, ret.Status, domain.ReturnReceived)
+	}
+
+	stockItems := make([]*pb.StockItem, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		stockItems = append(stockItems, &pb.StockItem{ProductId: item.ProductID, Quantity: int32(item.Quantity)})
+	}
+
+	_, err = s.catalogClient.RestockReturn(ctx, &pb.RestockReturnRequest{ReturnId: ret.PublicID, Items: stockItems})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to restock returned items: %w", err)
+	}
+
+	return s.transition(ctx, sellerID, returnID, domain.ReturnReceived, domain.ActorSeller, "")
+}
+
+// ApplyPickup records that the courier collected the return from the buyer.
+func (s *returnService) ApplyPickup(ctx context.Context, returnID string, note string) (*domain.Return, error) {
+	return s.transition(ctx, "", returnID, domain.ReturnPickedUp, domain.ActorLogistics, note)
+}
+
+func (s *returnService) transition(ctx context.Context, sellerID string, returnID string, to domain.ReturnStatus, actor string, note string) (*domain.Return, error) {
+	var ret *domain.Return
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var innerErr error
+		ret, innerErr = s.returnRepo.WithTx(tx).TransitionReturn(ctx, returnID, sellerID, to, actor, note)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		order, innerErr := s.orderRepo.WithTx(tx).GetOrderByPublicID(ctx, ret.OrderID)
+		if innerErr != nil {
+			return fmt.Errorf("could not load order %s for the return event: %w", ret.OrderID, innerErr)
+		}
+		return enqueueReturnEvent(ctx, tx, ret, order, note)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update return: %w", err)
+	}
+	return ret, nil
+}
+
+func enqueueReturnEvent(ctx context.Context, tx *gorm.DB, ret *domain.Return, order *domain.Order, reason string) error {
+	event := events.ReturnStatusChanged{
+		ReturnID:      ret.PublicID,
+		OrderID:       ret.OrderID,
+		FulfillmentID: ret.FulfillmentID,
+		SellerID:      ret.SellerID,
+		UserID:        ret.UserID,
+		Status:        string(ret.Status),
+		Reason:        reason,
+		Amount:        ret.RefundAmount,
+		OccurredAt:    time.Now(),
+
+		PickupName:    order.ShippingName,
+		PickupPhone:   order.ShippingPhone,
+		PickupAddress: order.ShippingAddress,
+		PickupCity:    order.ShippingCity,
+		PickupState:   order.ShippingState,
+		PickupZip:     order.ShippingZip,
+	}
+	for _, item := range ret.Items {
+		event.Items = append(event.Items, events.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return enqueueEvent(ctx, tx, events.OrderEventsExchange, events.ReturnEventKey(string(ret.Status)), event)
+}
+
+func (s *returnService) getUserOrder(ctx context.Context, userID string, orderID string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrderByPublicID(ctx, orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("service: %w: %s", domain.ErrOrderNotFound, orderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to fetch order: %w", err)
+	}
+	if order.UserID != userID {
+		return nil, fmt.Errorf("service: %w: %s", domain.ErrOrderNotFound, orderID)
+	}
+	return order, nil
+}
+
+func findOrderLine(items []domain.OrderItem, productID string) (domain.OrderItem, bool) {
+	for _, item := range items {
+		if item.ProductID == productID {
+			return item, true
+		}
+	}
+	return domain.OrderItem{}, false
+}
@@ -0,0 +1,109 @@
+package tax
+
+import (
+	"fmt"
+
+	"ecommerce/pkg/money"
+)
+
+const (
+	SupplyIntraState = "intra_state"
+	SupplyInterState = "inter_state"
+	// SupplyExport covers shipments outside India, which are zero-rated.
+	SupplyExport = "export"
+	// SupplyUnregistered covers sellers without a GSTIN, who cannot collect GST.
+	SupplyUnregistered = "unregistered"
+)
+
+// Line is one order line to be taxed. Catalog prices are GST-inclusive, so the
+// tax is carved out of the line total rather than added on top of it.
+type Line struct {
+	CategoryPath string
+	SellerGSTIN  string
+	Quantity     int
+	UnitPrice    money.Money
+}
+
+type Breakdown struct {
+	SupplyType   string
+	RateBps      int64
+	TaxableValue money.Money
+	CGST         money.Money
+	SGST         money.Money
+	IGST         money.Money
+}
+
+func (b Breakdown) Tax() money.Money {
+	return money.New(b.CGST.Amount+b.SGST.Amount+b.IGST.Amount, b.TaxableValue.Currency)
+}
+
+type Engine struct {
+	slabs *Slabs
+}
+
+func NewEngine(slabs *Slabs) *Engine {
+	return &Engine{slabs: slabs}
+}
+
+// Calculate splits a line into taxable value and GST. Supplies within the
+// seller's registered state pay CGST and SGST in equal halves; supplies to
+// another state pay IGST. The components always add up to the line total.
+func (e *Engine) Calculate(line Line, shippingState string) (Breakdown, error) {
+	if line.Quantity <= 0 {
+		return Breakdown{}, fmt.Errorf("tax: invalid quantity %d", line.Quantity)
+	}
+
+	gross := line.UnitPrice.Mul(int64(line.Quantity))
+	zero := money.Zero(gross.Currency)
+	breakdown := Breakdown{TaxableValue: gross, CGST: zero, SGST: zero, IGST: zero}
+
+	sellerState, registered := StateCodeFromGSTIN(line.SellerGSTIN)
+	if !registered {
+		breakdown.SupplyType = SupplyUnregistered
+		return breakdown, nil
+	}
+
+	buyerState, domestic := StateCode(shippingState)
+	if !domestic {
+		breakdown.SupplyType = SupplyExport
+		return breakdown, nil
+	}
+
+	rate := e.slabs.RateFor(line.CategoryPath)
+	breakdown.RateBps = rate
+
+	// taxable = gross * 10000 / (10000 + rate)
+	taxable := money.New(divRound(gross.Amount*10000, 10000+rate), gross.Currency)
+	tax, err := gross.Sub(taxable)
+	if err != nil {
+		return Breakdown{}, err
+	}
+	breakdown.TaxableValue = taxable
+
+	if sellerState == buyerState {
+		breakdown.SupplyType = SupplyIntraState
+		breakdown.CGST = money.New(divRound(tax.Amount, 2), gross.Currency)
+		breakdown.SGST = money.New(tax.Amount-breakdown.CGST.Amount, gross.Currency)
+	} else {
+		breakdown.SupplyType = SupplyInterState
+		breakdown.IGST = tax
+	}
+
+	return breakdown, nil
+}
+
+func divRound(numerator int64, denominator int64) int64 {
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder*2 >= denominator {
+		if numerator < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return quotient
+}
@@ -0,0 +1,90 @@
+package tax
+
+import (
+	"testing"
+
+	"ecommerce/pkg/money"
+)
+
+func TestCalculate(t *testing.T) {
+	slabs, err := NewSlabs(DefaultRateBps, []Slab{
+		{CategoryPath: "cat_books", RateBps: 0},
+		{CategoryPath: "cat_electronics", RateBps: 1800},
+		{CategoryPath: "cat_electronics.cat_mobiles", RateBps: 1200},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(slabs)
+
+	const maharashtraSeller = "27AAPFU0939F1ZV"
+
+	tests := []struct {
+		name    string
+		line    Line
+		shipTo  string
+		supply  string
+		rate    int64
+		taxable int64
+		cgst    int64
+		sgst    int64
+		igst    int64
+	}{
+		{
+			name:   "intra-state splits CGST and SGST",
+			line:   Line{CategoryPath: "cat_electronics.cat_laptops", SellerGSTIN: maharashtraSeller, Quantity: 1, UnitPrice: money.New(118000, "INR")},
+			shipTo: "Maharashtra",
+			supply: SupplyIntraState, rate: 1800, taxable: 100000, cgst: 9000, sgst: 9000,
+		},
+		{
+			name:   "inter-state charges IGST at the most specific slab",
+			line:   Line{CategoryPath: "cat_electronics.cat_mobiles.cat_android", SellerGSTIN: maharashtraSeller, Quantity: 2, UnitPrice: money.New(11200, "INR")},
+			shipTo: "karnataka",
+			supply: SupplyInterState, rate: 1200, taxable: 20000, igst: 2400,
+		},
+		{
+			name:   "odd tax splits without losing a paisa",
+			line:   Line{CategoryPath: "cat_toys", SellerGSTIN: maharashtraSeller, Quantity: 1, UnitPrice: money.New(999, "INR")},
+			shipTo: "27",
+			supply: SupplyIntraState, rate: 1800, taxable: 847, cgst: 76, sgst: 76,
+		},
+		{
+			name:   "exempt slab",
+			line:   Line{CategoryPath: "cat_books", SellerGSTIN: maharashtraSeller, Quantity: 3, UnitPrice: money.New(50000, "INR")},
+			shipTo: "Delhi",
+			supply: SupplyInterState, rate: 0, taxable: 150000,
+		},
+		{
+			name:   "export is zero-rated",
+			line:   Line{CategoryPath: "cat_toys", SellerGSTIN: maharashtraSeller, Quantity: 1, UnitPrice: money.New(2000, "USD")},
+			shipTo: "California",
+			supply: SupplyExport, taxable: 2000,
+		},
+		{
+			name:   "unregistered seller collects no GST",
+			line:   Line{CategoryPath: "cat_toys", Quantity: 1, UnitPrice: money.New(50000, "INR")},
+			shipTo: "Maharashtra",
+			supply: SupplyUnregistered, taxable: 50000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Calculate(tt.line, tt.shipTo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.SupplyType != tt.supply || got.RateBps != tt.rate {
+				t.Fatalf("got supply %s at %d bps, want %s at %d bps", got.SupplyType, got.RateBps, tt.supply, tt.rate)
+			}
+			if got.TaxableValue.Amount != tt.taxable || got.CGST.Amount != tt.cgst || got.SGST.Amount != tt.sgst || got.IGST.Amount != tt.igst {
+				t.Fatalf("got taxable %d cgst %d sgst %d igst %d", got.TaxableValue.Amount, got.CGST.Amount, got.SGST.Amount, got.IGST.Amount)
+			}
+
+			gross := tt.line.UnitPrice.Mul(int64(tt.line.Quantity))
+			if got.TaxableValue.Amount+got.Tax().Amount != gross.Amount {
+				t.Fatalf("components do not add up to %v", gross)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultRateBps applies to categories no slab matches: 18%, the standard GST
+// rate for most goods.
+const DefaultRateBps int64 = 1800
+
+// Slab assigns a GST rate, in basis points, to a category and everything
+// beneath it. CategoryPath is a catalog Category.Path or any ancestor prefix
+// of one, e.g. "cat_electronics" or "cat_electronics.cat_mobiles".
+type Slab struct {
+	CategoryPath string `json:"category_path"`
+	RateBps      int64  `json:"rate_bps"`
+}
+
+type Slabs struct {
+	defaultBps int64
+	byPath     map[string]int64
+}
+
+func NewSlabs(defaultBps int64, slabs []Slab) (*Slabs, error) {
+	if defaultBps < 0 || defaultBps > 10000 {
+		return nil, fmt.Errorf("tax: invalid default rate %d bps", defaultBps)
+	}
+
+	s := &Slabs{defaultBps: defaultBps, byPath: make(map[string]int64, len(slabs))}
+	for _, slab := range slabs {
+		path := strings.Trim(strings.TrimSpace(slab.CategoryPath), ".")
+		if path == "" {
+			return nil, fmt.Errorf("tax: slab without category path")
+		}
+		if slab.RateBps < 0 || slab.RateBps > 10000 {
+			return nil, fmt.Errorf("tax: invalid rate %d bps for %s", slab.RateBps, path)
+		}
+		s.byPath[path] = slab.RateBps
+	}
+	return s, nil
+}
+
+type slabFile struct {
+	DefaultRateBps *int64 `json:"default_rate_bps"`
+	Slabs          []Slab `json:"slabs"`
+}
+
+// LoadSlabs reads a JSON slab table:
+//
+//	{"default_rate_bps": 1800, "slabs": [{"category_path": "cat_books", "rate_bps": 0}]}
+func LoadSlabs(path string) (*Slabs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tax: failed to read slab file: %w", err)
+	}
+
+	var file slabFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("tax: failed to parse slab file %s: %w", path, err)
+	}
+
+	defaultBps := DefaultRateBps
+	if file.DefaultRateBps != nil {
+		defaultBps = *file.DefaultRateBps
+	}
+	return NewSlabs(defaultBps, file.Slabs)
+}
+
+// RateFor returns the rate of the most specific slab covering categoryPath.
+func (s *Slabs) RateFor(categoryPath string) int64 {
+	path := strings.Trim(categoryPath, ".")
+	for path != "" {
+		if rate, ok := s.byPath[path]; ok {
+			return rate
+		}
+		cut := strings.LastIndex(path, ".")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return s.defaultBps
+}
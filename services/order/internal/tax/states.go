@@ -0,0 +1,90 @@
+package tax
+
+import "strings"
+
+// stateCodes maps GST state codes, the first two digits of a GSTIN, to state
+// and union territory names.
+var stateCodes = map[string]string{
+	"01": "Jammu and Kashmir",
+	"02": "Himachal Pradesh",
+	"03": "Punjab",
+	"04": "Chandigarh",
+	"05": "Uttarakhand",
+	"06": "Haryana",
+	"07": "Delhi",
+	"08": "Rajasthan",
+	"09": "Uttar Pradesh",
+	"10": "Bihar",
+	"11": "Sikkim",
+	"12": "Arunachal Pradesh",
+	"13": "Nagaland",
+	"14": "Manipur",
+	"15": "Mizoram",
+	"16": "Tripura",
+	"17": "Meghalaya",
+	"18": "Assam",
+	"19": "West Bengal",
+	"20": "Jharkhand",
+	"21": "Odisha",
+	"22": "Chhattisgarh",
+	"23": "Madhya Pradesh",
+	"24": "Gujarat",
+	"26": "Dadra and Nagar Haveli and Daman and Diu",
+	"27": "Maharashtra",
+	"29": "Karnataka",
+	"30": "Goa",
+	"31": "Lakshadweep",
+	"32": "Kerala",
+	"33": "Tamil Nadu",
+	"34": "Puducherry",
+	"35": "Andaman and Nicobar Islands",
+	"36": "Telangana",
+	"37": "Andhra Pradesh",
+	"38": "Ladakh",
+}
+
+var aliases = map[string]string{
+	"new delhi":              "07",
+	"nct of delhi":           "07",
+	"orissa":                 "21",
+	"pondicherry":            "34",
+	"j&k":                    "01",
+	"daman and diu":          "26",
+	"dadra and nagar haveli": "26",
+}
+
+var codesByName = func() map[string]string {
+	byName := make(map[string]string, len(stateCodes)+len(aliases))
+	for code, name := range stateCodes {
+		byName[strings.ToLower(name)] = code
+	}
+	for alias, code := range aliases {
+		byName[alias] = code
+	}
+	return byName
+}()
+
+// StateCode resolves a shipping state, given as a name ("Maharashtra") or as a
+// GST code ("27"), to its GST code. It reports false for places outside India.
+func StateCode(state string) (string, bool) {
+	state = strings.ToLower(strings.TrimSpace(state))
+	if _, ok := stateCodes[state]; ok {
+		return state, true
+	}
+	code, ok := codesByName[state]
+	return code, ok
+}
+
+// StateCodeFromGSTIN returns the state a seller is registered in.
+func StateCodeFromGSTIN(gstin string) (string, bool) {
+	gstin = strings.TrimSpace(gstin)
+	if len(gstin) != 15 {
+		return "", false
+	}
+	_, ok := stateCodes[gstin[:2]]
+	return gstin[:2], ok
+}
+
+func StateName(code string) string {
+	return stateCodes[code]
+}
@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	UpdateStatus     = "status"
+	UpdateCheckpoint = "checkpoint"
+)
+
+// Update is one piece of shipment news pushed to a buyer watching their order.
+type Update struct {
+	Type          string    `json:"type"`
+	ShipmentID    string    `json:"shipment_id"`
+	FulfillmentID string    `json:"fulfillment_id"`
+	OrderID       string    `json:"order_id"`
+	Status        string    `json:"status"`
+	Note          string    `json:"note,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Hub fans shipment updates out to the open streams for an order on this
+// instance. A subscriber that falls behind misses updates rather than
+// holding up the others; clients refetch the timeline when they reconnect.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Update]struct{}
+	buffer      int
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[chan Update]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe returns a channel of updates for the order and a function that
+// closes it. The function must be called once the caller stops reading.
+func (h *Hub) Subscribe(orderID string) (<-chan Update, func()) {
+	ch := make(chan Update, h.buffer)
+
+	h.mu.Lock()
+	if h.subscribers[orderID] == nil {
+		h.subscribers[orderID] = make(map[chan Update]struct{})
+	}
+	h.subscribers[orderID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[orderID], ch)
+			if len(h.subscribers[orderID]) == 0 {
+				delete(h.subscribers, orderID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers the update to every subscriber of its order and reports
+// how many received it.
+func (h *Hub) Publish(update Update) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered := 0
+	for ch := range h.subscribers[update.OrderID] {
+		select {
+		case ch <- update:
+			delivered++
+		default:
+		}
+	}
+	return delivered
+}
@@ -0,0 +1,48 @@
+package tracking
+
+import "testing"
+
+func TestHubDeliversToOrderSubscribers(t *testing.T) {
+	hub := NewHub(1)
+
+	first, cancelFirst := hub.Subscribe("ORD-1")
+	defer cancelFirst()
+	second, cancelSecond := hub.Subscribe("ORD-1")
+	other, cancelOther := hub.Subscribe("ORD-2")
+	defer cancelOther()
+
+	if got := hub.Publish(Update{OrderID: "ORD-1", Status: "picked_up"}); got != 2 {
+		t.Fatalf("delivered to %d subscribers, want 2", got)
+	}
+	if u := <-first; u.Status != "picked_up" {
+		t.Errorf("first subscriber got %q", u.Status)
+	}
+	if u := <-second; u.Status != "picked_up" {
+		t.Errorf("second subscriber got %q", u.Status)
+	}
+	select {
+	case u := <-other:
+		t.Errorf("other order received %v", u)
+	default:
+	}
+
+	cancelSecond()
+	cancelSecond()
+	if _, ok := <-second; ok {
+		t.Error("channel still open after cancel")
+	}
+	if got := hub.Publish(Update{OrderID: "ORD-1"}); got != 1 {
+		t.Errorf("delivered to %d subscribers after cancel, want 1", got)
+	}
+}
+
+func TestHubSkipsSlowSubscribers(t *testing.T) {
+	hub := NewHub(1)
+	_, cancel := hub.Subscribe("ORD-1")
+	defer cancel()
+
+	hub.Publish(Update{OrderID: "ORD-1"})
+	if got := hub.Publish(Update{OrderID: "ORD-1"}); got != 0 {
+		t.Errorf("full subscriber counted as delivered: %d", got)
+	}
+}
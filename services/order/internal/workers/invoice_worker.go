@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type InvoiceWorker struct {
+	invoiceService service.InvoiceService
+	interval       time.Duration
+}
+
+func NewInvoiceWorker(invoiceService service.InvoiceService, interval time.Duration) *InvoiceWorker {
+	return &InvoiceWorker{invoiceService: invoiceService, interval: interval}
+}
+
+func (w *InvoiceWorker) StartInvoiceWorker(ctx context.Context) {
+	logger.Info("worker: Invoice retry worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Invoice retry worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			completed, err := w.invoiceService.RetryPending(ctx)
+			if err != nil {
+				logger.Error("worker: failed to retry pending invoices", zap.Error(err))
+				continue
+			}
+			if completed > 0 {
+				logger.Info("worker: completed pending order invoices", zap.Int("order_count", completed))
+			}
+		}
+	}
+}
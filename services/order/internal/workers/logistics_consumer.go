@@ -0,0 +1,158 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/inbox"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/domain"
+	"ecommerce/services/order/internal/service"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// shipmentStatuses maps the shipment updates the order cares about to the
+// fulfillment status they imply.
+var shipmentStatuses = map[string]domain.FulfillmentStatus{
+	"picked_up":        domain.FulfillmentShipped,
+	"out_for_delivery": domain.FulfillmentOutForDelivery,
+	"delivered":        domain.FulfillmentDelivered,
+}
+
+type LogisticsConsumer struct {
+	rabbitMQ           *broker.RabbitMQClient
+	orderService       service.OrderService
+	fulfillmentService service.FulfillmentService
+	returnService      service.ReturnService
+	inbox              *inbox.Inbox
+}
+
+func NewLogisticsConsumer(rabbitMQ *broker.RabbitMQClient, orderSvc service.OrderService, fulfillmentSvc service.FulfillmentService, returnSvc service.ReturnService, inbox *inbox.Inbox) *LogisticsConsumer {
+	return &LogisticsConsumer{
+		rabbitMQ:           rabbitMQ,
+		orderService:       orderSvc,
+		fulfillmentService: fulfillmentSvc,
+		returnService:      returnSvc,
+		inbox:              inbox,
+	}
+}
+
+func (c *LogisticsConsumer) StartListening(ctx context.Context) error {
+	consumer := broker.NewConsumer(c.rabbitMQ, broker.ConsumerConfig{
+		Exchange: events.LogisticsEventsExchange,
+		Queue:    "order_service_logistics_queue",
+	})
+
+	routingKeys := []string{
+		events.ShipmentEventKey("picked_up"),
+		events.ShipmentEventKey("out_for_delivery"),
+		events.ShipmentEventKey("delivered"),
+		events.ShipmentEventKey("delivery_failed"),
+	}
+
+	for _, routingKey := range routingKeys {
+		broker.Handle(consumer, routingKey, c.processMessage)
+	}
+
+	logger.Info("Order Service is now listening for logistics events...")
+
+	err := consumer.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to consume logistics events: %w", err)
+	}
+
+	logger.Info("Shutting down logistics consumer gracefully...")
+	return nil
+}
+
+func (c *LogisticsConsumer) processMessage(ctx context.Context, msg broker.Message, payload events.ShipmentStatusChanged) error {
+	if payload.FulfillmentID == "" {
+		return broker.Permanent(errors.New("shipment event has no fulfillment id"))
+	}
+
+	if payload.ReturnID != "" {
+		return c.processReturnShipment(ctx, msg, payload)
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		if payload.Status == "delivery_failed" {
+			return c.orderService.WithTx(tx).AddOrderNote(ctx, payload.OrderID, domain.ActorLogistics, deliveryFailedNote(payload.Note))
+		}
+
+		to, ok := shipmentStatuses[payload.Status]
+		if !ok {
+			logger.Info("Ignoring shipment event with unknown status", zap.String("status", payload.Status), zap.String("fulfillment", payload.FulfillmentID))
+			return nil
+		}
+
+		_, innerErr := c.fulfillmentService.WithTx(tx).ApplyShipmentStatus(ctx, payload.FulfillmentID, to, payload.Note)
+		return innerErr
+	})
+
+	// A repeat attempt after a failed delivery reports out_for_delivery again;
+	// the fulfillment is already there.
+	if errors.Is(err, domain.ErrInvalidFulfillmentTransition) || errors.Is(err, domain.ErrFulfillmentNotFound) {
+		logger.Info("Shipment event does not move the fulfillment, dropping message",
+			zap.Error(err),
+			zap.String("fulfillment", payload.FulfillmentID),
+			zap.String("shipment_status", payload.Status),
+		)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply shipment event to fulfillment %s: %w", payload.FulfillmentID, err)
+	}
+
+	if !processed {
+		logger.Info("Skipping already processed shipment event", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("Shipment event applied to order", zap.String("fulfillment_id", payload.FulfillmentID), zap.String("shipment_status", payload.Status))
+	return nil
+}
+
+// processReturnShipment follows the pickup of a return. Receipt is confirmed
+// by the seller, so a delivered return pickup is only noted on the order.
+func (c *LogisticsConsumer) processReturnShipment(ctx context.Context, msg broker.Message, payload events.ShipmentStatusChanged) error {
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		switch payload.Status {
+		case "picked_up":
+			_, innerErr := c.returnService.WithTx(tx).ApplyPickup(ctx, payload.ReturnID, payload.Note)
+			return innerErr
+		case "delivered":
+			return c.orderService.WithTx(tx).AddOrderNote(ctx, payload.OrderID, domain.ActorLogistics, "return "+payload.ReturnID+" delivered to seller")
+		}
+		return nil
+	})
+
+	if errors.Is(err, domain.ErrInvalidReturnTransition) || errors.Is(err, domain.ErrReturnNotFound) {
+		logger.Info("Shipment event does not move the return, dropping message",
+			zap.Error(err),
+			zap.String("return", payload.ReturnID),
+			zap.String("shipment_status", payload.Status),
+		)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply shipment event to return %s: %w", payload.ReturnID, err)
+	}
+
+	if !processed {
+		logger.Info("Skipping already processed shipment event", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("Shipment event applied to return", zap.String("return_id", payload.ReturnID), zap.String("shipment_status", payload.Status))
+	return nil
+}
+
+func deliveryFailedNote(note string) string {
+	if note == "" {
+		return "delivery attempt failed"
+	}
+	return "delivery attempt failed: " + note
+}
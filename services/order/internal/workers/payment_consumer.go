@@ -2,134 +2,155 @@ package workers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
+	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/inbox"
 	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/domain"
 	"ecommerce/services/order/internal/repository" // <-- Import your repository package
 	"ecommerce/services/order/internal/service"
 
-	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type PaymentConsumer struct {
-	rabbitChannel *amqp.Channel
-	orderService  service.OrderService
-	cartRepo      repository.CartRepository
+	rabbitMQ       *broker.RabbitMQClient
+	orderService   service.OrderService
+	invoiceService service.InvoiceService
+	cartRepo       repository.CartRepository
+	inbox          *inbox.Inbox
 }
 
-type PaymentEventPayload struct {
-	OrderID string `json:"order_id"`
-	UserID  string `json:"user_id"`
-	Status  string `json:"status"`
-}
-
-func NewPaymentConsumer(ch *amqp.Channel, svc service.OrderService, cartRepo repository.CartRepository) *PaymentConsumer {
+func NewPaymentConsumer(rabbitMQ *broker.RabbitMQClient, svc service.OrderService, invoiceSvc service.InvoiceService, cartRepo repository.CartRepository, inbox *inbox.Inbox) *PaymentConsumer {
 	return &PaymentConsumer{
-		rabbitChannel: ch,
-		orderService:  svc,
-		cartRepo:      cartRepo,
+		rabbitMQ:       rabbitMQ,
+		orderService:   svc,
+		invoiceService: invoiceSvc,
+		cartRepo:       cartRepo,
+		inbox:          inbox,
 	}
 }
 func (c *PaymentConsumer) StartListening(ctx context.Context) error {
-	queue, err := c.rabbitChannel.QueueDeclare(
-		"order_service_payment_queue", // name
-		true,                          // durable
-		false,                         // delete when unused
-		false,                         // exclusive
-		false,                         // no-wait
-		nil,                           // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare a queue: %w", err)
+	consumer := broker.NewConsumer(c.rabbitMQ, broker.ConsumerConfig{
+		Exchange: events.PaymentEventsExchange,
+		Queue:    "order_service_payment_queue",
+	})
+
+	routingKeys := []string{
+		events.OrderPaidKey,
+		events.PaymentFailedKey,
+		events.PaymentExpiredKey,
+		events.PaymentRefundedKey,
+		events.PaymentDisputedKey,
 	}
 
-	err = c.rabbitChannel.ExchangeDeclare(
-		"payment_events", // name
-		"topic",          // type
-		true,             // durable
-		false,            // auto-deleted
-		false,            // internal
-		false,            // no-wait
-		nil,              // arguments
-	)
-	if err != nil {
-		return fmt.Errorf("failed to declare exchange: %w", err)
+	for _, routingKey := range routingKeys {
+		broker.Handle(consumer, routingKey, c.processMessage)
 	}
 
-	err = c.rabbitChannel.QueueBind(
-		queue.Name,          // queue name
-		"payment.OrderPaid", // routing key we are listening for
-		"payment_events",    // exchange name
-		false,
-		nil,
-	)
+	logger.Info("Order Service is now listening for payment events...")
+
+	err := consumer.Run(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to bind queue: %w", err)
+		return fmt.Errorf("failed to consume payment events: %w", err)
 	}
 
-	messages, err := c.rabbitChannel.Consume(
-		queue.Name,
-		"order_service_consumer", // consumer tag
-		false,                    // auto-ack (set to false)
-		false,                    // exclusive
-		false,                    // no-local
-		false,                    // no-wait
-		nil,                      // args
-	)
+	logger.Info("Shutting down payment consumer gracefully...")
+	return nil
+}
+
+func (c *PaymentConsumer) processMessage(ctx context.Context, msg broker.Message, payload events.PaymentStatusChanged) error {
+	// The status change and the inbox record commit together, so a redelivered
+	// event neither moves the order twice nor repeats the follow-up work below.
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		return c.applyStatus(ctx, c.orderService.WithTx(tx), payload)
+	})
+
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		logger.Error("Payment event does not fit the order's current status, dropping message",
+			zap.Error(err),
+			zap.String("order", payload.OrderID),
+			zap.String("payment_status", payload.Status),
+		)
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to register a consumer: %w", err)
+		return fmt.Errorf("failed to apply payment event to order %s: %w", payload.OrderID, err)
 	}
 
-	logger.Info("Order Service is now listening for payment events...")
-
-	for {
-		select {
-		case <-ctx.Done():
-			logger.Info("Shutting down payment consumer gracefully...")
-			return nil
-		case msg, ok := <-messages:
-			if !ok {
-				logger.Error("RabbitMQ channel closed")
-				return nil
-			}
-			c.processMessage(ctx, msg)
-		}
+	if !processed {
+		logger.Info("Skipping already processed payment event", zap.String("message_id", msg.ID))
+		return nil
 	}
-}
 
-func (c *PaymentConsumer) processMessage(ctx context.Context, msg amqp.Delivery) {
-	var payload PaymentEventPayload
+	c.afterStatusApplied(ctx, payload)
+
+	logger.Info("Payment event applied to order", zap.String("order_id", payload.OrderID), zap.String("payment_status", payload.Status))
+	return nil
+}
 
-	if err := json.Unmarshal(msg.Body, &payload); err != nil {
-		logger.Error("Failed to unmarshal payment event, dropping message", zap.Error(err))
-		msg.Nack(false, false)
-		return
+func (c *PaymentConsumer) applyStatus(ctx context.Context, orderService service.OrderService, payload events.PaymentStatusChanged) error {
+	switch payload.Status {
+	case "paid", "success":
+		return orderService.TransitionOrderStatus(ctx, payload.OrderID, domain.OrderPaid, domain.ActorPayment, "payment captured")
+	case "failed":
+		return orderService.TransitionOrderStatus(ctx, payload.OrderID, domain.OrderCancelled, domain.ActorPayment, "payment failed")
+	case "expired":
+		return orderService.TransitionOrderStatus(ctx, payload.OrderID, domain.OrderCancelled, domain.ActorPayment, "payment session expired")
+	case "refunded":
+		return orderService.TransitionOrderStatus(ctx, payload.OrderID, domain.OrderRefunded, domain.ActorPayment, "payment refunded")
+	case "disputed":
+		return orderService.AddOrderNote(ctx, payload.OrderID, domain.ActorPayment, disputeNote(payload.Reason))
 	}
 
-	if payload.Status == "paid" || payload.Status == "success" {
+	logger.Info("Ignoring payment event with unknown status", zap.String("status", payload.Status), zap.String("order", payload.OrderID))
+	return nil
+}
 
-		err := c.orderService.UpdateOrderStatus(ctx, payload.OrderID, "paid")
+// afterStatusApplied runs the side effects that live outside the order
+// database. They are safe to repeat but are only attempted once per event.
+func (c *PaymentConsumer) afterStatusApplied(ctx context.Context, payload events.PaymentStatusChanged) {
+	switch payload.Status {
+	case "paid", "success":
+		err := c.orderService.CommitStockReservation(ctx, payload.OrderID)
 		if err != nil {
-			logger.Error("Failed to update order status in DB", zap.Error(err), zap.String("order", payload.OrderID))
-			msg.Nack(false, true)
-			return
+			logger.Error("Failed to commit stock reservation for paid order, the stock worker will retry", zap.Error(err), zap.String("order", payload.OrderID))
+		}
+
+		// Anything left undone here is picked up by the invoice worker.
+		err = c.invoiceService.IssueInvoices(ctx, payload.OrderID)
+		if err != nil {
+			logger.Error("Failed to issue invoices for paid order", zap.Error(err), zap.String("order", payload.OrderID))
 		}
 
 		order, err := c.orderService.GetOrder(ctx, payload.OrderID, payload.UserID)
 		if err != nil {
 			logger.Error("Failed to fetch order to clear cart", zap.Error(err))
+			return
+		}
+
+		err = c.cartRepo.ClearCart(ctx, order.UserID)
+		if err != nil {
+			logger.Error("Failed to clear cart, but order was paid", zap.Error(err))
 		} else {
-			err = c.cartRepo.ClearCart(ctx, order.UserID)
-			if err != nil {
-				logger.Error("Failed to clear cart, but order was paid", zap.Error(err))
-			} else {
-				logger.Info("Cart successfully cleared for user", zap.String("user_id", order.UserID))
-			}
+			logger.Info("Cart successfully cleared for user", zap.String("user_id", order.UserID))
+		}
+
+	case "failed", "expired":
+		err := c.orderService.ReleaseStockReservation(ctx, payload.OrderID, "payment "+payload.Status)
+		if err != nil {
+			logger.Error("Failed to release stock reservation for unpaid order", zap.Error(err), zap.String("order", payload.OrderID))
 		}
 	}
+}
 
-	msg.Ack(false)
-	logger.Info("Order successfully marked as paid from RabbitMQ event!", zap.String("order_id", payload.OrderID))
+func disputeNote(reason string) string {
+	if reason == "" {
+		return "payment disputed"
+	}
+	return "payment disputed: " + reason
 }
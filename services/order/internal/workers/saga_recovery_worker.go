@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type SagaRecoveryWorker struct {
+	orchestrator service.CheckoutOrchestrator
+	interval     time.Duration
+	staleAfter   time.Duration
+}
+
+func NewSagaRecoveryWorker(orchestrator service.CheckoutOrchestrator, interval time.Duration, staleAfter time.Duration) *SagaRecoveryWorker {
+	return &SagaRecoveryWorker{orchestrator: orchestrator, interval: interval, staleAfter: staleAfter}
+}
+
+func (w *SagaRecoveryWorker) StartSagaRecoveryWorker(ctx context.Context) {
+	logger.Info("worker: Checkout saga recovery worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Checkout saga recovery worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			recovered, err := w.orchestrator.Recover(ctx, w.staleAfter)
+			if err != nil {
+				logger.Error("worker: failed to recover checkout sagas", zap.Error(err))
+				continue
+			}
+			if recovered > 0 {
+				logger.Info("worker: compensated abandoned checkout sagas", zap.Int("saga_count", recovered))
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// StockWorker commits the stock of paid orders whose commit failed when the
+// payment event was handled, before their hold runs out.
+type StockWorker struct {
+	orderService service.OrderService
+	interval     time.Duration
+}
+
+func NewStockWorker(orderService service.OrderService, interval time.Duration) *StockWorker {
+	return &StockWorker{orderService: orderService, interval: interval}
+}
+
+func (w *StockWorker) StartStockWorker(ctx context.Context) {
+	logger.Info("worker: Stock commit retry worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Stock commit retry worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			committed, err := w.orderService.RetryStockCommits(ctx)
+			if err != nil {
+				logger.Error("worker: failed to retry stock commits", zap.Error(err))
+				continue
+			}
+			if committed > 0 {
+				logger.Info("worker: committed stock for paid orders", zap.Int("order_count", committed))
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+
+	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/order/internal/tracking"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// TrackingConsumer feeds every shipment event into this instance's tracking
+// hub. Each instance has its own temporary queue so that a buyer's stream gets
+// the update whichever instance it is connected to. Nothing is persisted here,
+// so messages are acknowledged as soon as they reach the hub.
+type TrackingConsumer struct {
+	rabbitMQ *broker.RabbitMQClient
+	hub      *tracking.Hub
+}
+
+func NewTrackingConsumer(rabbitMQ *broker.RabbitMQClient, hub *tracking.Hub) *TrackingConsumer {
+	return &TrackingConsumer{rabbitMQ: rabbitMQ, hub: hub}
+}
+
+func (c *TrackingConsumer) StartListening(ctx context.Context) error {
+	err := c.rabbitMQ.DeclareExchange(events.LogisticsEventsExchange, "topic")
+	if err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	queue, err := c.rabbitMQ.DeclareTemporaryQueue("order_service_tracking")
+	if err != nil {
+		return fmt.Errorf("failed to declare a queue: %w", err)
+	}
+
+	err = c.rabbitMQ.BindQueue(queue.Name, events.LogisticsEventsExchange, "shipment.#")
+	if err != nil {
+		return fmt.Errorf("failed to bind queue: %w", err)
+	}
+
+	messages, err := c.rabbitMQ.Consume(ctx, queue.Name)
+	if err != nil {
+		return fmt.Errorf("failed to register a consumer: %w", err)
+	}
+
+	logger.Info("Order Service is now streaming shipment updates to buyers...")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Shutting down tracking consumer gracefully...")
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				logger.Info("RabbitMQ client closed, stopping consumer")
+				return nil
+			}
+			c.processMessage(msg)
+			msg.Ack(false)
+		}
+	}
+}
+
+func (c *TrackingConsumer) processMessage(msg amqp.Delivery) {
+	update, err := trackingUpdate(msg)
+	if err != nil || update.OrderID == "" {
+		logger.Error("Failed to decode shipment event for tracking, skipping", zap.Error(err))
+		return
+	}
+
+	c.hub.Publish(update)
+}
+
+func trackingUpdate(msg amqp.Delivery) (tracking.Update, error) {
+	if msg.RoutingKey == events.ShipmentCheckpointKey {
+		_, checkpoint, err := events.Decode[events.ShipmentCheckpoint](msg.Body)
+		if err != nil {
+			return tracking.Update{}, err
+		}
+		return tracking.Update{
+			Type:          tracking.UpdateCheckpoint,
+			ShipmentID:    checkpoint.ShipmentID,
+			FulfillmentID: checkpoint.FulfillmentID,
+			OrderID:       checkpoint.OrderID,
+			Status:        checkpoint.Status,
+			Note:          checkpoint.Note,
+			Latitude:      &checkpoint.Latitude,
+			Longitude:     &checkpoint.Longitude,
+			OccurredAt:    checkpoint.OccurredAt,
+		}, nil
+	}
+
+	_, status, err := events.Decode[events.ShipmentStatusChanged](msg.Body)
+	if err != nil {
+		return tracking.Update{}, err
+	}
+	return tracking.Update{
+		Type:          tracking.UpdateStatus,
+		ShipmentID:    status.ShipmentID,
+		FulfillmentID: status.FulfillmentID,
+		OrderID:       status.OrderID,
+		Status:        status.Status,
+		Note:          status.Note,
+		OccurredAt:    status.OccurredAt,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalEntry is one balanced movement of money between ledger accounts.
+// Reference names the event that caused it, such as capture:<payment id>, and
+// is unique so an event is only ever posted once.
+type JournalEntry struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	Kind      string    `gorm:"type:varchar(30);not null;index" json:"kind"`
+	Reference string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"reference"`
+	OrderID   string    `gorm:"type:varchar(30);index" json:"order_id,omitempty"`
+	SellerID  string    `gorm:"type:varchar(30);index" json:"seller_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Lines []JournalLine `gorm:"foreignKey:EntryID" json:"lines"`
+}
+
+type JournalLine struct {
+	ID        uuid.UUID   `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	EntryID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"-"`
+	Account   string      `gorm:"type:varchar(100);not null;index" json:"account"`
+	Direction string      `gorm:"type:varchar(6);not null" json:"direction"`
+	Amount    money.Money `gorm:"embedded" json:"amount"`
+}
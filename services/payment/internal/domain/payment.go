@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"ecommerce/pkg/money"
 	"fmt"
 	"time"
 
@@ -18,11 +19,14 @@ type Payment struct {
 	Provider         string `gorm:"type:varchar(20);default:'stripe'" json:"provider"`
 	GatewaySessionID string `gorm:"varchar(255);uniqueIndex" json:"gateway_session_id"`
 
-	Amount   int64  `gorm:"not null,min=0" json:"amount"`
-	Currency string `gorm:"varchar(10); default='inr'" json:"currency"`
+	GatewayPaymentIntentID string `gorm:"type:varchar(255);index" json:"gateway_payment_intent_id,omitempty"`
+
+	Amount money.Money `gorm:"embedded" json:"amount"`
 
 	Status string `gorm:"type:varchar(20);default:'pending'" json:"status"`
 
+	Refunds []Refund `gorm:"foreignKey:PaymentID" json:"refunds,omitempty"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+
+	"ecommerce/pkg/events"
+)
+
+const (
+	PaymentPending   = "pending"
+	PaymentSuccess   = "success"
+	PaymentFailed    = "failed"
+	PaymentExpired   = "expired"
+	PaymentCancelled = "cancelled"
+	PaymentRefunded  = "refunded"
+	PaymentDisputed  = "disputed"
+)
+
+var (
+	ErrPaymentNotFound          = errors.New("payment record not found")
+	ErrInvalidPaymentTransition = errors.New("invalid payment status transition")
+)
+
+var paymentTransitions = map[string][]string{
+	PaymentPending:  {PaymentSuccess, PaymentFailed, PaymentExpired, PaymentCancelled},
+	PaymentSuccess:  {PaymentRefunded, PaymentDisputed},
+	PaymentDisputed: {PaymentRefunded},
+}
+
+func CanTransitionPayment(from string, to string) bool {
+	for _, allowed := range paymentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EventProducer names this service on the events it publishes.
+const EventProducer = "payment"
+
+// paymentStatusEvents maps a payment status to the routing key announcing it.
+var paymentStatusEvents = map[string]string{
+	PaymentSuccess:  events.OrderPaidKey,
+	PaymentFailed:   events.PaymentFailedKey,
+	PaymentExpired:  events.PaymentExpiredKey,
+	PaymentRefunded: events.PaymentRefundedKey,
+	PaymentDisputed: events.PaymentDisputedKey,
+}
+
+// PaymentEventKey returns the routing key a status is announced under, or ""
+// when the status change is not announced to other services.
+func PaymentEventKey(status string) string {
+	return paymentStatusEvents[status]
+}
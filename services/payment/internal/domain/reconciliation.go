@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReconcileMarkedSuccess = "marked_success"
+	ReconcileMarkedFailed  = "marked_failed"
+	ReconcileMarkedExpired = "marked_expired"
+	ReconcileLookupFailed  = "lookup_failed"
+	ReconcileRepairFailed  = "repair_failed"
+)
+
+// ReconciliationReport records one pass of comparing pending payments with the
+// gateway. Only payments whose local state disagreed, or could not be checked,
+// are listed in Mismatches.
+type ReconciliationReport struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Provider   string    `gorm:"type:varchar(20);not null" json:"provider"`
+	StartedAt  time.Time `gorm:"not null;index" json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	Checked  int `gorm:"not null;default:0" json:"checked"`
+	Repaired int `gorm:"not null;default:0" json:"repaired"`
+	Failed   int `gorm:"not null;default:0" json:"failed"`
+
+	Mismatches []ReconciliationMismatch `gorm:"type:jsonb;serializer:json" json:"mismatches"`
+}
+
+type ReconciliationMismatch struct {
+	PaymentID            string `json:"payment_id"`
+	OrderID              string `json:"order_id"`
+	GatewaySessionID     string `json:"gateway_session_id"`
+	LocalStatus          string `json:"local_status"`
+	GatewayStatus        string `json:"gateway_status,omitempty"`
+	GatewayPaymentStatus string `json:"gateway_payment_status,omitempty"`
+	Action               string `json:"action"`
+	Error                string `json:"error,omitempty"`
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sixafter/nanoid"
+	"gorm.io/gorm"
+)
+
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+)
+
+var (
+	ErrRefundExceedsPayment = errors.New("refund exceeds the amount left on the payment")
+	ErrInvalidRefundAmount  = errors.New("refund amount must be positive and in the payment's currency")
+)
+
+// Refund is one payout back to the buyer against a captured payment. A payment
+// can have several; pending and succeeded refunds count against its amount, so
+// a refund is recorded before the gateway is asked to pay it out.
+type Refund struct {
+	ID        uuid.UUID `gorm:"primary_key" json:"-"`
+	PublicID  string    `gorm:"type:varchar(30);uniqueIndex" json:"id"`
+	PaymentID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	OrderID   string    `gorm:"type:varchar(30);not null;index" json:"order_id"`
+
+	Amount money.Money `gorm:"embedded" json:"amount"`
+	Reason string      `gorm:"type:text" json:"reason,omitempty"`
+
+	// IdempotencyKey identifies the request that caused the refund, so a retry
+	// finds the original instead of refunding twice.
+	IdempotencyKey  string `gorm:"type:varchar(100);not null;uniqueIndex" json:"-"`
+	GatewayRefundID string `gorm:"type:varchar(255);index" json:"gateway_refund_id,omitempty"`
+	Status          string `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	FailureReason   string `gorm:"type:text" json:"failure_reason,omitempty"`
+	// Attempt counts retries after a failure, so each retry reaches the
+	// gateway under a key of its own.
+	Attempt int `gorm:"not null;default:0" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RefundStatusFromGateway maps a provider's refund status onto ours. Anything
+// the provider has not settled yet stays pending.
+func RefundStatusFromGateway(status string) string {
+	switch status {
+	case "succeeded":
+		return RefundSucceeded
+	case "failed", "canceled":
+		return RefundFailed
+	}
+	return RefundPending
+}
+
+// Retry turns a failed refund into a fresh pending one for amount. It gets a
+// new public ID, so the gateway sees a new request and the ledger entries of
+// the failed attempt stay apart from the new one's.
+func (r *Refund) Retry(amount money.Money, reason string) error {
+	publicID, err := newRefundPublicID()
+	if err != nil {
+		return err
+	}
+
+	r.PublicID = publicID
+	r.Attempt++
+	r.Amount = amount
+	r.Reason = reason
+	r.Status = RefundPending
+	r.GatewayRefundID = ""
+	r.FailureReason = ""
+	return nil
+}
+
+// GatewayIdempotencyKey is the key the gateway deduplicates this attempt on. It
+// is derived from the caller's key rather than the refund's own IDs, so a
+// request repeated after the refund was paid out gets the same payout back
+// even if the refund had to be recorded again.
+func (r *Refund) GatewayIdempotencyKey() string {
+	return fmt.Sprintf("refund-%s-%d", r.IdempotencyKey, r.Attempt)
+}
+
+func (r *Refund) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		newID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("domain: could not generate refund ID: %w", err)
+		}
+		r.ID = newID
+	}
+	if r.PublicID == "" {
+		publicID, err := newRefundPublicID()
+		if err != nil {
+			return err
+		}
+		r.PublicID = publicID
+	}
+
+	return nil
+}
+
+func newRefundPublicID() (string, error) {
+	publicID, err := nanoid.New()
+	if err != nil {
+		return "", fmt.Errorf("domain: could not generate refund public ID: %w", err)
+	}
+	return "re_" + publicID.String(), nil
+}
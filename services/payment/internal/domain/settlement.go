@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sixafter/nanoid"
+	"gorm.io/gorm"
+)
+
+const (
+	EarningPending = "pending"
+	EarningSettled = "settled"
+)
+
+// SellerEarning is what a seller made on one delivered fulfillment. It stays
+// pending until the return window on its items closes, then it is settled
+// into the seller's payable balance and paid out with the next batch.
+type SellerEarning struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	FulfillmentID string    `gorm:"type:varchar(24);not null;uniqueIndex" json:"fulfillment_id"`
+	OrderID       string    `gorm:"type:varchar(30);not null;index" json:"order_id"`
+	SellerID      string    `gorm:"type:varchar(30);not null;index" json:"seller_id"`
+
+	// Sales is what the buyer paid for the seller's lines and Commission the
+	// platform's cut of it. Returned is what buyers were refunded for returns
+	// before the earning settled.
+	Sales      money.Money `gorm:"embedded;embeddedPrefix:sales_" json:"sales"`
+	Commission money.Money `gorm:"embedded;embeddedPrefix:commission_" json:"commission"`
+	Returned   money.Money `gorm:"embedded;embeddedPrefix:returned_" json:"returned"`
+
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	DeliveredAt time.Time  `json:"delivered_at"`
+	SettleAfter time.Time  `gorm:"not null;index" json:"settle_after"`
+	SettledAt   *time.Time `json:"settled_at,omitempty"`
+	PayoutID    string     `gorm:"type:varchar(30);index" json:"payout_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PayoutBatch groups the payouts made by one settlement run. FileName is the
+// CSV bank file it was exported to; it is empty until the export succeeds.
+type PayoutBatch struct {
+	ID         uuid.UUID  `gorm:"primary_key" json:"-"`
+	PublicID   string     `gorm:"type:varchar(30);uniqueIndex" json:"id"`
+	FileName   string     `gorm:"type:text" json:"file_name,omitempty"`
+	ExportedAt *time.Time `gorm:"index" json:"exported_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	Payouts []Payout `gorm:"foreignKey:BatchID" json:"payouts"`
+}
+
+type Payout struct {
+	ID        uuid.UUID   `gorm:"primary_key" json:"-"`
+	PublicID  string      `gorm:"type:varchar(30);uniqueIndex" json:"id"`
+	BatchID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"-"`
+	SellerID  string      `gorm:"type:varchar(30);not null;index" json:"seller_id"`
+	Amount    money.Money `gorm:"embedded" json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// SellerBalance is a seller's position in one currency.
+type SellerBalance struct {
+	Pending   money.Money `json:"pending"`
+	Available money.Money `json:"available"`
+	PaidOut   money.Money `json:"paid_out"`
+}
+
+func (b *PayoutBatch) BeforeCreate(tx *gorm.DB) error {
+	id, publicID, err := newSettlementIDs("PB-")
+	if err != nil {
+		return fmt.Errorf("domain: could not generate payout batch ID: %w", err)
+	}
+	if b.ID == uuid.Nil {
+		b.ID = id
+	}
+	if b.PublicID == "" {
+		b.PublicID = publicID
+	}
+	return nil
+}
+
+func (p *Payout) BeforeCreate(tx *gorm.DB) error {
+	id, publicID, err := newSettlementIDs("PO-")
+	if err != nil {
+		return fmt.Errorf("domain: could not generate payout ID: %w", err)
+	}
+	if p.ID == uuid.Nil {
+		p.ID = id
+	}
+	if p.PublicID == "" {
+		p.PublicID = publicID
+	}
+	return nil
+}
+
+func newSettlementIDs(prefix string) (uuid.UUID, string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	publicID, err := nanoid.NewWithLength(12)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	return id, prefix + publicID.String(), nil
+}
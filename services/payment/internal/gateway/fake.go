@@ -0,0 +1,356 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	FakeOutcomeSuccess = "success"
+	FakeOutcomeFailure = "failure"
+	FakeOutcomeExpire  = "expire"
+	// FakeOutcomeNone leaves sessions open until they are expired or settled by hand.
+	FakeOutcomeNone = "none"
+
+	FakeSignatureHeader = "Fake-Signature"
+
+	fakeSignatureTolerance = 5 * time.Minute
+)
+
+type FakeConfig struct {
+	// WebhookURL receives the signed events; leave empty to only record them.
+	WebhookURL string
+	Secret     string
+	Outcome    string
+	// Delay between creating a session and the simulated buyer finishing it.
+	Delay time.Duration
+	// BaseURL is where the fake's own HTTP handler is mounted.
+	BaseURL string
+}
+
+// FakeGateway is an in-process stand-in for a payment provider. Sessions settle
+// on their own after the configured delay and every state change is posted to
+// the webhook URL with an HMAC signature, the same way a real provider would.
+type FakeGateway struct {
+	config FakeConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	refunds  map[string]*Refund
+	// refunded totals the refunds against each payment intent.
+	refunded map[string]int64
+}
+
+func NewFakeGateway(config FakeConfig) *FakeGateway {
+	if config.Outcome == "" {
+		config.Outcome = FakeOutcomeSuccess
+	}
+	return &FakeGateway{
+		config:   config,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		sessions: make(map[string]*Session),
+		refunds:  make(map[string]*Refund),
+		refunded: make(map[string]int64),
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+func (g *FakeGateway) CreateSession(ctx context.Context, req SessionRequest) (*Session, error) {
+	id := "fake_cs_" + randomID()
+	sess := &Session{
+		ID:              id,
+		URL:             strings.TrimRight(g.config.BaseURL, "/") + "/sessions/" + id,
+		OrderID:         req.OrderID,
+		Status:          SessionOpen,
+		PaymentStatus:   PaymentUnpaid,
+		PaymentIntentID: "fake_pi_" + randomID(),
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+	}
+
+	copied := *sess
+
+	g.mu.Lock()
+	g.sessions[id] = sess
+	g.mu.Unlock()
+
+	if g.config.Outcome != FakeOutcomeNone {
+		go g.settleAfterDelay(id, g.config.Outcome)
+	}
+
+	return &copied, nil
+}
+
+func (g *FakeGateway) RetrieveSession(ctx context.Context, sessionID string) (*Session, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sess, ok := g.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("gateway: fake session %s not found", sessionID)
+	}
+	copied := *sess
+	return &copied, nil
+}
+
+func (g *FakeGateway) ExpireSession(ctx context.Context, sessionID string) error {
+	g.mu.Lock()
+	sess, ok := g.sessions[sessionID]
+	if !ok {
+		g.mu.Unlock()
+		return fmt.Errorf("gateway: fake session %s not found", sessionID)
+	}
+	if sess.Status != SessionOpen {
+		g.mu.Unlock()
+		return fmt.Errorf("gateway: fake session %s is %s and cannot be expired", sessionID, sess.Status)
+	}
+	sess.Status = SessionExpired
+	event := g.sessionEvent(EventSessionExpired, sess)
+	g.mu.Unlock()
+
+	go g.post(event)
+	return nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if req.IdempotencyKey != "" {
+		if refund, ok := g.refunds[req.IdempotencyKey]; ok {
+			copied := *refund
+			return &copied, nil
+		}
+	}
+
+	var sess *Session
+	for _, s := range g.sessions {
+		if s.PaymentIntentID == req.PaymentIntentID {
+			sess = s
+			break
+		}
+	}
+	if sess == nil || sess.PaymentStatus != PaymentPaid {
+		return nil, fmt.Errorf("gateway: fake payment %s has nothing to refund", req.PaymentIntentID)
+	}
+
+	remaining := sess.Amount - g.refunded[sess.PaymentIntentID]
+	amount := req.Amount
+	if amount <= 0 {
+		amount = remaining
+	}
+	if amount <= 0 || amount > remaining {
+		return nil, fmt.Errorf("gateway: fake refund of %d exceeds the %d left on payment %s", amount, remaining, req.PaymentIntentID)
+	}
+	g.refunded[sess.PaymentIntentID] += amount
+
+	refund := &Refund{ID: "fake_re_" + randomID(), Amount: amount, Status: "succeeded"}
+	if req.IdempotencyKey != "" {
+		g.refunds[req.IdempotencyKey] = refund
+	}
+
+	event := &WebhookEvent{
+		ID:              "fake_evt_" + randomID(),
+		Type:            EventChargeRefunded,
+		PaymentIntentID: sess.PaymentIntentID,
+		FullyRefunded:   g.refunded[sess.PaymentIntentID] == sess.Amount,
+		AmountRefunded:  g.refunded[sess.PaymentIntentID],
+	}
+	go g.post(event)
+
+	copied := *refund
+	return &copied, nil
+}
+
+func (g *FakeGateway) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	signature := header.Get(FakeSignatureHeader)
+	if signature == "" {
+		return nil, fmt.Errorf("%w: missing %s header", ErrInvalidSignature, FakeSignatureHeader)
+	}
+
+	var timestamp, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			mac = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || mac == "" {
+		return nil, fmt.Errorf("%w: malformed signature header", ErrInvalidSignature)
+	}
+	if time.Since(time.Unix(unix, 0)).Abs() > fakeSignatureTolerance {
+		return nil, fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(mac), []byte(g.sign(timestamp, payload))) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+
+	var event WebhookEvent
+	if err = json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("gateway: failed to decode fake webhook: %w", err)
+	}
+	return &event, nil
+}
+
+// Settle finishes an open session with the given outcome right away.
+func (g *FakeGateway) Settle(sessionID string, outcome string) error {
+	g.mu.Lock()
+	sess, ok := g.sessions[sessionID]
+	if !ok {
+		g.mu.Unlock()
+		return fmt.Errorf("gateway: fake session %s not found", sessionID)
+	}
+	if sess.Status != SessionOpen {
+		g.mu.Unlock()
+		return fmt.Errorf("gateway: fake session %s is already %s", sessionID, sess.Status)
+	}
+
+	var events []*WebhookEvent
+	switch outcome {
+	case FakeOutcomeSuccess:
+		sess.Status = SessionComplete
+		sess.PaymentStatus = PaymentPaid
+		events = append(events, g.sessionEvent(EventSessionCompleted, sess))
+	case FakeOutcomeFailure:
+		// Mirrors a delayed payment method: the session completes unpaid, then fails.
+		sess.Status = SessionComplete
+		events = append(events, g.sessionEvent(EventSessionCompleted, sess), g.sessionEvent(EventAsyncPaymentFailed, sess))
+	case FakeOutcomeExpire:
+		sess.Status = SessionExpired
+		events = append(events, g.sessionEvent(EventSessionExpired, sess))
+	default:
+		g.mu.Unlock()
+		return fmt.Errorf("gateway: unknown fake outcome %q", outcome)
+	}
+	g.mu.Unlock()
+
+	go func() {
+		for _, event := range events {
+			g.post(event)
+		}
+	}()
+	return nil
+}
+
+// Handler serves GET {BaseURL}/sessions/{id} to inspect a session and
+// POST {BaseURL}/sessions/{id}?outcome=success|failure|expire to settle it.
+func (g *FakeGateway) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sessionID, found := strings.Cut(r.URL.Path, "/sessions/")
+		if !found || sessionID == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			if err := g.Settle(sessionID, r.URL.Query().Get("outcome")); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+		}
+
+		sess, err := g.RetrieveSession(r.Context(), sessionID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(sess)
+	})
+}
+
+// Sign returns the signature header value for payload, for posting events by hand.
+func (g *FakeGateway) Sign(payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + g.sign(timestamp, payload)
+}
+
+func (g *FakeGateway) settleAfterDelay(sessionID string, outcome string) {
+	time.Sleep(g.config.Delay)
+
+	err := g.Settle(sessionID, outcome)
+	if err != nil {
+		logger.Info("gateway: fake session was not settled automatically", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
+
+// sessionEvent must be called with g.mu held.
+func (g *FakeGateway) sessionEvent(eventType string, sess *Session) *WebhookEvent {
+	return &WebhookEvent{
+		ID:              "fake_evt_" + randomID(),
+		Type:            eventType,
+		SessionID:       sess.ID,
+		PaymentIntentID: sess.PaymentIntentID,
+		PaymentStatus:   sess.PaymentStatus,
+	}
+}
+
+func (g *FakeGateway) post(event *WebhookEvent) {
+	if g.config.WebhookURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("gateway: failed to marshal fake webhook", zap.Error(err))
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, g.config.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		logger.Error("gateway: failed to build fake webhook request", zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakeSignatureHeader, g.Sign(payload, time.Now()))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		logger.Error("gateway: failed to deliver fake webhook", zap.String("event_type", event.Type), zap.Error(err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		logger.Error("gateway: fake webhook rejected", zap.String("event_type", event.Type), zap.Int("status_code", resp.StatusCode))
+	}
+}
+
+func (g *FakeGateway) sign(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(g.config.Secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
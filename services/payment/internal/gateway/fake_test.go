@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"ecommerce/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init("dev")
+	os.Exit(m.Run())
+}
+
+// newFakeWithReceiver returns a fake gateway whose webhooks are verified and
+// delivered to the returned channel.
+func newFakeWithReceiver(t *testing.T, outcome string) (*FakeGateway, <-chan *WebhookEvent) {
+	t.Helper()
+
+	events := make(chan *WebhookEvent, 10)
+	verifier := NewFakeGateway(FakeConfig{Secret: "test-secret"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := verifier.VerifyWebhook(payload, r.Header)
+		if err != nil {
+			t.Errorf("webhook did not verify: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events <- event
+	}))
+	t.Cleanup(server.Close)
+
+	g := NewFakeGateway(FakeConfig{
+		WebhookURL: server.URL,
+		Secret:     "test-secret",
+		Outcome:    outcome,
+		BaseURL:    "http://fake.local",
+	})
+	return g, events
+}
+
+func nextEvent(t *testing.T, events <-chan *WebhookEvent) *WebhookEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook")
+		return nil
+	}
+}
+
+func TestFakeGatewaySuccessAndRefund(t *testing.T) {
+	g, events := newFakeWithReceiver(t, FakeOutcomeSuccess)
+	ctx := context.Background()
+
+	sess, err := g.CreateSession(ctx, SessionRequest{OrderID: "ORD-1", Amount: 5000, Currency: "inr"})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	event := nextEvent(t, events)
+	if event.Type != EventSessionCompleted || event.SessionID != sess.ID || event.PaymentStatus != PaymentPaid {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	first, err := g.Refund(ctx, RefundRequest{PaymentIntentID: event.PaymentIntentID, IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	second, err := g.Refund(ctx, RefundRequest{PaymentIntentID: event.PaymentIntentID, IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("repeated Refund: %v", err)
+	}
+	if first.ID != second.ID || first.Amount != 5000 {
+		t.Fatalf("refund was not idempotent: %+v vs %+v", first, second)
+	}
+
+	event = nextEvent(t, events)
+	if event.Type != EventChargeRefunded || !event.FullyRefunded || event.AmountRefunded != 5000 {
+		t.Fatalf("unexpected refund event: %+v", event)
+	}
+}
+
+func TestFakeGatewayPartialRefunds(t *testing.T) {
+	g, events := newFakeWithReceiver(t, FakeOutcomeSuccess)
+	ctx := context.Background()
+
+	if _, err := g.CreateSession(ctx, SessionRequest{OrderID: "ORD-3", Amount: 5000, Currency: "inr"}); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	paymentIntentID := nextEvent(t, events).PaymentIntentID
+
+	if _, err := g.Refund(ctx, RefundRequest{PaymentIntentID: paymentIntentID, Amount: 2000, IdempotencyKey: "refund-a"}); err != nil {
+		t.Fatalf("first partial Refund: %v", err)
+	}
+	if event := nextEvent(t, events); event.FullyRefunded || event.AmountRefunded != 2000 {
+		t.Fatalf("unexpected first refund event: %+v", event)
+	}
+
+	if _, err := g.Refund(ctx, RefundRequest{PaymentIntentID: paymentIntentID, Amount: 3500, IdempotencyKey: "refund-b"}); err == nil {
+		t.Fatal("expected refunding more than is left to fail")
+	}
+
+	rest, err := g.Refund(ctx, RefundRequest{PaymentIntentID: paymentIntentID, IdempotencyKey: "refund-c"})
+	if err != nil {
+		t.Fatalf("Refund of the rest: %v", err)
+	}
+	if rest.Amount != 3000 {
+		t.Fatalf("refunded %d, want the remaining 3000", rest.Amount)
+	}
+	if event := nextEvent(t, events); !event.FullyRefunded || event.AmountRefunded != 5000 {
+		t.Fatalf("unexpected final refund event: %+v", event)
+	}
+}
+
+func TestFakeGatewayFailure(t *testing.T) {
+	g, events := newFakeWithReceiver(t, FakeOutcomeFailure)
+
+	if _, err := g.CreateSession(context.Background(), SessionRequest{OrderID: "ORD-2", Amount: 100, Currency: "inr"}); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	if event := nextEvent(t, events); event.Type != EventSessionCompleted || event.PaymentStatus != PaymentUnpaid {
+		t.Fatalf("unexpected first event: %+v", event)
+	}
+	if event := nextEvent(t, events); event.Type != EventAsyncPaymentFailed {
+		t.Fatalf("unexpected second event: %+v", event)
+	}
+}
+
+func TestFakeGatewayManualSettlementAndExpiry(t *testing.T) {
+	g, events := newFakeWithReceiver(t, FakeOutcomeNone)
+	ctx := context.Background()
+
+	sess, err := g.CreateSession(ctx, SessionRequest{OrderID: "ORD-3", Amount: 100, Currency: "inr"})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	if err = g.ExpireSession(ctx, sess.ID); err != nil {
+		t.Fatalf("ExpireSession: %v", err)
+	}
+	if event := nextEvent(t, events); event.Type != EventSessionExpired {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	if err = g.Settle(sess.ID, FakeOutcomeSuccess); err == nil {
+		t.Fatal("expected settling an expired session to fail")
+	}
+}
+
+func TestFakeGatewayRejectsBadSignatures(t *testing.T) {
+	g := NewFakeGateway(FakeConfig{Secret: "test-secret"})
+	payload := []byte(`{"id":"evt_1","type":"session.completed"}`)
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, g.Sign(payload, time.Now()))
+	if _, err := g.VerifyWebhook(payload, header); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+
+	tampered := []byte(`{"id":"evt_1","type":"session.expired"}`)
+	if _, err := g.VerifyWebhook(tampered, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered payload accepted: %v", err)
+	}
+
+	header.Set(FakeSignatureHeader, g.Sign(payload, time.Now().Add(-time.Hour)))
+	if _, err := g.VerifyWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("stale signature accepted: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	SessionOpen     = "open"
+	SessionComplete = "complete"
+	SessionExpired  = "expired"
+
+	PaymentPaid   = "paid"
+	PaymentUnpaid = "unpaid"
+)
+
+// Webhook event types, normalised across providers.
+const (
+	EventSessionCompleted      = "session.completed"
+	EventAsyncPaymentSucceeded = "session.async_payment_succeeded"
+	EventAsyncPaymentFailed    = "session.async_payment_failed"
+	EventSessionExpired        = "session.expired"
+	EventChargeRefunded        = "charge.refunded"
+	EventRefundUpdated         = "refund.updated"
+	EventDisputeCreated        = "charge.dispute_created"
+	EventUnhandled             = "unhandled"
+)
+
+var ErrInvalidSignature = errors.New("gateway: webhook signature verification failed")
+
+// PaymentGateway is everything the payment service needs from a payment
+// provider. Amounts are in the currency's minor unit.
+type PaymentGateway interface {
+	Name() string
+	CreateSession(ctx context.Context, req SessionRequest) (*Session, error)
+	RetrieveSession(ctx context.Context, sessionID string) (*Session, error)
+	ExpireSession(ctx context.Context, sessionID string) error
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+	VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+}
+
+type SessionRequest struct {
+	OrderID   string
+	UserID    string
+	Amount    int64
+	Currency  string
+	ExpiresAt time.Time
+}
+
+type Session struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	OrderID         string `json:"order_id"`
+	Status          string `json:"status"`
+	PaymentStatus   string `json:"payment_status"`
+	PaymentIntentID string `json:"payment_intent_id,omitempty"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+}
+
+type RefundRequest struct {
+	PaymentIntentID string
+	// Amount of zero refunds whatever is left of the payment.
+	Amount         int64
+	Reason         string
+	IdempotencyKey string
+	Metadata       map[string]string
+}
+
+type Refund struct {
+	ID     string `json:"id"`
+	Amount int64  `json:"amount"`
+	Status string `json:"status"`
+}
+
+type WebhookEvent struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ProviderType    string `json:"provider_type,omitempty"`
+	SessionID       string `json:"session_id,omitempty"`
+	PaymentIntentID string `json:"payment_intent_id,omitempty"`
+	PaymentStatus   string `json:"payment_status,omitempty"`
+	FullyRefunded   bool   `json:"fully_refunded,omitempty"`
+	AmountRefunded  int64  `json:"amount_refunded,omitempty"`
+	RefundID        string `json:"refund_id,omitempty"`
+	RefundStatus    string `json:"refund_status,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+}
@@ -0,0 +1,212 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/stripe/stripe-go/v78"
+	"github.com/stripe/stripe-go/v78/client"
+	"github.com/stripe/stripe-go/v78/webhook"
+)
+
+type StripeGateway struct {
+	api           *client.API
+	webhookSecret string
+	successURL    string
+	cancelURL     string
+}
+
+func NewStripeGateway(secretKey string, webhookSecret string, successURL string, cancelURL string) *StripeGateway {
+	return &StripeGateway{
+		api:           client.New(secretKey, nil),
+		webhookSecret: webhookSecret,
+		successURL:    successURL,
+		cancelURL:     cancelURL,
+	}
+}
+
+func (g *StripeGateway) Name() string {
+	return "stripe"
+}
+
+func (g *StripeGateway) CreateSession(ctx context.Context, req SessionRequest) (*Session, error) {
+	params := &stripe.CheckoutSessionParams{
+		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
+		Mode:               stripe.String(string(stripe.CheckoutSessionModePayment)),
+		SuccessURL:         stripe.String(g.successURL),
+		CancelURL:          stripe.String(g.cancelURL),
+		ClientReferenceID:  stripe.String(req.OrderID),
+		ExpiresAt:          stripe.Int64(req.ExpiresAt.Unix()),
+
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency: stripe.String(req.Currency),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String("Order #" + req.OrderID),
+					},
+					UnitAmount: stripe.Int64(req.Amount), // Remember: This is PAISE (300000 = ₹3000)
+				},
+				Quantity: stripe.Int64(1),
+			},
+		},
+	}
+	params.Context = ctx
+
+	sess, err := g.api.CheckoutSessions.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: stripe failed to create checkout session: %w", err)
+	}
+	return stripeSession(sess), nil
+}
+
+func (g *StripeGateway) RetrieveSession(ctx context.Context, sessionID string) (*Session, error) {
+	params := &stripe.CheckoutSessionParams{}
+	params.Context = ctx
+
+	sess, err := g.api.CheckoutSessions.Get(sessionID, params)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: stripe failed to retrieve checkout session: %w", err)
+	}
+	return stripeSession(sess), nil
+}
+
+func (g *StripeGateway) ExpireSession(ctx context.Context, sessionID string) error {
+	params := &stripe.CheckoutSessionExpireParams{}
+	params.Context = ctx
+
+	_, err := g.api.CheckoutSessions.Expire(sessionID, params)
+	if err != nil {
+		return fmt.Errorf("gateway: stripe failed to expire checkout session: %w", err)
+	}
+	return nil
+}
+
+func (g *StripeGateway) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(req.PaymentIntentID),
+		Reason:        stripe.String(string(stripe.RefundReasonRequestedByCustomer)),
+	}
+	if req.Amount > 0 {
+		params.Amount = stripe.Int64(req.Amount)
+	}
+	for key, value := range req.Metadata {
+		params.AddMetadata(key, value)
+	}
+	if req.Reason != "" {
+		params.AddMetadata("reason", req.Reason)
+	}
+	if req.IdempotencyKey != "" {
+		params.SetIdempotencyKey(req.IdempotencyKey)
+	}
+	params.Context = ctx
+
+	refund, err := g.api.Refunds.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: stripe failed to create refund: %w", err)
+	}
+	return &Refund{ID: refund.ID, Amount: refund.Amount, Status: string(refund.Status)}, nil
+}
+
+func (g *StripeGateway) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	signature := header.Get("Stripe-Signature")
+	if signature == "" {
+		return nil, fmt.Errorf("%w: missing Stripe-Signature header", ErrInvalidSignature)
+	}
+
+	event, err := webhook.ConstructEventWithOptions(payload, signature, g.webhookSecret, webhook.ConstructEventOptions{
+		IgnoreAPIVersionMismatch: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	result := &WebhookEvent{ID: event.ID, ProviderType: string(event.Type)}
+
+	switch event.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded",
+		"checkout.session.async_payment_failed", "checkout.session.expired":
+		var sess stripe.CheckoutSession
+		if err = json.Unmarshal(event.Data.Raw, &sess); err != nil {
+			return nil, fmt.Errorf("gateway: failed to decode %s: %w", event.Type, err)
+		}
+
+		s := stripeSession(&sess)
+		result.SessionID = s.ID
+		result.PaymentIntentID = s.PaymentIntentID
+		result.PaymentStatus = s.PaymentStatus
+
+		switch event.Type {
+		case "checkout.session.completed":
+			result.Type = EventSessionCompleted
+		case "checkout.session.async_payment_succeeded":
+			result.Type = EventAsyncPaymentSucceeded
+		case "checkout.session.async_payment_failed":
+			result.Type = EventAsyncPaymentFailed
+		default:
+			result.Type = EventSessionExpired
+		}
+
+	case "charge.refunded":
+		var charge stripe.Charge
+		if err = json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			return nil, fmt.Errorf("gateway: failed to decode %s: %w", event.Type, err)
+		}
+
+		result.Type = EventChargeRefunded
+		result.FullyRefunded = charge.Refunded
+		result.AmountRefunded = charge.AmountRefunded
+		if charge.PaymentIntent != nil {
+			result.PaymentIntentID = charge.PaymentIntent.ID
+		}
+
+	case "refund.updated", "charge.refund.updated":
+		var refund stripe.Refund
+		if err = json.Unmarshal(event.Data.Raw, &refund); err != nil {
+			return nil, fmt.Errorf("gateway: failed to decode %s: %w", event.Type, err)
+		}
+
+		result.Type = EventRefundUpdated
+		result.RefundID = refund.ID
+		result.RefundStatus = string(refund.Status)
+		result.Reason = string(refund.FailureReason)
+		if refund.PaymentIntent != nil {
+			result.PaymentIntentID = refund.PaymentIntent.ID
+		}
+
+	case "charge.dispute.created":
+		var dispute stripe.Dispute
+		if err = json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			return nil, fmt.Errorf("gateway: failed to decode %s: %w", event.Type, err)
+		}
+
+		result.Type = EventDisputeCreated
+		result.Reason = string(dispute.Reason)
+		if dispute.PaymentIntent != nil {
+			result.PaymentIntentID = dispute.PaymentIntent.ID
+		}
+
+	default:
+		result.Type = EventUnhandled
+	}
+
+	return result, nil
+}
+
+func stripeSession(sess *stripe.CheckoutSession) *Session {
+	s := &Session{
+		ID:            sess.ID,
+		URL:           sess.URL,
+		OrderID:       sess.ClientReferenceID,
+		Status:        string(sess.Status),
+		PaymentStatus: string(sess.PaymentStatus),
+		Amount:        sess.AmountTotal,
+		Currency:      string(sess.Currency),
+	}
+	if sess.PaymentIntent != nil {
+		s.PaymentIntentID = sess.PaymentIntent.ID
+	}
+	return s
+}
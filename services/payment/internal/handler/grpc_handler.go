@@ -2,23 +2,30 @@ package handler
 
 import (
 	"context"
+	"errors"
 
+	"ecommerce/pkg/money"
 	pb "ecommerce/pkg/protobufs/payment"
+	"ecommerce/services/payment/internal/domain"
 	"ecommerce/services/payment/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type PaymentGrpcHandler struct {
 	pb.UnimplementedPaymentServiceServer
-	paymentSvc service.PaymentService
+	paymentSvc    service.PaymentService
+	settlementSvc service.SettlementService
 }
 
-func NewPaymentGrpcHandler(paymentSvc service.PaymentService) *PaymentGrpcHandler {
-	return &PaymentGrpcHandler{paymentSvc: paymentSvc}
+func NewPaymentGrpcHandler(paymentSvc service.PaymentService, settlementSvc service.SettlementService) *PaymentGrpcHandler {
+	return &PaymentGrpcHandler{paymentSvc: paymentSvc, settlementSvc: settlementSvc}
 }
 
 func (h *PaymentGrpcHandler) CreatePaymentSession(ctx context.Context, req *pb.CreatePaymentRequest) (*pb.CreatePaymentResponse, error) {
 
-	sessionID, url, err := h.paymentSvc.CreateCheckoutSession(ctx, req.OrderId, req.UserId, req.Amount, req.Currency)
+	sessionID, url, err := h.paymentSvc.CreateCheckoutSession(ctx, req.OrderId, req.UserId, money.FromProto(req.Amount))
 	if err != nil {
 		return nil, err
 	}
@@ -28,3 +35,62 @@ func (h *PaymentGrpcHandler) CreatePaymentSession(ctx context.Context, req *pb.C
 		TransactionId: sessionID,
 	}, nil
 }
+
+func (h *PaymentGrpcHandler) CancelPaymentSession(ctx context.Context, req *pb.CancelPaymentRequest) (*pb.CancelPaymentResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	cancelled, err := h.paymentSvc.CancelCheckoutSession(ctx, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CancelPaymentResponse{Cancelled: cancelled}, nil
+}
+
+func (h *PaymentGrpcHandler) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.RefundPaymentResponse, error) {
+	if req.OrderId == "" || req.IdempotencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id and idempotency_key are required")
+	}
+
+	// An unset amount comes through as zero, which refunds whatever is left.
+	refund, err := h.paymentSvc.RefundPayment(ctx, req.OrderId, money.FromProto(req.Amount), req.Reason, req.IdempotencyKey)
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidRefundAmount):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrRefundExceedsPayment):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &pb.RefundPaymentResponse{
+		RefundId: refund.PublicID,
+		Status:   refund.Status,
+		Amount:   refund.Amount.ToProto(),
+	}, nil
+}
+
+func (h *PaymentGrpcHandler) GetSellerBalance(ctx context.Context, req *pb.GetSellerBalanceRequest) (*pb.GetSellerBalanceResponse, error) {
+	if req.SellerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "seller_id is required")
+	}
+
+	balances, err := h.settlementSvc.GetSellerBalance(ctx, req.SellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetSellerBalanceResponse{Balances: make([]*pb.SellerBalance, 0, len(balances))}
+	for _, balance := range balances {
+		res.Balances = append(res.Balances, &pb.SellerBalance{
+			Pending:   balance.Pending.ToProto(),
+			Available: balance.Available.ToProto(),
+			PaidOut:   balance.PaidOut.ToProto(),
+		})
+	}
+	return res, nil
+}
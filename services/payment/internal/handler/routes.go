@@ -1,11 +1,22 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"ecommerce/pkg/middleware"
+	"ecommerce/pkg/outbox"
 
-func RegisterRoutes(router *gin.Engine, wh *WebhookHandler) {
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.Engine, wh *WebhookHandler, outboxStore *outbox.Store) {
 	v1 := router.Group("/api/v1/payment/")
 
 	{
 		v1.POST("webhook", wh.handleWebhook)
 	}
+
+	internal := v1.Group("internal")
+	internal.Use(middleware.RequireInternalToken())
+	{
+		outbox.RegisterRoutes(internal, outboxStore)
+	}
 }
@@ -1,26 +1,28 @@
 package handler
 
 import (
+	"context"
+	"ecommerce/pkg/inbox"
 	"ecommerce/pkg/logger"
+	"ecommerce/services/payment/internal/gateway"
 	"ecommerce/services/payment/internal/service"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/stripe/stripe-go/v78"
-	"github.com/stripe/stripe-go/v78/webhook"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type WebhookHandler struct {
-	paymentSvc    service.PaymentService
-	webhookSecret string
+	paymentSvc service.PaymentService
+	gateway    gateway.PaymentGateway
+	inbox      *inbox.Inbox
 }
 
-func NewWebhookHandler(paymentSvc service.PaymentService, webhookSecret string) *WebhookHandler {
-	return &WebhookHandler{paymentSvc: paymentSvc, webhookSecret: webhookSecret}
+func NewWebhookHandler(paymentSvc service.PaymentService, paymentGateway gateway.PaymentGateway, inbox *inbox.Inbox) *WebhookHandler {
+	return &WebhookHandler{paymentSvc: paymentSvc, gateway: paymentGateway, inbox: inbox}
 }
 
 func (wh *WebhookHandler) handleWebhook(c *gin.Context) {
@@ -33,49 +35,77 @@ func (wh *WebhookHandler) handleWebhook(c *gin.Context) {
 		return
 	}
 
-	signatureHeader := c.GetHeader("Stripe-Signature")
-	if signatureHeader == "" {
-		fmt.Println("ERROR: Missing Stripe-Signature header!")
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "missing Stripe-Signature header"})
+	event, err := wh.gateway.VerifyWebhook(payload, c.Request.Header)
+	if errors.Is(err, gateway.ErrInvalidSignature) {
+		logger.Error("handler: webhook signature verification failed", zap.String("provider", wh.gateway.Name()), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "verification failed"})
+		return
+	}
+	if err != nil {
+		logger.Error("handler: failed to decode webhook", zap.String("provider", wh.gateway.Name()), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	event, err := webhook.ConstructEventWithOptions(
-		payload,
-		signatureHeader,
-		wh.webhookSecret,
-		webhook.ConstructEventOptions{
-			IgnoreAPIVersionMismatch: true,
-		},
-	)
+	// Providers retry deliveries they did not see acknowledged; the inbox makes
+	// sure each event ID changes payment state only once.
+	processed, err := wh.inbox.Process(c.Request.Context(), event.ID, func(tx *gorm.DB) error {
+		return wh.applyEvent(c.Request.Context(), wh.paymentSvc.WithTx(tx), event)
+	})
 	if err != nil {
-		fmt.Println("ERROR: Signature Verification Failed! Details:", err.Error())
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "verification failed"})
+		logger.Error("handler: failed to process webhook event", zap.String("event_id", event.ID), zap.String("event_type", event.Type), zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to process webhook event"})
 		return
 	}
+	if !processed {
+		logger.Info("handler: skipping already processed webhook event", zap.String("event_id", event.ID))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
 
-	if event.Type == "checkout.session.completed" {
-		var session stripe.CheckoutSession
-		err = json.Unmarshal(event.Data.Raw, &session)
+func (wh *WebhookHandler) applyEvent(ctx context.Context, paymentSvc service.PaymentService, event *gateway.WebhookEvent) error {
+	switch event.Type {
+	case gateway.EventSessionCompleted, gateway.EventAsyncPaymentSucceeded:
+		// Delayed payment methods complete the session before the money arrives;
+		// those are settled by the async payment succeeded / failed events.
+		if event.PaymentStatus != gateway.PaymentPaid {
+			logger.Info("handler: checkout completed with payment still processing", zap.String("session_id", event.SessionID))
+			return nil
+		}
+
+		return paymentSvc.MarkPaymentAsSuccess(ctx, event.SessionID, event.PaymentIntentID)
+
+	case gateway.EventAsyncPaymentFailed:
+		return paymentSvc.MarkPaymentAsFailed(ctx, event.SessionID)
 
-		if err != nil {
-			logger.Error("handler: failed to unmarshal checkout session", zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-			return
+	case gateway.EventSessionExpired:
+		return paymentSvc.MarkPaymentAsExpired(ctx, event.SessionID)
+
+	case gateway.EventChargeRefunded:
+		if !event.FullyRefunded || event.PaymentIntentID == "" {
+			logger.Info("handler: ignoring partial refund", zap.String("payment_intent_id", event.PaymentIntentID), zap.Int64("amount_refunded", event.AmountRefunded))
+			return nil
 		}
-		err = wh.paymentSvc.MarkPaymentAsSuccess(c.Request.Context(), session.ID)
 
-		if err != nil {
-			logger.Error("handler: failed to mark payment as success ", zap.Error(err))
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to mark payment as success"})
-			return
+		return paymentSvc.MarkPaymentAsRefunded(ctx, event.PaymentIntentID)
+
+	case gateway.EventRefundUpdated:
+		if event.RefundID == "" {
+			return nil
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "Payment successful! Your order is confirmed.",
-			"session_id": session.ID,
-		})
+		return paymentSvc.UpdateRefundStatus(ctx, event.RefundID, event.RefundStatus, event.Reason)
+
+	case gateway.EventDisputeCreated:
+		if event.PaymentIntentID == "" {
+			logger.Error("handler: dispute without payment intent", zap.String("event_id", event.ID))
+			return nil
+		}
+
+		return paymentSvc.MarkPaymentAsDisputed(ctx, event.PaymentIntentID, event.Reason)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	logger.Info("handler: ignoring unhandled webhook event", zap.String("event_type", event.ProviderType))
+	return nil
 }
@@ -0,0 +1,184 @@
+// Package ledger builds the double-entry journal entries that track money
+// moving between buyers, the platform and sellers.
+//
+// An account's balance is its credits minus its debits: what the account
+// holder has, or is owed. A buyer's payment debits the buyer and credits
+// escrow; settling a delivery moves it out of escrow to the seller, less the
+// platform's commission; a payout clears what the seller is owed.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+
+	"ecommerce/pkg/money"
+)
+
+const (
+	Debit  = "debit"
+	Credit = "credit"
+)
+
+const (
+	// AccountBuyerFunds is money buyers have paid in, net of refunds.
+	AccountBuyerFunds = "buyer_funds"
+	// AccountEscrow holds captured payments until they are settled to sellers.
+	AccountEscrow = "platform_escrow"
+	// AccountCommission is the platform's revenue.
+	AccountCommission = "platform_commission"
+	// AccountPayouts is money sent to sellers' bank accounts.
+	AccountPayouts = "seller_payouts"
+
+	sellerPayablePrefix = "seller_payable:"
+)
+
+const (
+	KindCapture       = "capture"
+	KindRefund        = "refund"
+	KindRefundFailed  = "refund_failed"
+	KindSellerPayable = "seller_payable"
+	KindCommission    = "commission"
+	KindClawback      = "clawback"
+	KindPayout        = "payout"
+)
+
+var ErrUnbalancedEntry = errors.New("ledger: journal entry does not balance")
+
+// SellerPayable is the account holding what the platform owes a seller.
+func SellerPayable(sellerID string) string {
+	return sellerPayablePrefix + sellerID
+}
+
+type Posting struct {
+	Account   string
+	Direction string
+	Amount    money.Money
+}
+
+// Entry is one balanced journal entry. Reference identifies the business
+// event behind it, so posting the same event twice is a no-op.
+type Entry struct {
+	Kind      string
+	Reference string
+	OrderID   string
+	SellerID  string
+	Postings  []Posting
+}
+
+// Validate checks that the entry has a reference, that every posting is a
+// positive amount in one currency and that debits equal credits.
+func (e Entry) Validate() error {
+	if e.Reference == "" {
+		return fmt.Errorf("ledger: %s entry has no reference", e.Kind)
+	}
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("%w: %s needs at least two postings", ErrUnbalancedEntry, e.Reference)
+	}
+
+	currency := e.Postings[0].Amount.Currency
+	var debits, credits int64
+	for _, posting := range e.Postings {
+		if posting.Account == "" || !posting.Amount.IsPositive() {
+			return fmt.Errorf("ledger: %s has an empty posting", e.Reference)
+		}
+		if !posting.Amount.SameCurrency(money.Zero(currency)) {
+			return fmt.Errorf("%w: %s mixes %s and %s", ErrUnbalancedEntry, e.Reference, currency, posting.Amount.Currency)
+		}
+
+		switch posting.Direction {
+		case Debit:
+			debits += posting.Amount.Amount
+		case Credit:
+			credits += posting.Amount.Amount
+		default:
+			return fmt.Errorf("ledger: %s has a posting with direction %q", e.Reference, posting.Direction)
+		}
+	}
+
+	if debits != credits {
+		return fmt.Errorf("%w: %s debits %d, credits %d", ErrUnbalancedEntry, e.Reference, debits, credits)
+	}
+	return nil
+}
+
+// Capture records a buyer's payment landing in escrow.
+func Capture(paymentID string, orderID string, amount money.Money) Entry {
+	return transfer(KindCapture, "capture:"+paymentID, orderID, "", AccountBuyerFunds, AccountEscrow, amount)
+}
+
+// Refund records money going back to a buyer out of escrow.
+func Refund(refundID string, orderID string, amount money.Money) Entry {
+	return transfer(KindRefund, "refund:"+refundID, orderID, "", AccountEscrow, AccountBuyerFunds, amount)
+}
+
+// RefundFailed puts a refund the gateway could not pay out back into escrow.
+func RefundFailed(refundID string, orderID string, amount money.Money) Entry {
+	return transfer(KindRefundFailed, "refund_failed:"+refundID, orderID, "", AccountBuyerFunds, AccountEscrow, amount)
+}
+
+// Settle moves a delivered fulfillment's sales out of escrow to its seller and
+// then takes the platform's commission out of the seller's share. It returns
+// one entry for each step; a zero commission produces only the first.
+func Settle(fulfillmentID string, orderID string, sellerID string, sales money.Money, commission money.Money) []Entry {
+	entries := []Entry{
+		transfer(KindSellerPayable, "payable:"+fulfillmentID, orderID, sellerID, AccountEscrow, SellerPayable(sellerID), sales),
+	}
+	if commission.IsPositive() {
+		entries = append(entries, transfer(KindCommission, "commission:"+fulfillmentID, orderID, sellerID, SellerPayable(sellerID), AccountCommission, commission))
+	}
+	return entries
+}
+
+// Clawback takes a refunded return back into escrow after its sales were
+// already settled: the seller gives up their share and the platform its
+// commission. A seller left owing the platform has it netted off their next
+// payout.
+func Clawback(returnID string, orderID string, sellerID string, amount money.Money, commission money.Money) Entry {
+	entry := Entry{Kind: KindClawback, Reference: "clawback:" + returnID, OrderID: orderID, SellerID: sellerID}
+
+	share, _ := amount.Sub(commission)
+	if share.IsPositive() {
+		entry.Postings = append(entry.Postings, Posting{Account: SellerPayable(sellerID), Direction: Debit, Amount: share})
+	}
+	if commission.IsPositive() {
+		entry.Postings = append(entry.Postings, Posting{Account: AccountCommission, Direction: Debit, Amount: commission})
+	}
+	entry.Postings = append(entry.Postings, Posting{Account: AccountEscrow, Direction: Credit, Amount: amount})
+	return entry
+}
+
+// Payout records a seller's payable balance being sent to their bank.
+func Payout(payoutID string, sellerID string, amount money.Money) Entry {
+	return transfer(KindPayout, "payout:"+payoutID, "", sellerID, SellerPayable(sellerID), AccountPayouts, amount)
+}
+
+// CommissionShare is the part of commission charged on sales that belongs to
+// portion of them, rounded to the nearest minor unit.
+func CommissionShare(commission money.Money, sales money.Money, portion money.Money) money.Money {
+	if !sales.IsPositive() {
+		return money.Zero(commission.Currency)
+	}
+	if cmp, err := portion.Cmp(sales); err == nil && cmp >= 0 {
+		return commission
+	}
+
+	numerator := commission.Amount * portion.Amount
+	share := numerator / sales.Amount
+	if 2*(numerator%sales.Amount) >= sales.Amount {
+		share++
+	}
+	return money.New(share, commission.Currency)
+}
+
+func transfer(kind string, reference string, orderID string, sellerID string, from string, to string, amount money.Money) Entry {
+	return Entry{
+		Kind:      kind,
+		Reference: reference,
+		OrderID:   orderID,
+		SellerID:  sellerID,
+		Postings: []Posting{
+			{Account: from, Direction: Debit, Amount: amount},
+			{Account: to, Direction: Credit, Amount: amount},
+		},
+	}
+}
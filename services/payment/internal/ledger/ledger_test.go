@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"ecommerce/pkg/money"
+)
+
+func TestEntriesBalance(t *testing.T) {
+	sales := money.New(150000, "INR")
+	commission := money.New(15000, "INR")
+
+	entries := []Entry{
+		Capture("pay_1", "ORD-1", money.New(180000, "INR")),
+		Refund("re_1", "ORD-1", money.New(30000, "INR")),
+		RefundFailed("re_1", "ORD-1", money.New(30000, "INR")),
+		Clawback("RET-1", "ORD-1", "sel_1", money.New(50000, "INR"), money.New(5000, "INR")),
+		Payout("PO-1", "sel_1", money.New(135000, "INR")),
+	}
+	entries = append(entries, Settle("FUL-1", "ORD-1", "sel_1", sales, commission)...)
+
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			t.Errorf("%s: %v", entry.Reference, err)
+		}
+	}
+}
+
+func TestSettleMovesSalesLessCommission(t *testing.T) {
+	entries := Settle("FUL-1", "ORD-1", "sel_1", money.New(100000, "INR"), money.New(12000, "INR"))
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want payable and commission", len(entries))
+	}
+
+	balances := map[string]int64{}
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if posting.Direction == Credit {
+				balances[posting.Account] += posting.Amount.Amount
+			} else {
+				balances[posting.Account] -= posting.Amount.Amount
+			}
+		}
+	}
+
+	if got := balances[SellerPayable("sel_1")]; got != 88000 {
+		t.Errorf("seller payable = %d, want 88000", got)
+	}
+	if got := balances[AccountCommission]; got != 12000 {
+		t.Errorf("commission = %d, want 12000", got)
+	}
+	if got := balances[AccountEscrow]; got != -100000 {
+		t.Errorf("escrow = %d, want -100000", got)
+	}
+
+	if entries := Settle("FUL-2", "ORD-2", "sel_1", money.New(5000, "INR"), money.Zero("INR")); len(entries) != 1 {
+		t.Errorf("zero commission produced %d entries, want 1", len(entries))
+	}
+}
+
+func TestValidateRejectsBadEntries(t *testing.T) {
+	unbalanced := Entry{Kind: KindCapture, Reference: "capture:x", Postings: []Posting{
+		{Account: AccountBuyerFunds, Direction: Debit, Amount: money.New(100, "INR")},
+		{Account: AccountEscrow, Direction: Credit, Amount: money.New(90, "INR")},
+	}}
+	if err := unbalanced.Validate(); !errors.Is(err, ErrUnbalancedEntry) {
+		t.Errorf("unbalanced entry: got %v", err)
+	}
+
+	mixed := Entry{Kind: KindCapture, Reference: "capture:y", Postings: []Posting{
+		{Account: AccountBuyerFunds, Direction: Debit, Amount: money.New(100, "INR")},
+		{Account: AccountEscrow, Direction: Credit, Amount: money.New(100, "USD")},
+	}}
+	if err := mixed.Validate(); !errors.Is(err, ErrUnbalancedEntry) {
+		t.Errorf("mixed currencies: got %v", err)
+	}
+
+	if err := Capture("pay_2", "ORD-2", money.Zero("INR")).Validate(); err == nil {
+		t.Error("expected a zero amount to be rejected")
+	}
+}
+
+func TestCommissionShare(t *testing.T) {
+	commission := money.New(1000, "INR")
+	sales := money.New(10000, "INR")
+
+	tests := []struct {
+		portion int64
+		want    int64
+	}{
+		{10000, 1000},
+		{12000, 1000},
+		{3333, 333},
+		{3335, 334},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := CommissionShare(commission, sales, money.New(tt.portion, "INR")); got.Amount != tt.want {
+			t.Errorf("share of %d = %d, want %d", tt.portion, got.Amount, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"ecommerce/pkg/money"
+)
+
+var payoutFileHeader = []string{"batch_id", "payout_id", "seller_id", "amount", "currency", "narration"}
+
+type PayoutLine struct {
+	PayoutID string
+	SellerID string
+	Amount   money.Money
+}
+
+// WritePayoutFile writes a payout batch as a CSV bank file, one transfer per
+// line. Amounts are in major units as banks expect, e.g. 1499.50; the payout
+// ID doubles as the transfer reference so bank statements can be matched back.
+func WritePayoutFile(w io.Writer, batchID string, lines []PayoutLine) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(payoutFileHeader); err != nil {
+		return fmt.Errorf("ledger: failed to write payout file header: %w", err)
+	}
+	for _, line := range lines {
+		record := []string{
+			batchID,
+			line.PayoutID,
+			line.SellerID,
+			line.Amount.Decimal(),
+			line.Amount.Currency,
+			"Seller payout " + line.PayoutID,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("ledger: failed to write payout %s: %w", line.PayoutID, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("ledger: failed to write payout file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package ledger
+
+import (
+	"strings"
+	"testing"
+
+	"ecommerce/pkg/money"
+)
+
+func TestWritePayoutFile(t *testing.T) {
+	var out strings.Builder
+	err := WritePayoutFile(&out, "PB-1", []PayoutLine{
+		{PayoutID: "PO-1", SellerID: "sel_1", Amount: money.New(149950, "INR")},
+		{PayoutID: "PO-2", SellerID: "sel_2", Amount: money.New(500, "JPY")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "batch_id,payout_id,seller_id,amount,currency,narration\n" +
+		"PB-1,PO-1,sel_1,1499.50,INR,Seller payout PO-1\n" +
+		"PB-1,PO-2,sel_2,500,JPY,Seller payout PO-2\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
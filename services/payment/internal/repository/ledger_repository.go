@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/ledger"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository interface {
+	Post(ctx context.Context, entries ...ledger.Entry) error
+	AccountBalances(ctx context.Context, account string) ([]money.Money, error)
+	WithTx(tx *gorm.DB) LedgerRepository
+}
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) WithTx(tx *gorm.DB) LedgerRepository {
+	return &ledgerRepository{db: tx}
+}
+
+// Post writes the entries in one transaction. Entries whose reference is
+// already in the journal are skipped, so redelivered events post once.
+func (r *ledgerRepository) Post(ctx context.Context, entries ...ledger.Entry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return postEntries(tx, entries)
+	})
+	if err != nil {
+		return fmt.Errorf("repository: could not post journal entries: %w", err)
+	}
+	return nil
+}
+
+// AccountBalances returns the account's credits minus its debits, one amount
+// per currency it has seen.
+func (r *ledgerRepository) AccountBalances(ctx context.Context, account string) ([]money.Money, error) {
+	balances, err := accountBalances(r.db.WithContext(ctx), account)
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not get account balance: %w", err)
+	}
+	return balances, nil
+}
+
+func postEntries(tx *gorm.DB, entries []ledger.Entry) error {
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			return err
+		}
+
+		journalEntry := domain.JournalEntry{
+			Kind:      entry.Kind,
+			Reference: entry.Reference,
+			OrderID:   entry.OrderID,
+			SellerID:  entry.SellerID,
+		}
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "reference"}}, DoNothing: true}).
+			Omit("Lines").
+			Create(&journalEntry)
+		if result.Error != nil {
+			return fmt.Errorf("could not save journal entry %s: %w", entry.Reference, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		lines := make([]domain.JournalLine, 0, len(entry.Postings))
+		for _, posting := range entry.Postings {
+			lines = append(lines, domain.JournalLine{
+				EntryID:   journalEntry.ID,
+				Account:   posting.Account,
+				Direction: posting.Direction,
+				Amount:    posting.Amount,
+			})
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return fmt.Errorf("could not save journal lines for %s: %w", entry.Reference, err)
+		}
+	}
+	return nil
+}
+
+func accountBalances(tx *gorm.DB, account string) ([]money.Money, error) {
+	var rows []struct {
+		Currency string
+		Balance  int64
+	}
+	err := tx.Model(&domain.JournalLine{}).
+		Select("currency, SUM(CASE WHEN direction = ? THEN amount_minor ELSE -amount_minor END) AS balance", ledger.Credit).
+		Where("account = ?", account).
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]money.Money, 0, len(rows))
+	for _, row := range rows {
+		balances = append(balances, money.New(row.Balance, row.Currency))
+	}
+	return balances, nil
+}
@@ -2,16 +2,27 @@ package repository
 
 import (
 	"context"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/outbox"
 	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/ledger"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PaymentRepository interface {
 	CreatePayment(ctx context.Context, payment *domain.Payment) error
-	UpdatePaymentStatusBySessionID(ctx context.Context, sessionID string, status string) error
+	UpdatePaymentStatusBySessionID(ctx context.Context, sessionID string, status string, reason string) error
+	UpdatePaymentStatusByPaymentIntentID(ctx context.Context, paymentIntentID string, status string, reason string) error
+	SetPaymentIntentID(ctx context.Context, sessionID string, paymentIntentID string) error
+	GetPaymentByOrderID(ctx context.Context, orderID string, status string) (*domain.Payment, error)
+	GetPaymentBySessionID(ctx context.Context, sessionID string) (*domain.Payment, error)
+	ListPendingPayments(ctx context.Context, createdBefore time.Time, limit int) ([]domain.Payment, error)
+	WithTx(tx *gorm.DB) PaymentRepository
 }
 
 type paymentRepository struct {
@@ -22,6 +33,10 @@ func NewPaymentRepository(db *gorm.DB) PaymentRepository {
 	return &paymentRepository{db: db}
 }
 
+func (r *paymentRepository) WithTx(tx *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: tx}
+}
+
 func (r *paymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) error {
 	err := gorm.G[domain.Payment](r.db).Create(ctx, payment)
 	if err != nil {
@@ -30,44 +45,134 @@ func (r *paymentRepository) CreatePayment(ctx context.Context, payment *domain.P
 	return nil
 }
 
-func (r *paymentRepository) UpdatePaymentStatusBySessionID(ctx context.Context, sessionID string, status string) error {
+func (r *paymentRepository) GetPaymentByOrderID(ctx context.Context, orderID string, status string) (*domain.Payment, error) {
+	payment, err := gorm.G[domain.Payment](r.db).
+		Where("order_id = ? AND status = ?", orderID, status).
+		Order("created_at desc").
+		First(ctx)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: could not get payment by order id: %w", err)
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetPaymentBySessionID(ctx context.Context, sessionID string) (*domain.Payment, error) {
+	payment, err := gorm.G[domain.Payment](r.db).
+		Where("gateway_session_id = ?", sessionID).
+		First(ctx)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("repository: %w: %s", domain.ErrPaymentNotFound, sessionID)
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: could not get payment by session id: %w", err)
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) ListPendingPayments(ctx context.Context, createdBefore time.Time, limit int) ([]domain.Payment, error) {
+	payments, err := gorm.G[domain.Payment](r.db).
+		Where("status = ? AND created_at < ?", domain.PaymentPending, createdBefore).
+		Order("created_at asc").
+		Limit(limit).
+		Find(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not list pending payments: %w", err)
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) UpdatePaymentStatusBySessionID(ctx context.Context, sessionID string, status string, reason string) error {
+	return r.updatePaymentStatus(ctx, "gateway_session_id = ?", sessionID, status, reason)
+}
+
+func (r *paymentRepository) UpdatePaymentStatusByPaymentIntentID(ctx context.Context, paymentIntentID string, status string, reason string) error {
+	return r.updatePaymentStatus(ctx, "gateway_payment_intent_id = ?", paymentIntentID, status, reason)
+}
+
+func (r *paymentRepository) SetPaymentIntentID(ctx context.Context, sessionID string, paymentIntentID string) error {
+	_, err := gorm.G[domain.Payment](r.db).
+		Where("gateway_session_id = ?", sessionID).
+		Update(ctx, "gateway_payment_intent_id", paymentIntentID)
+	if err != nil {
+		return fmt.Errorf("repository: could not save payment intent id: %w", err)
+	}
+	return nil
+}
+
+// updatePaymentStatus changes the status and, in the same transaction, posts a
+// capture or a refund made outside this service to the ledger and writes the
+// outbox event for statuses other services care about. Setting the status a payment already has is a no-op so
+// webhook retries do not emit twice.
+func (r *paymentRepository) updatePaymentStatus(ctx context.Context, query string, value string, status string, reason string) error {
 
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		//Save to Payment Service Database
 		var payment domain.Payment
-		innerErr := tx.Where("gateway_session_id = ?", sessionID).First(&payment).Error
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(query, value).First(&payment).Error
 		if innerErr != nil {
 			if errors.Is(innerErr, gorm.ErrRecordNotFound) {
-				return errors.New("payment record not found for the given session ID")
+				return fmt.Errorf("%w: %s", domain.ErrPaymentNotFound, value)
 			}
 			return fmt.Errorf("could not find payment: %w", innerErr)
 		}
 
+		if payment.Status == status {
+			return nil
+		}
+		if !domain.CanTransitionPayment(payment.Status, status) {
+			return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidPaymentTransition, payment.Status, status)
+		}
+
 		payment.Status = status
 		innerErr = tx.Save(&payment).Error
 		if innerErr != nil {
 			return fmt.Errorf("could not update payment status: %w", innerErr)
 		}
 
-		//Save to Outbox Database for Message Broker
-		if status == "success" {
-			payload := fmt.Sprintf(`{"order_id": "%s", "status": "paid"}`, payment.OrderID)
-
-			outboxEvent := &domain.OutboxEvent{
-				EventType: "OrderPaid",
-				Payload:   payload,
-				Processed: false,
-			}
-
-			innerErr = tx.Create(outboxEvent).Error
+		switch status {
+		case domain.PaymentSuccess:
+			innerErr = postEntries(tx, []ledger.Entry{ledger.Capture(payment.PublicID, payment.OrderID, payment.Amount)})
 			if innerErr != nil {
-				return fmt.Errorf("failed to save event to outbox: %w", innerErr)
+				return fmt.Errorf("could not record capture in ledger: %w", innerErr)
+			}
+		case domain.PaymentRefunded:
+			// Refunds requested through RefundPayment are already recorded,
+			// so this only picks up what was refunded at the gateway directly.
+			if innerErr = recordOutsideRefund(tx, &payment, reason); innerErr != nil {
+				return innerErr
 			}
 		}
 
-		return nil
+		//Save to Outbox Database for Message Broker
+		eventKey := domain.PaymentEventKey(status)
+		if eventKey == "" {
+			return nil
+		}
+
+		payloadStatus := status
+		if status == domain.PaymentSuccess {
+			payloadStatus = "paid"
+		}
+
+		event, innerErr := events.New(ctx, domain.EventProducer, eventKey, events.PaymentStatusChanged{
+			OrderID: payment.OrderID,
+			UserID:  payment.UserID,
+			Status:  payloadStatus,
+			Reason:  reason,
+		})
+		if innerErr != nil {
+			return innerErr
+		}
+		return outbox.Enqueue(tx, events.PaymentEventsExchange, event)
 	})
 
-	return err
+	if err != nil {
+		return fmt.Errorf("repository: could not update payment status: %w", err)
+	}
+	return nil
 }
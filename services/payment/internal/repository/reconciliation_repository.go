@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/services/payment/internal/domain"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type ReconciliationRepository interface {
+	SaveReport(ctx context.Context, report *domain.ReconciliationReport) error
+}
+
+type reconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) ReconciliationRepository {
+	return &reconciliationRepository{db: db}
+}
+
+func (r *reconciliationRepository) SaveReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	err := gorm.G[domain.ReconciliationReport](r.db).Create(ctx, report)
+	if err != nil {
+		return fmt.Errorf("repository: could not save reconciliation report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/ledger"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefundRepository interface {
+	ReserveRefund(ctx context.Context, orderID string, amount money.Money, reason string, idempotencyKey string) (*domain.Refund, *domain.Payment, error)
+	CompleteRefund(ctx context.Context, refundID string, gatewayRefundID string, status string, failureReason string) error
+	UpdateRefundStatus(ctx context.Context, gatewayRefundID string, status string, failureReason string) (*domain.Refund, error)
+	WithTx(tx *gorm.DB) RefundRepository
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) WithTx(tx *gorm.DB) RefundRepository {
+	return &refundRepository{db: tx}
+}
+
+// ReserveRefund records a pending refund against the order's captured payment.
+// The payment row is locked while the earlier refunds are summed, so two
+// requests racing for the same payment cannot refund more than was captured.
+// A zero amount reserves whatever is left. A key that was already used returns
+// the refund it created, unless that refund failed, in which case it is tried
+// again.
+func (r *refundRepository) ReserveRefund(ctx context.Context, orderID string, amount money.Money, reason string, idempotencyKey string) (*domain.Refund, *domain.Payment, error) {
+	var refund domain.Refund
+	var payment domain.Payment
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status = ?", orderID, domain.PaymentSuccess).
+			Order("created_at desc").
+			Take(&payment).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: no captured payment for order %s", domain.ErrPaymentNotFound, orderID)
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock payment: %w", innerErr)
+		}
+
+		innerErr = tx.Where("idempotency_key = ?", idempotencyKey).Take(&refund).Error
+		if innerErr == nil && refund.Status != domain.RefundFailed {
+			return nil
+		} else if innerErr != nil && !errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("could not look up refund: %w", innerErr)
+		}
+
+		remaining, innerErr := unrefunded(tx, &payment)
+		if innerErr != nil {
+			return innerErr
+		}
+		if amount.IsZero() {
+			amount = remaining
+		}
+		cmp, innerErr := amount.Cmp(remaining)
+		if innerErr != nil {
+			return fmt.Errorf("%w: %s against a %s payment", domain.ErrInvalidRefundAmount, amount, payment.Amount)
+		}
+		if !amount.IsPositive() || cmp > 0 {
+			return fmt.Errorf("%w: %s requested, %s left", domain.ErrRefundExceedsPayment, amount, remaining)
+		}
+
+		if refund.ID != uuid.Nil {
+			if innerErr = refund.Retry(amount, reason); innerErr != nil {
+				return innerErr
+			}
+			return tx.Save(&refund).Error
+		}
+
+		refund = domain.Refund{
+			PaymentID:      payment.ID,
+			OrderID:        orderID,
+			Amount:         amount,
+			Reason:         reason,
+			IdempotencyKey: idempotencyKey,
+			Status:         domain.RefundPending,
+		}
+		return tx.Create(&refund).Error
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository: could not reserve refund: %w", err)
+	}
+	return &refund, &payment, nil
+}
+
+// unrefunded is what is left of payment once its pending and succeeded refunds
+// are taken off.
+func unrefunded(tx *gorm.DB, payment *domain.Payment) (money.Money, error) {
+	var refunded int64
+	err := tx.Model(&domain.Refund{}).
+		Select("COALESCE(SUM(amount_minor), 0)").
+		Where("payment_id = ? AND status IN ?", payment.ID, []string{domain.RefundPending, domain.RefundSucceeded}).
+		Scan(&refunded).Error
+	if err != nil {
+		return money.Money{}, fmt.Errorf("could not sum earlier refunds: %w", err)
+	}
+	return money.New(payment.Amount.Amount-refunded, payment.Amount.Currency), nil
+}
+
+// recordOutsideRefund records whatever the gateway refunded on payment without
+// a refund of ours behind it, such as a refund made from the provider's
+// dashboard, and takes it out of escrow. payment must be locked in tx.
+func recordOutsideRefund(tx *gorm.DB, payment *domain.Payment, reason string) error {
+	remaining, err := unrefunded(tx, payment)
+	if err != nil {
+		return err
+	}
+	if !remaining.IsPositive() {
+		return nil
+	}
+
+	refund := domain.Refund{
+		PaymentID:      payment.ID,
+		OrderID:        payment.OrderID,
+		Amount:         remaining,
+		Reason:         reason,
+		IdempotencyKey: "gateway-refunded-" + payment.PublicID,
+		Status:         domain.RefundSucceeded,
+	}
+	if err = tx.Create(&refund).Error; err != nil {
+		return fmt.Errorf("could not record refund: %w", err)
+	}
+
+	err = postEntries(tx, []ledger.Entry{ledger.Refund(refund.PublicID, refund.OrderID, refund.Amount)})
+	if err != nil {
+		return fmt.Errorf("could not record refund in ledger: %w", err)
+	}
+	return nil
+}
+
+func (r *refundRepository) CompleteRefund(ctx context.Context, refundID string, gatewayRefundID string, status string, failureReason string) error {
+	_, err := gorm.G[domain.Refund](r.db).
+		Where("public_id = ?", refundID).
+		Updates(ctx, domain.Refund{GatewayRefundID: gatewayRefundID, Status: status, FailureReason: failureReason})
+	if err != nil {
+		return fmt.Errorf("repository: could not complete refund: %w", err)
+	}
+	return nil
+}
+
+// UpdateRefundStatus applies a status the gateway reported for one of our
+// refunds after it was created. Refunds are posted to the ledger as soon as
+// the gateway accepts them, so one that fails is put back into escrow in the
+// same transaction. Failed is final. It returns nil, nil for a refund that
+// was not made through this service.
+func (r *refundRepository) UpdateRefundStatus(ctx context.Context, gatewayRefundID string, status string, failureReason string) (*domain.Refund, error) {
+	var refund domain.Refund
+	found := true
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("gateway_refund_id = ?", gatewayRefundID).
+			Take(&refund).Error
+		if errors.Is(innerErr, gorm.ErrRecordNotFound) {
+			found = false
+			return nil
+		} else if innerErr != nil {
+			return fmt.Errorf("could not lock refund: %w", innerErr)
+		}
+
+		if refund.Status == status || refund.Status == domain.RefundFailed {
+			return nil
+		}
+
+		refund.Status = status
+		refund.FailureReason = failureReason
+		if innerErr = tx.Save(&refund).Error; innerErr != nil {
+			return fmt.Errorf("could not update refund status: %w", innerErr)
+		}
+
+		if status != domain.RefundFailed {
+			return nil
+		}
+		innerErr = postEntries(tx, []ledger.Entry{ledger.RefundFailed(refund.PublicID, refund.OrderID, refund.Amount)})
+		if innerErr != nil {
+			return fmt.Errorf("could not record failed refund in ledger: %w", innerErr)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not update refund status: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &refund, nil
+}
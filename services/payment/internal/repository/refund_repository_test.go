@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"ecommerce/pkg/database"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// The cap on refunds rests on the payment row lock, so it is tested against a
+// real Postgres. Point PAYMENT_TEST_DATABASE_URL at a scratch database to run it.
+func TestReserveRefundCapsConcurrentRequests(t *testing.T) {
+	dsn := os.Getenv("PAYMENT_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("PAYMENT_TEST_DATABASE_URL is not set")
+	}
+
+	pg, err := database.NewPostgres(dsn)
+	if err != nil {
+		t.Fatalf("could not connect to database: %v", err)
+	}
+	t.Cleanup(func() { _ = pg.Close() })
+
+	if err = pg.DB.AutoMigrate(&domain.Payment{}, &domain.Refund{}); err != nil {
+		t.Fatalf("could not migrate: %v", err)
+	}
+
+	suffix := uuid.NewString()[:8]
+	payment := domain.Payment{
+		OrderID:          "ORD-" + suffix,
+		UserID:           "usr_" + suffix,
+		GatewaySessionID: "cs_test_" + suffix,
+		Amount:           money.New(10000, "INR"),
+		Status:           domain.PaymentSuccess,
+	}
+	if err = pg.DB.Create(&payment).Error; err != nil {
+		t.Fatalf("could not create payment: %v", err)
+	}
+	t.Cleanup(func() {
+		pg.DB.Where("payment_id = ?", payment.ID).Delete(&domain.Refund{})
+		pg.DB.Unscoped().Delete(&payment)
+	})
+
+	repo := NewRefundRepository(pg.DB)
+	ctx := context.Background()
+
+	const requests = 10
+	var wg sync.WaitGroup
+	errs := make([]error, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "test-" + suffix + "-" + uuid.NewString()
+			_, _, errs[i] = repo.ReserveRefund(ctx, payment.OrderID, money.New(3000, "INR"), "test", key)
+		}()
+	}
+	wg.Wait()
+
+	var reserved, refused int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			reserved++
+		case errors.Is(err, domain.ErrRefundExceedsPayment):
+			refused++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if reserved != 3 || refused != requests-3 {
+		t.Fatalf("got %d reserved and %d refused, want 3 and %d", reserved, refused, requests-3)
+	}
+
+	refund, _, err := repo.ReserveRefund(ctx, payment.OrderID, money.Zero("INR"), "test", "test-"+suffix+"-rest")
+	if err != nil {
+		t.Fatalf("could not reserve the rest: %v", err)
+	}
+	if refund.Amount.Amount != 1000 {
+		t.Errorf("a zero amount reserved %d, want the 1000 left", refund.Amount.Amount)
+	}
+
+	_, _, err = repo.ReserveRefund(ctx, payment.OrderID, money.New(1, "INR"), "test", "test-"+suffix+"-over")
+	if !errors.Is(err, domain.ErrRefundExceedsPayment) {
+		t.Errorf("refunding past the payment: got %v, want ErrRefundExceedsPayment", err)
+	}
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/ledger"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettlementRepository interface {
+	RecordEarning(ctx context.Context, earning *domain.SellerEarning) error
+	ReturnEarning(ctx context.Context, fulfillmentID string, amount money.Money) (*domain.SellerEarning, error)
+	Settle(ctx context.Context, now time.Time, limit int) (*domain.PayoutBatch, error)
+	ListUnexportedBatches(ctx context.Context) ([]domain.PayoutBatch, error)
+	MarkBatchExported(ctx context.Context, batchID string, fileName string) error
+	ListPendingEarnings(ctx context.Context, sellerID string) ([]domain.SellerEarning, error)
+	SumPayouts(ctx context.Context, sellerID string) ([]money.Money, error)
+	WithTx(tx *gorm.DB) SettlementRepository
+}
+
+type settlementRepository struct {
+	db *gorm.DB
+}
+
+func NewSettlementRepository(db *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+func (r *settlementRepository) WithTx(tx *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: tx}
+}
+
+// RecordEarning saves a delivered fulfillment's earning. A fulfillment that
+// already has one keeps it.
+func (r *settlementRepository) RecordEarning(ctx context.Context, earning *domain.SellerEarning) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "fulfillment_id"}}, DoNothing: true}).
+		Create(earning).Error
+	if err != nil {
+		return fmt.Errorf("repository: could not record seller earning: %w", err)
+	}
+	return nil
+}
+
+// ReturnEarning notes a refund for returned items against the fulfillment's
+// earning. A pending earning has the amount taken off what will be settled;
+// a settled one is returned unchanged for the caller to claw back. It returns
+// nil if the fulfillment has no earning.
+func (r *settlementRepository) ReturnEarning(ctx context.Context, fulfillmentID string, amount money.Money) (*domain.SellerEarning, error) {
+	var earning domain.SellerEarning
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("fulfillment_id = ?", fulfillmentID).
+			Take(&earning).Error
+		if innerErr != nil {
+			return innerErr
+		}
+		if earning.Status != domain.EarningPending {
+			return nil
+		}
+
+		returned, innerErr := earning.Returned.Add(amount)
+		if innerErr != nil {
+			return innerErr
+		}
+		if cmp, _ := returned.Cmp(earning.Sales); cmp > 0 {
+			returned = earning.Sales
+		}
+		earning.Returned = returned
+		return tx.Model(&domain.SellerEarning{}).
+			Where("id = ?", earning.ID).
+			Update("returned_amount_minor", returned.Amount).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("repository: could not apply return to seller earning: %w", err)
+	}
+	return &earning, nil
+}
+
+// Settle moves earnings whose return window has closed into their sellers'
+// payable balances and pays each of those sellers everything they are owed.
+// Earnings are locked with SKIP LOCKED so overlapping runs split the work
+// instead of settling twice. It returns nil when nobody is due a payout.
+func (r *settlementRepository) Settle(ctx context.Context, now time.Time, limit int) (*domain.PayoutBatch, error) {
+	var batch *domain.PayoutBatch
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var earnings []domain.SellerEarning
+		innerErr := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND settle_after <= ?", domain.EarningPending, now).
+			Order("settle_after asc").
+			Limit(limit).
+			Find(&earnings).Error
+		if innerErr != nil {
+			return fmt.Errorf("could not lock due earnings: %w", innerErr)
+		}
+		if len(earnings) == 0 {
+			return nil
+		}
+
+		var sellers []string
+		seen := make(map[string]bool)
+		for _, earning := range earnings {
+			sales, innerErr := earning.Sales.Sub(earning.Returned)
+			if innerErr != nil {
+				return innerErr
+			}
+			if sales.IsPositive() {
+				commission := ledger.CommissionShare(earning.Commission, earning.Sales, sales)
+				innerErr = postEntries(tx, ledger.Settle(earning.FulfillmentID, earning.OrderID, earning.SellerID, sales, commission))
+				if innerErr != nil {
+					return innerErr
+				}
+			}
+
+			if !seen[earning.SellerID] {
+				seen[earning.SellerID] = true
+				sellers = append(sellers, earning.SellerID)
+			}
+		}
+
+		candidate := &domain.PayoutBatch{}
+		payoutBySeller := make(map[string]string)
+		for _, sellerID := range sellers {
+			balances, innerErr := accountBalances(tx, ledger.SellerPayable(sellerID))
+			if innerErr != nil {
+				return fmt.Errorf("could not get seller balance: %w", innerErr)
+			}
+			for _, balance := range balances {
+				if !balance.IsPositive() {
+					continue
+				}
+				candidate.Payouts = append(candidate.Payouts, domain.Payout{SellerID: sellerID, Amount: balance})
+			}
+		}
+
+		if len(candidate.Payouts) > 0 {
+			innerErr = tx.Create(candidate).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not save payout batch: %w", innerErr)
+			}
+			for _, payout := range candidate.Payouts {
+				innerErr = postEntries(tx, []ledger.Entry{ledger.Payout(payout.PublicID, payout.SellerID, payout.Amount)})
+				if innerErr != nil {
+					return innerErr
+				}
+				payoutBySeller[payout.SellerID] = payout.PublicID
+			}
+			batch = candidate
+		}
+
+		settledAt := time.Now()
+		for _, earning := range earnings {
+			innerErr = tx.Model(&domain.SellerEarning{}).
+				Where("id = ?", earning.ID).
+				Updates(map[string]any{
+					"status":     domain.EarningSettled,
+					"settled_at": settledAt,
+					"payout_id":  payoutBySeller[earning.SellerID],
+				}).Error
+			if innerErr != nil {
+				return fmt.Errorf("could not mark earning settled: %w", innerErr)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not settle seller earnings: %w", err)
+	}
+	return batch, nil
+}
+
+func (r *settlementRepository) ListUnexportedBatches(ctx context.Context) ([]domain.PayoutBatch, error) {
+	batches, err := gorm.G[domain.PayoutBatch](r.db).
+		Preload("Payouts", nil).
+		Where("exported_at IS NULL").
+		Order("created_at asc").
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not list unexported payout batches: %w", err)
+	}
+	return batches, nil
+}
+
+func (r *settlementRepository) MarkBatchExported(ctx context.Context, batchID string, fileName string) error {
+	exportedAt := time.Now()
+	_, err := gorm.G[domain.PayoutBatch](r.db).
+		Where("public_id = ?", batchID).
+		Updates(ctx, domain.PayoutBatch{FileName: fileName, ExportedAt: &exportedAt})
+	if err != nil {
+		return fmt.Errorf("repository: could not mark payout batch exported: %w", err)
+	}
+	return nil
+}
+
+func (r *settlementRepository) ListPendingEarnings(ctx context.Context, sellerID string) ([]domain.SellerEarning, error) {
+	earnings, err := gorm.G[domain.SellerEarning](r.db).
+		Where("seller_id = ? AND status = ?", sellerID, domain.EarningPending).
+		Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not list pending earnings: %w", err)
+	}
+	return earnings, nil
+}
+
+func (r *settlementRepository) SumPayouts(ctx context.Context, sellerID string) ([]money.Money, error) {
+	var rows []struct {
+		Currency string
+		Total    int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Payout{}).
+		Select("currency, SUM(amount_minor) AS total").
+		Where("seller_id = ?", sellerID).
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("repository: could not sum payouts: %w", err)
+	}
+
+	totals := make([]money.Money, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, money.New(row.Total, row.Currency))
+	}
+	return totals, nil
+}
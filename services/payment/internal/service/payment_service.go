@@ -2,61 +2,296 @@ package service
 
 import (
 	"context"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
 	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/gateway"
+	"ecommerce/services/payment/internal/ledger"
 	"ecommerce/services/payment/internal/repository"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/stripe/stripe-go/v78"
-	"github.com/stripe/stripe-go/v78/checkout/session"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type PaymentService interface {
-	CreateCheckoutSession(ctx context.Context, orderID string, userID string, amount int64, currency string) (string, string, error)
-	MarkPaymentAsSuccess(ctx context.Context, sessionID string) error
+	CreateCheckoutSession(ctx context.Context, orderID string, userID string, amount money.Money) (string, string, error)
+	MarkPaymentAsSuccess(ctx context.Context, sessionID string, paymentIntentID string) error
+	MarkPaymentAsFailed(ctx context.Context, sessionID string) error
+	MarkPaymentAsExpired(ctx context.Context, sessionID string) error
+	MarkPaymentAsRefunded(ctx context.Context, paymentIntentID string) error
+	MarkPaymentAsDisputed(ctx context.Context, paymentIntentID string, reason string) error
+	CancelCheckoutSession(ctx context.Context, orderID string) (bool, error)
+	RefundOrderPayment(ctx context.Context, orderID string, reason string) error
+	RefundPayment(ctx context.Context, orderID string, amount money.Money, reason string, idempotencyKey string) (*domain.Refund, error)
+	UpdateRefundStatus(ctx context.Context, gatewayRefundID string, gatewayStatus string, failureReason string) error
+	WithTx(tx *gorm.DB) PaymentService
 }
 
 type paymentService struct {
 	paymentRepository repository.PaymentRepository
+	refundRepository  repository.RefundRepository
+	ledgerRepository  repository.LedgerRepository
+	gateway           gateway.PaymentGateway
 }
 
-func (s *paymentService) MarkPaymentAsSuccess(ctx context.Context, sessionID string) error {
-	err := s.paymentRepository.UpdatePaymentStatusBySessionID(ctx, sessionID, "success")
+func (s *paymentService) MarkPaymentAsSuccess(ctx context.Context, sessionID string, paymentIntentID string) error {
+	if paymentIntentID != "" {
+		err := s.paymentRepository.SetPaymentIntentID(ctx, sessionID, paymentIntentID)
+		if err != nil {
+			return fmt.Errorf("service: failed to record payment intent: %w", err)
+		}
+	}
+
+	return s.updateStatus(ctx, s.paymentRepository.UpdatePaymentStatusBySessionID, sessionID, domain.PaymentSuccess, "")
+}
+
+func (s *paymentService) MarkPaymentAsFailed(ctx context.Context, sessionID string) error {
+	return s.updateStatus(ctx, s.paymentRepository.UpdatePaymentStatusBySessionID, sessionID, domain.PaymentFailed, "async payment failed")
+}
+
+func (s *paymentService) MarkPaymentAsExpired(ctx context.Context, sessionID string) error {
+	return s.updateStatus(ctx, s.paymentRepository.UpdatePaymentStatusBySessionID, sessionID, domain.PaymentExpired, "checkout session expired")
+}
+
+func (s *paymentService) MarkPaymentAsRefunded(ctx context.Context, paymentIntentID string) error {
+	return s.updateStatus(ctx, s.paymentRepository.UpdatePaymentStatusByPaymentIntentID, paymentIntentID, domain.PaymentRefunded, "charge refunded")
+}
+
+func (s *paymentService) MarkPaymentAsDisputed(ctx context.Context, paymentIntentID string, reason string) error {
+	return s.updateStatus(ctx, s.paymentRepository.UpdatePaymentStatusByPaymentIntentID, paymentIntentID, domain.PaymentDisputed, reason)
+}
+
+// updateStatus treats transitions the payment has already moved past as
+// handled: Stripe delivers webhooks out of order and, for sessions we expire
+// ourselves, after the payment has been cancelled.
+func (s *paymentService) updateStatus(
+	ctx context.Context,
+	update func(ctx context.Context, key string, status string, reason string) error,
+	key string,
+	status string,
+	reason string,
+) error {
+	err := update(ctx, key, status, reason)
+	if errors.Is(err, domain.ErrInvalidPaymentTransition) {
+		logger.Info("service: ignoring stale payment status update", zap.String("key", key), zap.String("status", status), zap.Error(err))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to mark payment as %s: %w", status, err)
+	}
+	return nil
+}
+
+func (s *paymentService) CancelCheckoutSession(ctx context.Context, orderID string) (bool, error) {
+	payment, err := s.paymentRepository.GetPaymentByOrderID(ctx, orderID, domain.PaymentPending)
+	if err != nil {
+		return false, fmt.Errorf("service: failed to look up payment: %w", err)
+	}
+	if payment == nil {
+		return false, nil
+	}
+
+	err = s.gateway.ExpireSession(ctx, payment.GatewaySessionID)
+	if err != nil {
+		return false, fmt.Errorf("service: failed to expire checkout session: %w", err)
+	}
+
+	err = s.paymentRepository.UpdatePaymentStatusBySessionID(ctx, payment.GatewaySessionID, domain.PaymentCancelled, "")
+	if err != nil {
+		return false, fmt.Errorf("service: failed to mark payment as cancelled: %w", err)
+	}
+
+	return true, nil
+}
+
+// RefundOrderPayment refunds whatever is left of a cancelled order's captured
+// payment and marks the payment refunded. An order that was never paid only has
+// its open checkout session closed.
+func (s *paymentService) RefundOrderPayment(ctx context.Context, orderID string, reason string) error {
+	payment, err := s.paymentRepository.GetPaymentByOrderID(ctx, orderID, domain.PaymentSuccess)
+	if err != nil {
+		return fmt.Errorf("service: failed to look up payment: %w", err)
+	}
+
+	if payment == nil {
+		_, err = s.CancelCheckoutSession(ctx, orderID)
+		return err
+	}
+
+	// Keyed on the payment so redelivered cancellation events do not refund twice.
+	_, err = s.RefundPayment(ctx, orderID, money.Zero(payment.Amount.Currency), reason, payment.PublicID)
+	if err != nil && !errors.Is(err, domain.ErrRefundExceedsPayment) {
+		return err
+	}
+
+	err = s.paymentRepository.UpdatePaymentStatusBySessionID(ctx, payment.GatewaySessionID, domain.PaymentRefunded, reason)
+	if err != nil {
+		return fmt.Errorf("service: failed to mark payment as refunded: %w", err)
+	}
+
+	return nil
+}
+
+// RefundPayment pays part of an order's captured payment back, such as the
+// lines a seller rejected; a zero amount refunds whatever is left. The refund
+// is reserved in the ledger before the gateway is called, so concurrent
+// requests cannot add up to more than was captured. Repeating a request with
+// the same idempotency key returns the original refund.
+func (s *paymentService) RefundPayment(ctx context.Context, orderID string, amount money.Money, reason string, idempotencyKey string) (*domain.Refund, error) {
+	if amount.IsNegative() {
+		return nil, fmt.Errorf("service: %w: %s", domain.ErrInvalidRefundAmount, amount)
+	}
+	if idempotencyKey == "" {
+		return nil, fmt.Errorf("service: refunds need an idempotency key")
+	}
+
+	refund, payment, err := s.refundRepository.ReserveRefund(ctx, orderID, amount, reason, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to reserve refund: %w", err)
+	}
+	if refund.Status == domain.RefundSucceeded || refund.GatewayRefundID != "" {
+		if err = s.recordRefund(ctx, refund); err != nil {
+			return nil, err
+		}
+		return refund, nil
+	}
+
+	gatewayRefund, err := s.requestGatewayRefund(ctx, payment, refund)
+	if err != nil {
+		completeErr := s.refundRepository.CompleteRefund(ctx, refund.PublicID, "", domain.RefundFailed, err.Error())
+		if completeErr != nil {
+			logger.Error("service: failed to mark refund as failed", zap.String("refund_id", refund.PublicID), zap.Error(completeErr))
+		}
+		return nil, err
+	}
+
+	refund.GatewayRefundID = gatewayRefund.ID
+	refund.Status = domain.RefundStatusFromGateway(gatewayRefund.Status)
+	err = s.refundRepository.CompleteRefund(ctx, refund.PublicID, refund.GatewayRefundID, refund.Status, "")
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to record refund result: %w", err)
+	}
+
+	if err = s.recordRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// recordRefund posts a refund the gateway has accepted to the ledger. It is
+// safe to repeat, which lets retried requests fill in an entry that was missed.
+func (s *paymentService) recordRefund(ctx context.Context, refund *domain.Refund) error {
+	if refund.Status == domain.RefundFailed {
+		return nil
+	}
+
+	err := s.ledgerRepository.Post(ctx, ledger.Refund(refund.PublicID, refund.OrderID, refund.Amount))
+	if err != nil {
+		return fmt.Errorf("service: failed to record refund in ledger: %w", err)
+	}
+	return nil
+}
+
+// UpdateRefundStatus records what became of a refund the gateway accepted but
+// had not finished, so a failed one stops counting against the payment.
+func (s *paymentService) UpdateRefundStatus(ctx context.Context, gatewayRefundID string, gatewayStatus string, failureReason string) error {
+	refund, err := s.refundRepository.UpdateRefundStatus(ctx, gatewayRefundID, domain.RefundStatusFromGateway(gatewayStatus), failureReason)
 	if err != nil {
-		return fmt.Errorf("service: failed to mark payment as success: %w", err)
+		return fmt.Errorf("service: failed to update refund status: %w", err)
+	}
+	if refund == nil {
+		logger.Info("service: ignoring update for a refund made outside this service", zap.String("gateway_refund_id", gatewayRefundID))
+		return nil
 	}
 
+	if refund.Status == domain.RefundFailed {
+		logger.Error("service: CRITICAL - refund failed at the gateway, the buyer has not been paid back",
+			zap.String("refund_id", refund.PublicID),
+			zap.String("order_id", refund.OrderID),
+			zap.String("failure_reason", failureReason),
+		)
+	}
 	return nil
 }
 
-func NewPaymentService(repo repository.PaymentRepository, paymentGatewaySecretKey string) PaymentService {
-	stripe.Key = paymentGatewaySecretKey
-	return &paymentService{paymentRepository: repo}
+func (s *paymentService) requestGatewayRefund(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (*gateway.Refund, error) {
+	paymentIntentID, err := s.paymentIntentID(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayRefund, err := s.gateway.Refund(ctx, gateway.RefundRequest{
+		PaymentIntentID: paymentIntentID,
+		Amount:          refund.Amount.Amount,
+		Reason:          refund.Reason,
+		Metadata:        map[string]string{"order_id": refund.OrderID, "refund_id": refund.PublicID},
+		IdempotencyKey:  refund.GatewayIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to refund payment: %w", err)
+	}
+	return gatewayRefund, nil
 }
 
-func (s *paymentService) CreateCheckoutSession(ctx context.Context, orderID string, userID string, amount int64, currency string) (string, string, error) {
+func (s *paymentService) paymentIntentID(ctx context.Context, payment *domain.Payment) (string, error) {
+	if payment.GatewayPaymentIntentID != "" {
+		return payment.GatewayPaymentIntentID, nil
+	}
 
-	params := &stripe.CheckoutSessionParams{
-		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
-		Mode:               stripe.String(string(stripe.CheckoutSessionModePayment)),
-		SuccessURL:         stripe.String("http://localhost:8085/success?session_id={CHECKOUT_SESSION_ID}"),
-		CancelURL:          stripe.String("http://localhost:8085/cancel"),
-		ClientReferenceID:  stripe.String(orderID),
+	sess, err := s.gateway.RetrieveSession(ctx, payment.GatewaySessionID)
+	if err != nil {
+		return "", fmt.Errorf("service: failed to retrieve checkout session: %w", err)
+	}
+	if sess.PaymentIntentID == "" {
+		return "", fmt.Errorf("service: checkout session %s has no payment intent", payment.GatewaySessionID)
+	}
+	return sess.PaymentIntentID, nil
+}
+
+// WithTx returns a copy of the service whose payment and ledger writes join tx.
+// Refunds do not: each is committed before the gateway is called and again
+// with its result, so rolling tx back cannot lose a refund the gateway has
+// already paid out.
+func (s *paymentService) WithTx(tx *gorm.DB) PaymentService {
+	return &paymentService{
+		paymentRepository: s.paymentRepository.WithTx(tx),
+		refundRepository:  s.refundRepository,
+		ledgerRepository:  s.ledgerRepository.WithTx(tx),
+		gateway:           s.gateway,
+	}
+}
+
+func NewPaymentService(
+	repo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	ledgerRepo repository.LedgerRepository,
+	paymentGateway gateway.PaymentGateway,
+) PaymentService {
+	return &paymentService{
+		paymentRepository: repo,
+		refundRepository:  refundRepo,
+		ledgerRepository:  ledgerRepo,
+		gateway:           paymentGateway,
+	}
+}
 
-		LineItems: []*stripe.CheckoutSessionLineItemParams{
-			{
-				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency: stripe.String(currency),
-					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
-						Name: stripe.String("Order #" + orderID),
-					},
-					UnitAmount: stripe.Int64(amount), // Remember: This is PAISE (300000 = ₹3000)
-				},
-				Quantity: stripe.Int64(1),
-			},
-		},
+func (s *paymentService) CreateCheckoutSession(ctx context.Context, orderID string, userID string, amount money.Money) (string, string, error) {
+	if !amount.IsPositive() {
+		return "", "", fmt.Errorf("service: payment amount must be positive, got %s", amount)
 	}
 
-	sess, err := session.New(params)
+	sess, err := s.gateway.CreateSession(ctx, gateway.SessionRequest{
+		OrderID:  orderID,
+		UserID:   userID,
+		Amount:   amount.Amount,
+		Currency: strings.ToLower(amount.Currency),
+		// Stripe's minimum; the order service holds stock slightly longer than this.
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+	})
 	if err != nil {
 		return "", "", err
 	}
@@ -64,10 +299,10 @@ func (s *paymentService) CreateCheckoutSession(ctx context.Context, orderID stri
 	paymentRecord := &domain.Payment{
 		OrderID:          orderID,
 		UserID:           userID,
+		Provider:         s.gateway.Name(),
 		GatewaySessionID: sess.ID,
 		Amount:           amount,
-		Currency:         currency,
-		Status:           "pending",
+		Status:           domain.PaymentPending,
 	}
 
 	err = s.paymentRepository.CreatePayment(ctx, paymentRecord)
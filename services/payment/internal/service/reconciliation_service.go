@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/logger"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/gateway"
+	"ecommerce/services/payment/internal/repository"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const reconciliationBatchSize = 200
+
+type ReconciliationService interface {
+	ReconcilePendingPayments(ctx context.Context, minAge time.Duration) (*domain.ReconciliationReport, error)
+}
+
+type reconciliationService struct {
+	paymentRepo        repository.PaymentRepository
+	reconciliationRepo repository.ReconciliationRepository
+	paymentSvc         PaymentService
+	gateway            gateway.PaymentGateway
+}
+
+func NewReconciliationService(
+	paymentRepo repository.PaymentRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	paymentSvc PaymentService,
+	paymentGateway gateway.PaymentGateway,
+) ReconciliationService {
+	return &reconciliationService{
+		paymentRepo:        paymentRepo,
+		reconciliationRepo: reconciliationRepo,
+		paymentSvc:         paymentSvc,
+		gateway:            paymentGateway,
+	}
+}
+
+// ReconcilePendingPayments asks the gateway about every payment that has been
+// pending for at least minAge and repairs the ones whose webhook never arrived.
+// Repairs go through the regular status update path, so the order service gets
+// the same outbox events it would have received from the webhook.
+func (s *reconciliationService) ReconcilePendingPayments(ctx context.Context, minAge time.Duration) (*domain.ReconciliationReport, error) {
+	report := &domain.ReconciliationReport{
+		Provider:   s.gateway.Name(),
+		StartedAt:  time.Now(),
+		Mismatches: []domain.ReconciliationMismatch{},
+	}
+
+	payments, err := s.paymentRepo.ListPendingPayments(ctx, report.StartedAt.Add(-minAge), reconciliationBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to list pending payments: %w", err)
+	}
+
+	for _, payment := range payments {
+		// Payments created by another provider cannot be looked up here.
+		if payment.Provider != "" && payment.Provider != s.gateway.Name() {
+			continue
+		}
+		report.Checked++
+
+		mismatch, ok := s.reconcile(ctx, payment)
+		if !ok {
+			continue
+		}
+
+		if mismatch.Error != "" {
+			report.Failed++
+		} else {
+			report.Repaired++
+		}
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+
+	report.FinishedAt = time.Now()
+
+	err = s.reconciliationRepo.SaveReport(ctx, report)
+	if err != nil {
+		return report, fmt.Errorf("service: failed to save reconciliation report: %w", err)
+	}
+
+	return report, nil
+}
+
+// reconcile reports false when the payment is rightly still pending.
+func (s *reconciliationService) reconcile(ctx context.Context, payment domain.Payment) (domain.ReconciliationMismatch, bool) {
+	mismatch := domain.ReconciliationMismatch{
+		PaymentID:        payment.PublicID,
+		OrderID:          payment.OrderID,
+		GatewaySessionID: payment.GatewaySessionID,
+		LocalStatus:      payment.Status,
+	}
+
+	sess, err := s.gateway.RetrieveSession(ctx, payment.GatewaySessionID)
+	if err != nil {
+		mismatch.Action = domain.ReconcileLookupFailed
+		mismatch.Error = err.Error()
+		logger.Error("service: reconciliation could not look up session", zap.String("session_id", payment.GatewaySessionID), zap.Error(err))
+		return mismatch, true
+	}
+
+	mismatch.GatewayStatus = sess.Status
+	mismatch.GatewayPaymentStatus = sess.PaymentStatus
+
+	switch {
+	case sess.Status == gateway.SessionComplete && sess.PaymentStatus == gateway.PaymentPaid:
+		mismatch.Action = domain.ReconcileMarkedSuccess
+		err = s.paymentSvc.MarkPaymentAsSuccess(ctx, payment.GatewaySessionID, sess.PaymentIntentID)
+	case sess.Status == gateway.SessionExpired:
+		mismatch.Action = domain.ReconcileMarkedExpired
+		err = s.paymentSvc.MarkPaymentAsExpired(ctx, payment.GatewaySessionID)
+	default:
+		// Still open, or completed with a delayed payment method that has not settled.
+		return mismatch, false
+	}
+
+	if err != nil {
+		mismatch.Action = domain.ReconcileRepairFailed
+		mismatch.Error = err.Error()
+		logger.Error("service: reconciliation could not repair payment", zap.String("payment_id", payment.PublicID), zap.Error(err))
+	}
+
+	return mismatch, true
+}
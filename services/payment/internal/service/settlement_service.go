@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/ledger"
+	"ecommerce/services/payment/internal/repository"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const settlementBatchSize = 500
+
+// Delivery is a seller's fulfillment reaching the buyer, as reported by the
+// order service.
+type Delivery struct {
+	FulfillmentID string
+	OrderID       string
+	SellerID      string
+	Sales         money.Money
+	// Commission is the platform's cut as snapshotted at checkout.
+	Commission  money.Money
+	DeliveredAt time.Time
+	// SettleAfter is when the last return window on the fulfillment closes.
+	SettleAfter time.Time
+}
+
+type SettlementService interface {
+	RecordDelivery(ctx context.Context, delivery Delivery) error
+	ApplyReturnRefund(ctx context.Context, fulfillmentID string, returnID string, refund *domain.Refund) error
+	RunSettlement(ctx context.Context, now time.Time) (*domain.PayoutBatch, error)
+	GetSellerBalance(ctx context.Context, sellerID string) ([]domain.SellerBalance, error)
+	WithTx(tx *gorm.DB) SettlementService
+}
+
+type settlementService struct {
+	settlementRepo repository.SettlementRepository
+	ledgerRepo     repository.LedgerRepository
+	exportDir      string
+}
+
+// NewSettlementService writes payout bank files to exportDir.
+func NewSettlementService(
+	settlementRepo repository.SettlementRepository,
+	ledgerRepo repository.LedgerRepository,
+	exportDir string,
+) (SettlementService, error) {
+	if err := os.MkdirAll(exportDir, 0o750); err != nil {
+		return nil, fmt.Errorf("service: failed to create payout export directory: %w", err)
+	}
+
+	return &settlementService{
+		settlementRepo: settlementRepo,
+		ledgerRepo:     ledgerRepo,
+		exportDir:      exportDir,
+	}, nil
+}
+
+// WithTx returns a copy of the service whose database writes join tx.
+func (s *settlementService) WithTx(tx *gorm.DB) SettlementService {
+	clone := *s
+	clone.settlementRepo = s.settlementRepo.WithTx(tx)
+	clone.ledgerRepo = s.ledgerRepo.WithTx(tx)
+	return &clone
+}
+
+func (s *settlementService) RecordDelivery(ctx context.Context, delivery Delivery) error {
+	if !delivery.Sales.IsPositive() {
+		return nil
+	}
+
+	settleAfter := delivery.SettleAfter
+	if settleAfter.Before(delivery.DeliveredAt) {
+		settleAfter = delivery.DeliveredAt
+	}
+
+	commission := delivery.Commission
+	if cmp, err := commission.Cmp(delivery.Sales); err != nil || cmp > 0 || commission.IsNegative() {
+		return fmt.Errorf("service: commission %s does not fit sales of %s", commission, delivery.Sales)
+	}
+
+	err := s.settlementRepo.RecordEarning(ctx, &domain.SellerEarning{
+		FulfillmentID: delivery.FulfillmentID,
+		OrderID:       delivery.OrderID,
+		SellerID:      delivery.SellerID,
+		Sales:         delivery.Sales,
+		Commission:    commission,
+		Returned:      money.Zero(delivery.Sales.Currency),
+		Status:        domain.EarningPending,
+		DeliveredAt:   delivery.DeliveredAt,
+		SettleAfter:   settleAfter,
+	})
+	if err != nil {
+		return fmt.Errorf("service: failed to record delivery: %w", err)
+	}
+	return nil
+}
+
+// ApplyReturnRefund charges a buyer's refund for returned items to the seller.
+// Before settlement it shrinks what will be settled; afterwards the seller's
+// share and the commission on it are clawed back from the payable balance.
+func (s *settlementService) ApplyReturnRefund(ctx context.Context, fulfillmentID string, returnID string, refund *domain.Refund) error {
+	earning, err := s.settlementRepo.ReturnEarning(ctx, fulfillmentID, refund.Amount)
+	if err != nil {
+		return fmt.Errorf("service: failed to apply return to earnings: %w", err)
+	}
+	if earning == nil {
+		logger.Info("service: no seller earning to charge return against", zap.String("fulfillment_id", fulfillmentID), zap.String("return_id", returnID))
+		return nil
+	}
+	if earning.Status != domain.EarningSettled {
+		return nil
+	}
+
+	commission := ledger.CommissionShare(earning.Commission, earning.Sales, refund.Amount)
+	err = s.ledgerRepo.Post(ctx, ledger.Clawback(returnID, earning.OrderID, earning.SellerID, refund.Amount, commission))
+	if err != nil {
+		return fmt.Errorf("service: failed to claw back settled return: %w", err)
+	}
+	return nil
+}
+
+// RunSettlement settles every earning whose return window has closed into a
+// payout batch and exports it, along with any batch an earlier run failed to
+// export, as a CSV bank file.
+func (s *settlementService) RunSettlement(ctx context.Context, now time.Time) (*domain.PayoutBatch, error) {
+	batch, err := s.settlementRepo.Settle(ctx, now, settlementBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to settle earnings: %w", err)
+	}
+
+	batches, err := s.settlementRepo.ListUnexportedBatches(ctx)
+	if err != nil {
+		return batch, fmt.Errorf("service: failed to list payout batches to export: %w", err)
+	}
+	for _, pending := range batches {
+		if err = s.exportBatch(ctx, &pending); err != nil {
+			return batch, err
+		}
+	}
+
+	return batch, nil
+}
+
+func (s *settlementService) exportBatch(ctx context.Context, batch *domain.PayoutBatch) error {
+	lines := make([]ledger.PayoutLine, 0, len(batch.Payouts))
+	for _, payout := range batch.Payouts {
+		lines = append(lines, ledger.PayoutLine{PayoutID: payout.PublicID, SellerID: payout.SellerID, Amount: payout.Amount})
+	}
+
+	fileName := filepath.Join(s.exportDir, batch.PublicID+".csv")
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("service: failed to create payout file: %w", err)
+	}
+
+	err = ledger.WritePayoutFile(file, batch.PublicID, lines)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to export payout batch %s: %w", batch.PublicID, err)
+	}
+
+	err = s.settlementRepo.MarkBatchExported(ctx, batch.PublicID, fileName)
+	if err != nil {
+		return fmt.Errorf("service: failed to mark payout batch exported: %w", err)
+	}
+
+	logger.Info("service: exported payout batch", zap.String("batch_id", batch.PublicID), zap.Int("payouts", len(lines)), zap.String("file", fileName))
+	return nil
+}
+
+// GetSellerBalance reports, per currency, what the seller has pending in open
+// return windows, what has settled and awaits payout, and what has been paid.
+func (s *settlementService) GetSellerBalance(ctx context.Context, sellerID string) ([]domain.SellerBalance, error) {
+	var currencies []string
+	byCurrency := make(map[string]*domain.SellerBalance)
+	balanceFor := func(currency string) *domain.SellerBalance {
+		if balance, ok := byCurrency[currency]; ok {
+			return balance
+		}
+		balance := &domain.SellerBalance{
+			Pending:   money.Zero(currency),
+			Available: money.Zero(currency),
+			PaidOut:   money.Zero(currency),
+		}
+		byCurrency[currency] = balance
+		currencies = append(currencies, currency)
+		return balance
+	}
+
+	earnings, err := s.settlementRepo.ListPendingEarnings(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get pending earnings: %w", err)
+	}
+	for _, earning := range earnings {
+		sales, innerErr := earning.Sales.Sub(earning.Returned)
+		if innerErr != nil {
+			return nil, fmt.Errorf("service: earning %s is inconsistent: %w", earning.FulfillmentID, innerErr)
+		}
+		commission := ledger.CommissionShare(earning.Commission, earning.Sales, sales)
+
+		balance := balanceFor(sales.Currency)
+		balance.Pending.Amount += sales.Amount - commission.Amount
+	}
+
+	available, err := s.ledgerRepo.AccountBalances(ctx, ledger.SellerPayable(sellerID))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get payable balance: %w", err)
+	}
+	for _, amount := range available {
+		balanceFor(amount.Currency).Available = amount
+	}
+
+	paidOut, err := s.settlementRepo.SumPayouts(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get payouts: %w", err)
+	}
+	for _, amount := range paidOut {
+		balanceFor(amount.Currency).PaidOut = amount
+	}
+
+	balances := make([]domain.SellerBalance, 0, len(currencies))
+	for _, currency := range currencies {
+		balances = append(balances, *byCurrency[currency])
+	}
+	return balances, nil
+}
@@ -0,0 +1,181 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ecommerce/pkg/broker"
+	"ecommerce/pkg/events"
+	"ecommerce/pkg/inbox"
+	"ecommerce/pkg/logger"
+	"ecommerce/pkg/money"
+	"ecommerce/services/payment/internal/domain"
+	"ecommerce/services/payment/internal/service"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultSettlementHold applies to deliveries reported without a return
+// window; it matches the order service's default window.
+const defaultSettlementHold = 7 * 24 * time.Hour
+
+type OrderConsumer struct {
+	rabbitMQ      *broker.RabbitMQClient
+	paymentSvc    service.PaymentService
+	settlementSvc service.SettlementService
+	inbox         *inbox.Inbox
+}
+
+func NewOrderConsumer(rabbitMQ *broker.RabbitMQClient, paymentSvc service.PaymentService, settlementSvc service.SettlementService, inbox *inbox.Inbox) *OrderConsumer {
+	return &OrderConsumer{rabbitMQ: rabbitMQ, paymentSvc: paymentSvc, settlementSvc: settlementSvc, inbox: inbox}
+}
+
+func (c *OrderConsumer) StartListening(ctx context.Context) error {
+	consumer := broker.NewConsumer(c.rabbitMQ, broker.ConsumerConfig{
+		Exchange: events.OrderEventsExchange,
+		Queue:    "payment_service_order_queue",
+	})
+
+	broker.Handle(consumer, events.OrderCancelledKey, c.processCancellation)
+	broker.Handle(consumer, events.FulfillmentRejectedKey, c.processRejection)
+	broker.Handle(consumer, events.FulfillmentDeliveredKey, c.processDelivery)
+	broker.Handle(consumer, events.ReturnReceivedKey, c.processReturn)
+
+	logger.Info("worker: Payment Service is now listening for order events...")
+
+	err := consumer.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("worker: failed to consume order events: %w", err)
+	}
+
+	logger.Info("worker: Shutting down order consumer gracefully...")
+	return nil
+}
+
+func (c *OrderConsumer) processCancellation(ctx context.Context, msg broker.Message, payload events.OrderCancelled) error {
+	if payload.OrderID == "" {
+		return broker.Permanent(errors.New("worker: order cancelled event has no order id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		return c.paymentSvc.WithTx(tx).RefundOrderPayment(ctx, payload.OrderID, payload.Reason)
+	})
+	if err != nil {
+		return fmt.Errorf("worker: failed to refund cancelled order %s: %w", payload.OrderID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed order cancellation", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: processed order cancellation", zap.String("order_id", payload.OrderID))
+	return nil
+}
+
+// processRejection refunds the lines a seller turned down; the rest of the
+// order goes ahead.
+func (c *OrderConsumer) processRejection(ctx context.Context, msg broker.Message, payload events.FulfillmentStatusChanged) error {
+	if payload.FulfillmentID == "" {
+		return broker.Permanent(errors.New("worker: fulfillment rejected event has no fulfillment id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		_, innerErr := c.refund(ctx, tx, payload.OrderID, payload.Amount, payload.Reason, payload.FulfillmentID)
+		return innerErr
+	})
+	if err != nil {
+		return fmt.Errorf("worker: failed to refund rejected fulfillment %s: %w", payload.FulfillmentID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed fulfillment rejection", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: refunded rejected fulfillment", zap.String("fulfillment_id", payload.FulfillmentID), zap.String("amount", payload.Amount.String()))
+	return nil
+}
+
+// refund pays back part of an order. A zero amount is skipped rather than
+// refunding the whole payment, and refunds the payment cannot cover are logged
+// and dropped since redelivery would not change the outcome. Either way the
+// refund is nil.
+func (c *OrderConsumer) refund(ctx context.Context, tx *gorm.DB, orderID string, amount money.Money, reason string, idempotencyKey string) (*domain.Refund, error) {
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	refund, err := c.paymentSvc.WithTx(tx).RefundPayment(ctx, orderID, amount, reason, idempotencyKey)
+	if errors.Is(err, domain.ErrPaymentNotFound) || errors.Is(err, domain.ErrRefundExceedsPayment) {
+		logger.Error("worker: cannot refund order", zap.String("order_id", orderID), zap.String("amount", amount.String()), zap.Error(err))
+		return nil, nil
+	}
+	return refund, err
+}
+
+// processDelivery records what the seller earned on a delivered fulfillment,
+// to be settled once its return window closes.
+func (c *OrderConsumer) processDelivery(ctx context.Context, msg broker.Message, payload events.FulfillmentStatusChanged) error {
+	if payload.FulfillmentID == "" || payload.SellerID == "" {
+		return broker.Permanent(errors.New("worker: fulfillment delivered event has no fulfillment or seller id"))
+	}
+	// The order service snapshots the commission at checkout and is the only
+	// place it is decided, so a delivery without one is not guessed at.
+	if payload.Commission == nil {
+		return broker.Permanent(errors.New("worker: fulfillment delivered event has no commission"))
+	}
+
+	settleAfter := payload.OccurredAt.Add(defaultSettlementHold)
+	if payload.ReturnWindowClosesAt != nil {
+		settleAfter = *payload.ReturnWindowClosesAt
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		return c.settlementSvc.WithTx(tx).RecordDelivery(ctx, service.Delivery{
+			FulfillmentID: payload.FulfillmentID,
+			OrderID:       payload.OrderID,
+			SellerID:      payload.SellerID,
+			Sales:         payload.Amount,
+			Commission:    *payload.Commission,
+			DeliveredAt:   payload.OccurredAt,
+			SettleAfter:   settleAfter,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("worker: failed to record delivered fulfillment %s: %w", payload.FulfillmentID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed delivery", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: recorded seller earning", zap.String("fulfillment_id", payload.FulfillmentID), zap.Time("settle_after", settleAfter))
+	return nil
+}
+
+// processReturn refunds the returned units once the seller has them back.
+func (c *OrderConsumer) processReturn(ctx context.Context, msg broker.Message, payload events.ReturnStatusChanged) error {
+	if payload.ReturnID == "" {
+		return broker.Permanent(errors.New("worker: return received event has no return id"))
+	}
+
+	processed, err := c.inbox.Process(ctx, msg.ID, func(tx *gorm.DB) error {
+		refund, innerErr := c.refund(ctx, tx, payload.OrderID, payload.Amount, "returned by buyer", payload.ReturnID)
+		if innerErr != nil || refund == nil {
+			return innerErr
+		}
+		return c.settlementSvc.WithTx(tx).ApplyReturnRefund(ctx, payload.FulfillmentID, payload.ReturnID, refund)
+	})
+	if err != nil {
+		return fmt.Errorf("worker: failed to refund return %s: %w", payload.ReturnID, err)
+	}
+
+	if !processed {
+		logger.Info("worker: skipping already processed return", zap.String("message_id", msg.ID))
+		return nil
+	}
+	logger.Info("worker: refunded return", zap.String("return_id", payload.ReturnID), zap.String("amount", payload.Amount.String()))
+	return nil
+}
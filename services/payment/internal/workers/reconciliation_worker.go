@@ -0,0 +1,53 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/payment/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type ReconciliationWorker struct {
+	reconciliationSvc service.ReconciliationService
+	interval          time.Duration
+	minAge            time.Duration
+}
+
+func NewReconciliationWorker(reconciliationSvc service.ReconciliationService, interval time.Duration, minAge time.Duration) *ReconciliationWorker {
+	return &ReconciliationWorker{reconciliationSvc: reconciliationSvc, interval: interval, minAge: minAge}
+}
+
+func (w *ReconciliationWorker) StartReconciliationWorker(ctx context.Context) {
+	logger.Info("worker: Payment reconciliation worker started",
+		zap.Duration("interval", w.interval),
+		zap.Duration("min_age", w.minAge),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Payment reconciliation worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			report, err := w.reconciliationSvc.ReconcilePendingPayments(ctx, w.minAge)
+			if err != nil {
+				logger.Error("worker: payment reconciliation failed", zap.Error(err))
+				continue
+			}
+			if len(report.Mismatches) > 0 {
+				logger.Info("worker: payment reconciliation found mismatches",
+					zap.String("report_id", report.ID.String()),
+					zap.Int("checked", report.Checked),
+					zap.Int("repaired", report.Repaired),
+					zap.Int("failed", report.Failed),
+				)
+			}
+		}
+	}
+}
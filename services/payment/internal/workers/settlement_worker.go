@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"ecommerce/pkg/logger"
+	"ecommerce/services/payment/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type SettlementWorker struct {
+	settlementSvc service.SettlementService
+	interval      time.Duration
+}
+
+func NewSettlementWorker(settlementSvc service.SettlementService, interval time.Duration) *SettlementWorker {
+	return &SettlementWorker{settlementSvc: settlementSvc, interval: interval}
+}
+
+func (w *SettlementWorker) StartSettlementWorker(ctx context.Context) {
+	logger.Info("worker: Seller settlement worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("worker: Seller settlement worker shutting down gracefully")
+			return
+		case <-ticker.C:
+			batch, err := w.settlementSvc.RunSettlement(ctx, time.Now())
+			if err != nil {
+				logger.Error("worker: seller settlement failed", zap.Error(err))
+				continue
+			}
+			if batch != nil {
+				logger.Info("worker: settled seller payouts",
+					zap.String("batch_id", batch.PublicID),
+					zap.Int("payouts", len(batch.Payouts)),
+				)
+			}
+		}
+	}
+}